@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/bench"
+	"github.com/Priyans-hu/sreq/internal/client"
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/internal/history"
+	"github.com/Priyans-hu/sreq/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench <METHOD> <path>",
+	Short: "Load test a service endpoint",
+	Long: `Send a request repeatedly from concurrent workers and report
+throughput, status codes and latency percentiles.
+
+Credentials are resolved once and connections are reused. The run stops
+after --requests requests or when --duration has passed, whichever comes
+first; Ctrl-C stops early and reports what completed. Requests are not
+retried and not saved to history.
+
+Protected environments (protected_envs, prod and production by default)
+are refused. Note that -c selects a context, as with every command; use
+--concurrency for the number of workers.
+
+Examples:
+  sreq bench GET /api/v1/users -s auth-service -e dev --concurrency 20 -n 5000
+  sreq bench GET /health -s billing-service -e staging --duration 30s --rate 100
+  sreq bench POST /api/v1/search -s search -e dev -d @query.json -o json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBench,
+}
+
+var (
+	benchConcurrency int
+	benchRequests    int
+	benchDuration    time.Duration
+	benchRate        float64
+	benchData        string
+	benchHeaders     []string
+	benchOutput      string
+)
+
+// benchDefaultRequests is used when neither --requests nor --duration is set
+const benchDefaultRequests = 200
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().IntVar(&benchConcurrency, "concurrency", 10, "Number of concurrent workers")
+	benchCmd.Flags().IntVarP(&benchRequests, "requests", "n", 0, fmt.Sprintf("Total requests to send (default %d without --duration)", benchDefaultRequests))
+	benchCmd.Flags().DurationVar(&benchDuration, "duration", 0, "Send requests for this long")
+	benchCmd.Flags().Float64Var(&benchRate, "rate", 0, "Cap requests per second across all workers (0: no cap)")
+	benchCmd.Flags().StringVarP(&benchData, "data", "d", "", "Request body (@filename or @- for stdin, read once)")
+	benchCmd.Flags().StringArrayVarP(&benchHeaders, "header", "H", nil, "Add header (repeatable)")
+	benchCmd.Flags().StringArrayVarP(&queryParams, "query", "q", nil, "Add query parameter key=value, URL-encoded (repeatable)")
+	benchCmd.Flags().StringArrayVarP(&pathParams, "param", "P", nil, "Fill a {name} path parameter with name=value (repeatable)")
+	benchCmd.Flags().StringVarP(&benchOutput, "output", "o", "text", "Output format (text/json)")
+	benchCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for each request")
+	benchCmd.Flags().StringVar(&httpProtocol, "protocol", "", "Force an HTTP version: http1.1, h2, h2c or h3 (default: negotiated)")
+	benchCmd.Flags().BoolVar(&offlineMode, "offline", false, "Use cached credentials only (no provider calls)")
+	benchCmd.Flags().BoolVar(&noCache, "no-cache", false, "Skip cache and fetch fresh credentials")
+	benchCmd.Flags().BoolVarP(&insecure, "insecure", "k", false, "Skip TLS certificate verification")
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	method := strings.ToUpper(args[0])
+	path := args[1]
+
+	switch method {
+	case "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS":
+	default:
+		return sreerrors.InvalidMethod(method)
+	}
+	if serviceName == "" {
+		return sreerrors.MissingRequiredFlag("service")
+	}
+	if benchOutput != "text" && benchOutput != "json" {
+		return fmt.Errorf("unknown output format %q (want text or json)", benchOutput)
+	}
+
+	opts := bench.Options{
+		Concurrency: benchConcurrency,
+		Requests:    benchRequests,
+		Duration:    benchDuration,
+		Rate:        benchRate,
+	}
+	if opts.Requests == 0 && opts.Duration == 0 {
+		opts.Requests = benchDefaultRequests
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	cfg, ctxName, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.IsProtectedEnv(environment) {
+		return sreerrors.BenchNotAllowed(environment)
+	}
+
+	headers, err := parseHeaders(benchHeaders)
+	if err != nil {
+		return err
+	}
+
+	// The body is read once and sent with every request
+	body := benchData
+	if strings.HasPrefix(body, "@") {
+		var data []byte
+		if body == "@-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(body[1:])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read body: %w", err)
+		}
+		body = string(data)
+	}
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, "Request Details:")
+		if ctxName != "" {
+			fmt.Fprintf(os.Stderr, "  Context:     %s\n", ctxName)
+		}
+		fmt.Fprintf(os.Stderr, "  Service:     %s\n", serviceName)
+		fmt.Fprintf(os.Stderr, "  Environment: %s\n", environment)
+		fmt.Fprintf(os.Stderr, "  Method:      %s\n", method)
+		fmt.Fprintf(os.Stderr, "  Path:        %s\n", path)
+		if body != "" {
+			fmt.Fprintf(os.Stderr, "  Body:        %s\n", truncate(body, 100))
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+
+	if dryRun {
+		fmt.Println("[DRY RUN] Would execute:")
+		fmt.Printf("  %s <base-url>%s\n", method, path)
+		fmt.Printf("  %s\n", describeBench(opts))
+		fmt.Println()
+		fmt.Println("Credentials would be resolved from configured providers.")
+		return nil
+	}
+
+	// Ctrl-C stops the run and reports what completed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var timing history.Timing
+	target, err := resolveTarget(ctx, cfg, &timing)
+	if err != nil {
+		return err
+	}
+	creds, red := target.creds, target.red
+	if creds.BaseURL == "" {
+		return sreerrors.BaseURLMissing(serviceName, environment)
+	}
+
+	contextVars := map[string]string{
+		"service": serviceName,
+		"env":     environment,
+		"region":  region,
+		"project": project,
+		"app":     app,
+	}
+	vars, err := pathVars(creds.Custom, contextVars, pathParams)
+	if err != nil {
+		return err
+	}
+	redactPathValues(red, path, creds.Custom, vars)
+	if path, err = renderPath(path, vars); err != nil {
+		return err
+	}
+	if path, err = appendQuery(path, queryParams); err != nil {
+		return err
+	}
+
+	clientOpts, err := target.clientOptions(cfg)
+	if err != nil {
+		return err
+	}
+	protocol, err := protocolFor(cfg, serviceName, environment)
+	if err != nil {
+		return err
+	}
+	// Retries would hide failures and skew latency; per-request verbose
+	// output would drown the report
+	clientOpts = append(clientOpts,
+		client.WithProtocol(protocol),
+		client.WithRetry(client.RetryPolicy{}),
+		client.WithVerbose(false),
+		client.WithIdleConns(opts.Concurrency),
+	)
+	httpClient := client.New(clientOpts...)
+
+	req := &types.Request{
+		Method:      method,
+		Path:        path,
+		Service:     serviceName,
+		Environment: environment,
+		Body:        body,
+		Headers:     headers,
+	}
+
+	if benchOutput == "text" {
+		fmt.Fprintf(os.Stderr, "Benchmarking %s %s: %s\n\n", method, red.URL(creds.BaseURL+path), describeBench(opts))
+	}
+	report, err := bench.Run(ctx, opts, func(ctx context.Context) (int, error) {
+		resp, err := httpClient.Do(ctx, req, creds)
+		if err != nil {
+			return 0, err
+		}
+		return resp.StatusCode, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Error messages can carry URLs with tokens in the query
+	if len(report.ErrorCounts) > 0 {
+		counts := make(map[string]int, len(report.ErrorCounts))
+		for msg, n := range report.ErrorCounts {
+			counts[red.URL(msg)] += n
+		}
+		report.ErrorCounts = counts
+	}
+
+	if benchOutput == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	report.WriteText(os.Stdout)
+	return nil
+}
+
+// describeBench summarises the options for humans
+func describeBench(opts bench.Options) string {
+	var parts []string
+	if opts.Requests > 0 {
+		parts = append(parts, fmt.Sprintf("%d requests", opts.Requests))
+	}
+	if opts.Duration > 0 {
+		parts = append(parts, fmt.Sprintf("for %s", opts.Duration))
+	}
+	parts = append(parts, fmt.Sprintf("%d workers", opts.Concurrency))
+	if opts.Rate > 0 {
+		parts = append(parts, fmt.Sprintf("at most %g req/s", opts.Rate))
+	}
+	return strings.Join(parts, ", ")
+}
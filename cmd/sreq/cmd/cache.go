@@ -120,9 +120,16 @@ func runCacheStatus(cmd *cobra.Command, args []string) error {
 			if e.Expired {
 				expiredStr = " (EXPIRED)"
 			}
-			fmt.Printf("  %s/%s - cached %s, expires %s%s\n",
+			kind := ""
+			if e.Token {
+				kind = " [oauth2 token]"
+			} else if e.Cookies > 0 {
+				kind = fmt.Sprintf(" [%d cookies]", e.Cookies)
+			}
+			fmt.Printf("  %s/%s%s - cached %s, expires %s%s\n",
 				e.Service,
 				e.Env,
+				kind,
 				e.CachedAt.Format("15:04:05"),
 				e.ExpiresAt.Format("15:04:05"),
 				expiredStr,
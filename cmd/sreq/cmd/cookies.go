@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Priyans-hu/sreq/internal/cache"
+	"github.com/Priyans-hu/sreq/internal/config"
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/spf13/cobra"
+)
+
+var cookiesCmd = &cobra.Command{
+	Use:   "cookies",
+	Short: "Manage stored cookie jars",
+	Long: `Manage the cookie jars kept for services with cookies enabled.
+
+Jars are stored per service and environment in the encrypted cache.
+Enable them with 'cookies: true' in a service config or --cookies on run.
+
+Examples:
+  sreq cookies list                       # List all stored cookies
+  sreq cookies list -s auth-service -e dev
+  sreq cookies clear -s auth-service      # Clear one service's jars
+  sreq cookies clear                      # Clear all jars`,
+}
+
+var cookiesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored cookies",
+	Long: `List stored cookies, optionally for one service (-s) and environment (-e).
+
+Cookie values are masked unless --reveal-secrets is set.`,
+	RunE: runCookiesList,
+}
+
+var cookiesClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear stored cookies",
+	Long:  `Clear stored cookie jars, optionally for one service (-s) and environment (-e).`,
+	RunE:  runCookiesClear,
+}
+
+func init() {
+	rootCmd.AddCommand(cookiesCmd)
+	cookiesCmd.AddCommand(cookiesListCmd)
+	cookiesCmd.AddCommand(cookiesClearCmd)
+}
+
+// cookieJars returns the cache and the service/env pairs holding cookies
+// that match the -s and -e flags
+func cookieJars() (*cache.Cache, []cache.EntryInfo, error) {
+	if cache.IsDisabled() {
+		return nil, nil, fmt.Errorf("cache is disabled (SREQ_NO_CACHE=1 or CI environment detected)")
+	}
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !cache.KeyExists(configDir) {
+		return nil, nil, nil
+	}
+
+	c, err := cache.New(cache.Config{ConfigDir: configDir})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	status, err := c.Status()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cache status: %w", err)
+	}
+
+	var jars []cache.EntryInfo
+	for _, e := range status.Entries {
+		if e.Cookies == 0 {
+			continue
+		}
+		if serviceName != "" && e.Service != serviceName || environment != "" && e.Env != environment {
+			continue
+		}
+		jars = append(jars, e)
+	}
+	return c, jars, nil
+}
+
+func runCookiesList(cmd *cobra.Command, args []string) error {
+	c, jars, err := cookieJars()
+	if err != nil {
+		return err
+	}
+	if len(jars) == 0 {
+		fmt.Println("No stored cookies")
+		return nil
+	}
+
+	for i, jar := range jars {
+		cookies, err := c.GetCookies(jar.Service, jar.Env)
+		if err != nil {
+			return err
+		}
+		if len(cookies) == 0 {
+			continue
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s/%s:\n", jar.Service, jar.Env)
+		for _, cookie := range cookies {
+			var attrs []string
+			if cookie.HostOnly {
+				attrs = append(attrs, "host-only")
+			}
+			if cookie.Secure {
+				attrs = append(attrs, "secure")
+			}
+			if cookie.HTTPOnly {
+				attrs = append(attrs, "httponly")
+			}
+			expires := "session"
+			if !cookie.Expires.IsZero() {
+				expires = cookie.Expires.Local().Format("2006-01-02 15:04:05")
+			}
+			attrs = append(attrs, "expires "+expires)
+
+			value := cookie.Value
+			if !secretsRevealed() {
+				value = redact.Mask
+			}
+			fmt.Printf("  %s=%s  %s%s  (%s)\n", cookie.Name, value, cookie.Domain, cookie.Path, strings.Join(attrs, ", "))
+		}
+	}
+	return nil
+}
+
+func runCookiesClear(cmd *cobra.Command, args []string) error {
+	c, jars, err := cookieJars()
+	if err != nil {
+		return err
+	}
+
+	for _, jar := range jars {
+		if err := c.DeleteCookies(jar.Service, jar.Env); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Cleared %d cookie jar(s)\n", len(jars))
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// parseFormField parses a -F spec: "name=value", or "name=@path" with
+// optional ";type=..." and ";filename=..." attributes for file uploads
+func parseFormField(spec string) (types.FormField, error) {
+	name, value, ok := strings.Cut(spec, "=")
+	if !ok || name == "" {
+		return types.FormField{}, fmt.Errorf("invalid form field: %s (expected 'name=value' or 'name=@file')", spec)
+	}
+
+	if !strings.HasPrefix(value, "@") {
+		return types.FormField{Name: name, Value: value}, nil
+	}
+
+	parts := strings.Split(value[1:], ";")
+	field := types.FormField{Name: name, File: parts[0]}
+	if field.File == "" {
+		return types.FormField{}, fmt.Errorf("invalid form field: %s (missing file path)", spec)
+	}
+	for _, attr := range parts[1:] {
+		key, val, _ := strings.Cut(strings.TrimSpace(attr), "=")
+		switch key {
+		case "type":
+			field.ContentType = val
+		case "filename":
+			field.Filename = val
+		default:
+			return types.FormField{}, fmt.Errorf("invalid form field: %s (unknown attribute '%s')", spec, key)
+		}
+	}
+	return field, nil
+}
+
+// urlencodedBody encodes --form name=value pairs, keeping their order
+func urlencodedBody(specs []string) (string, error) {
+	pairs := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		name, value, ok := strings.Cut(spec, "=")
+		if !ok || name == "" {
+			return "", fmt.Errorf("invalid form value: %s (expected 'name=value')", spec)
+		}
+		pairs = append(pairs, url.QueryEscape(name)+"="+url.QueryEscape(value))
+	}
+	return strings.Join(pairs, "&"), nil
+}
+
+// formSummary describes multipart fields for verbose output and history
+func formSummary(fields []types.FormField) []string {
+	summary := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f.File == "" {
+			summary = append(summary, f.Name+"="+f.Value)
+			continue
+		}
+		s := f.Name + "=@" + f.File
+		if f.ContentType != "" {
+			s += ";type=" + f.ContentType
+		}
+		if f.Filename != "" {
+			s += ";filename=" + f.Filename
+		}
+		summary = append(summary, s)
+	}
+	return summary
+}
+
+// hasHeader reports whether headers sets name, ignoring case
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
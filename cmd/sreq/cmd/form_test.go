@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestParseFormField(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    types.FormField
+		wantErr bool
+	}{
+		{spec: "title=Q3 report", want: types.FormField{Name: "title", Value: "Q3 report"}},
+		{spec: "note=a;b", want: types.FormField{Name: "note", Value: "a;b"}},
+		{spec: "file=@./q3.csv", want: types.FormField{Name: "file", File: "./q3.csv"}},
+		{spec: "file=@q3.csv;type=text/csv;filename=report.csv", want: types.FormField{Name: "file", File: "q3.csv", ContentType: "text/csv", Filename: "report.csv"}},
+		{spec: "novalue", wantErr: true},
+		{spec: "=value", wantErr: true},
+		{spec: "file=@", wantErr: true},
+		{spec: "file=@q3.csv;size=1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseFormField(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseFormField(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseFormField(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestUrlencodedBody(t *testing.T) {
+	got, err := urlencodedBody([]string{"q=a b&c", "page=2", "empty="})
+	if err != nil {
+		t.Fatalf("urlencodedBody() error = %v", err)
+	}
+	if want := "q=a+b%26c&page=2&empty="; got != want {
+		t.Errorf("urlencodedBody() = %q, want %q", got, want)
+	}
+
+	if _, err := urlencodedBody([]string{"novalue"}); err == nil {
+		t.Error("expected error for a value without '='")
+	}
+}
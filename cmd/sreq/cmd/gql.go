@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/client"
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/internal/graphql"
+	"github.com/Priyans-hu/sreq/internal/history"
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var gqlCmd = &cobra.Command{
+	Use:   "gql [query-file]",
+	Short: "Run a GraphQL query or mutation",
+	Long: `Run a GraphQL query or mutation against a service.
+
+Credentials are resolved exactly like 'run'. The query is read from a file
+(- for stdin) and POSTed to the service's GraphQL endpoint with its
+variables. The response's data is printed to stdout and any errors to
+stderr; errors make sreq exit non-zero.
+
+The file can also be given with --file. A file named like a subcommand,
+such as schema, must be passed as ./schema or with --file.
+
+--var name=value is converted to the variable's declared type, so id=123
+is sent as a number for an Int and as a string for an ID. Use
+--var name:=json for raw JSON values.
+
+Examples:
+  sreq gql order.graphql -s orders --var id=123
+  sreq gql create.graphql -s orders --vars @vars.json --var dryRun=true
+  sreq gql ops.graphql -s orders --operation GetOrder --var id=o-1
+  sreq gql --file schema -s orders
+  sreq gql schema -s orders -e staging > schema.graphql`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGQL,
+}
+
+var gqlSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the introspected GraphQL schema",
+	Long: `Print the service's schema, fetched with an introspection query, in
+the GraphQL schema definition language or as the raw introspection JSON.`,
+	Args: cobra.NoArgs,
+	RunE: runGQLSchema,
+}
+
+var (
+	gqlFile         string
+	gqlVars         []string
+	gqlVarsJSON     string
+	gqlOperation    string
+	gqlOutput       string
+	gqlEndpoint     string
+	gqlHeaders      []string
+	gqlSchemaFormat string
+)
+
+func init() {
+	rootCmd.AddCommand(gqlCmd)
+	gqlCmd.AddCommand(gqlSchemaCmd)
+
+	gqlCmd.Flags().StringVarP(&gqlFile, "file", "f", "", "Read the query from a file (- for stdin)")
+	gqlCmd.Flags().StringArrayVar(&gqlVars, "var", nil, "Set a variable name=value, or name:=json for raw JSON (repeatable)")
+	gqlCmd.Flags().StringVar(&gqlVarsJSON, "vars", "", "Variables as a JSON object (@filename reads a file)")
+	gqlCmd.Flags().StringVar(&gqlOperation, "operation", "", "Operation to run when the document has several")
+	gqlCmd.Flags().StringVarP(&gqlOutput, "output", "o", "json", "Output format (json/raw)")
+
+	flags := gqlCmd.PersistentFlags()
+	flags.StringVar(&gqlEndpoint, "endpoint", "/graphql", "Path of the GraphQL endpoint")
+	flags.StringArrayVarP(&gqlHeaders, "header", "H", nil, "Add header (repeatable)")
+	flags.DurationVar(&timeout, "timeout", 30*time.Second, "Request timeout")
+	flags.BoolVar(&offlineMode, "offline", false, "Use cached credentials only (no provider calls)")
+	flags.BoolVar(&noCache, "no-cache", false, "Skip cache and fetch fresh credentials")
+	flags.BoolVarP(&insecure, "insecure", "k", false, "Skip TLS certificate verification (refused in protected environments)")
+
+	gqlSchemaCmd.Flags().StringVar(&gqlSchemaFormat, "format", "sdl", "Schema format (sdl/json)")
+}
+
+func runGQL(cmd *cobra.Command, args []string) error {
+	if gqlOutput != "json" && gqlOutput != "raw" {
+		return fmt.Errorf("unknown output format: %s", gqlOutput)
+	}
+
+	source := gqlFile
+	if len(args) == 1 {
+		if source != "" {
+			return fmt.Errorf("pass the query file as an argument or with --file, not both")
+		}
+		source = args[0]
+	}
+	if source == "" {
+		return fmt.Errorf("no query file (pass it as an argument or with --file)")
+	}
+	query, err := readQuery(source)
+	if err != nil {
+		return err
+	}
+
+	vars := map[string]any{}
+	if gqlVarsJSON != "" {
+		data := []byte(gqlVarsJSON)
+		if strings.HasPrefix(gqlVarsJSON, "@") {
+			if data, err = os.ReadFile(gqlVarsJSON[1:]); err != nil {
+				return fmt.Errorf("failed to read variables file: %w", err)
+			}
+		}
+		if err := json.Unmarshal(data, &vars); err != nil {
+			return fmt.Errorf("--vars must be a JSON object: %w", err)
+		}
+	}
+	flagVars, err := graphql.ParseVariables(gqlVars, graphql.VariableTypes(query))
+	if err != nil {
+		return err
+	}
+	for name, v := range flagVars {
+		vars[name] = v
+	}
+
+	gqlReq := &graphql.Request{Query: query, OperationName: gqlOperation}
+	if len(vars) > 0 {
+		gqlReq.Variables = vars
+	}
+
+	resp, body, red, err := sendGQL(gqlReq)
+	if err != nil || resp == nil {
+		return err
+	}
+
+	if gqlOutput == "raw" {
+		fmt.Println(red.String(string(body)))
+	} else if resp.HasData() {
+		printJSON(resp.Data, red)
+	}
+	if len(resp.Errors) > 0 {
+		if gqlOutput == "json" {
+			fmt.Fprintln(os.Stderr, "Errors:")
+			for _, e := range resp.Errors {
+				fmt.Fprintf(os.Stderr, "  - %s\n", red.String(e.String()))
+			}
+		}
+		// The errors are already printed; usage would only bury them
+		if cmd != nil {
+			cmd.SilenceUsage = true
+		}
+		return fmt.Errorf("GraphQL response has %d error(s)", len(resp.Errors))
+	}
+	return nil
+}
+
+func runGQLSchema(cmd *cobra.Command, args []string) error {
+	if gqlSchemaFormat != "sdl" && gqlSchemaFormat != "json" {
+		return fmt.Errorf("unknown schema format: %s (want sdl or json)", gqlSchemaFormat)
+	}
+
+	resp, _, red, err := sendGQL(&graphql.Request{Query: graphql.IntrospectionQuery, OperationName: "IntrospectionQuery"})
+	if err != nil || resp == nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		for _, e := range resp.Errors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", red.String(e.String()))
+		}
+		return fmt.Errorf("introspection failed with %d error(s); it may be disabled for %s/%s", len(resp.Errors), serviceName, environment)
+	}
+
+	if gqlSchemaFormat == "json" {
+		printJSON(resp.Data, red)
+		return nil
+	}
+	schema, err := graphql.ParseSchema(resp.Data)
+	if err != nil {
+		return err
+	}
+	fmt.Print(schema.SDL())
+	return nil
+}
+
+// sendGQL POSTs a GraphQL request to the -s service and saves it to
+// history. It returns the parsed response and the raw body; both are nil
+// for a dry run.
+func sendGQL(gqlReq *graphql.Request) (*graphql.Response, []byte, *redact.Redactor, error) {
+	startTime := time.Now()
+	if serviceName == "" {
+		return nil, nil, nil, sreerrors.MissingRequiredFlag("service")
+	}
+
+	cfg, ctxName, err := loadConfig()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if insecure && cfg.IsProtectedEnv(environment) {
+		return nil, nil, nil, sreerrors.InsecureTLSNotAllowed(environment)
+	}
+
+	headers, err := parseHeaders(gqlHeaders)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !hasHeader(headers, "Content-Type") {
+		headers["Content-Type"] = "application/json"
+	}
+	if !hasHeader(headers, "Accept") {
+		headers["Accept"] = "application/graphql-response+json, application/json"
+	}
+
+	body, err := json.Marshal(gqlReq)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if verbose {
+		fmt.Println("Request Details:")
+		if ctxName != "" {
+			fmt.Printf("  Context:     %s\n", ctxName)
+		}
+		fmt.Printf("  Service:     %s\n", serviceName)
+		fmt.Printf("  Environment: %s\n", environment)
+		fmt.Printf("  Endpoint:    %s\n", gqlEndpoint)
+		if gqlReq.OperationName != "" {
+			fmt.Printf("  Operation:   %s\n", gqlReq.OperationName)
+		}
+		if len(gqlReq.Variables) > 0 {
+			v, _ := json.Marshal(gqlReq.Variables)
+			fmt.Printf("  Variables:   %s\n", truncate(string(v), 100))
+		}
+		fmt.Println()
+	}
+
+	if dryRun {
+		fmt.Println("[DRY RUN] Would execute:")
+		fmt.Printf("  POST <base-url>%s\n", gqlEndpoint)
+		fmt.Printf("  %s\n", body)
+		fmt.Println()
+		fmt.Println("Credentials would be resolved from configured providers.")
+		return nil, nil, nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var timing history.Timing
+	target, err := resolveTarget(ctx, cfg, &timing)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	creds, red := target.creds, target.red
+	if creds.BaseURL == "" {
+		return nil, nil, nil, sreerrors.BaseURLMissing(serviceName, environment)
+	}
+
+	clientOpts, err := target.clientOptions(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	protocol, err := protocolFor(cfg, serviceName, environment)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	clientOpts = append(clientOpts, client.WithProtocol(protocol))
+
+	req := &types.Request{
+		Method:      "POST",
+		Path:        gqlEndpoint,
+		Service:     serviceName,
+		Environment: environment,
+		Body:        string(body),
+		Headers:     headers,
+	}
+	resp, err := client.New(clientOpts...).Do(ctx, req, creds)
+	duration := time.Since(startTime).Milliseconds()
+	if resp != nil && resp.Timing != nil {
+		timing.SetRequest(resp.Timing)
+	}
+
+	if os.Getenv("SREQ_NO_HISTORY") != "1" {
+		histRed := redact.ForCredentials(creds)
+		histRed.Add(red.Secrets()...)
+		saveHistory(req, creds.BaseURL, resp, err, duration, timing, histRed)
+	}
+
+	if err != nil {
+		return nil, nil, nil, red.Error(sreerrors.RequestFailed(red.URL(creds.BaseURL+gqlEndpoint), err))
+	}
+
+	gqlResp, err := graphql.ParseResponse(resp.Body)
+	if err != nil {
+		// Gateways and auth proxies answer with their own error pages
+		return nil, nil, nil, red.Error(fmt.Errorf("%s from %s is not a GraphQL response (%v): %s",
+			resp.Status, gqlEndpoint, err, truncate(string(resp.Body), 200)))
+	}
+	return gqlResp, resp.Body, red, nil
+}
+
+// readQuery reads a query document from a file, or stdin for "-"
+func readQuery(arg string) (string, error) {
+	if arg == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read query from stdin: %w", err)
+		}
+		return string(data), nil
+	}
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return "", fmt.Errorf("failed to read query file: %w", err)
+	}
+	return string(data), nil
+}
+
+// printJSON pretty-prints JSON, keeping its key order
+func printJSON(data []byte, red *redact.Redactor) {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, data, "", "  "); err == nil {
+		data = pretty.Bytes()
+	}
+	fmt.Println(red.String(string(data)))
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunGQL_QuerySource(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "schema")
+
+	tests := []struct {
+		name string
+		file string
+		args []string
+		want string
+	}{
+		{"none", "", nil, "no query file"},
+		{"both", missing, []string{missing}, "not both"},
+		{"file flag", missing, nil, "failed to read query file"},
+		{"argument", "", []string{missing}, "failed to read query file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gqlFile = tt.file
+			defer func() { gqlFile = "" }()
+
+			err := runGQL(nil, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("runGQL() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGQLCmd_FileNamedSchema(t *testing.T) {
+	// A positional "schema" is the subcommand; --file schema is a query file
+	cmd, _, err := rootCmd.Find([]string{"gql", "schema"})
+	if err != nil || cmd != gqlSchemaCmd {
+		t.Errorf("Find(gql schema) = %v, %v, want the schema subcommand", cmd.Name(), err)
+	}
+	cmd, _, err = rootCmd.Find([]string{"gql", "--file", "schema"})
+	if err != nil || cmd != gqlCmd {
+		t.Errorf("Find(gql --file schema) = %v, %v, want gql", cmd.Name(), err)
+	}
+}
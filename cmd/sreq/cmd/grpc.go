@@ -0,0 +1,415 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/client"
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/internal/grpc"
+	"github.com/Priyans-hu/sreq/internal/history"
+	"github.com/Priyans-hu/sreq/internal/protobuf"
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var grpcCmd = &cobra.Command{
+	Use:   "grpc <package.Service/Method>",
+	Short: "Call a gRPC method",
+	Long: `Call a gRPC method with a JSON request message.
+
+Credentials are resolved exactly like 'run' and sent as metadata. Message
+types come from server reflection, or from local .proto files (--proto)
+or a descriptor set (--protoset). Unary and server-streaming methods are
+supported; each response message is printed as JSON.
+
+Examples:
+  sreq grpc list -s orders                        # List services
+  sreq grpc list shop.v1.OrderService -s orders   # List methods
+  sreq grpc describe shop.v1.Order -s orders      # Show a message type
+  sreq grpc shop.v1.OrderService/GetOrder -s orders -d '{"id": "o-1"}'
+  sreq grpc shop.v1.OrderService/GetOrder -s orders --proto shop.proto -I ./protos -d @req.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGRPC,
+}
+
+var grpcListCmd = &cobra.Command{
+	Use:   "list [service]",
+	Short: "List gRPC services, or the methods of one",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runGRPCList,
+}
+
+var grpcDescribeCmd = &cobra.Command{
+	Use:   "describe <symbol>",
+	Short: "Describe a gRPC service, method, message or enum",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGRPCDescribe,
+}
+
+var (
+	grpcData        string
+	grpcOutput      string
+	grpcMetadata    []string
+	grpcProtos      []string
+	grpcImportPaths []string
+	grpcProtoset    string
+)
+
+func init() {
+	rootCmd.AddCommand(grpcCmd)
+	grpcCmd.AddCommand(grpcListCmd)
+	grpcCmd.AddCommand(grpcDescribeCmd)
+
+	grpcCmd.Flags().StringVarP(&grpcData, "data", "d", "{}", "Request message as JSON (@filename reads a file, @- reads stdin)")
+	grpcCmd.Flags().StringVarP(&grpcOutput, "output", "o", "json", "Output format (json/raw)")
+	grpcCmd.Flags().DurationVar(&maxTime, "max-time", 0, "Limit for the whole call (default: --timeout for unary calls, none for streams)")
+
+	flags := grpcCmd.PersistentFlags()
+	flags.StringArrayVarP(&grpcMetadata, "header", "H", nil, "Add metadata 'Key: Value' (repeatable)")
+	flags.StringArrayVar(&grpcProtos, "proto", nil, "Read message types from a .proto file instead of reflection (repeatable)")
+	flags.StringArrayVarP(&grpcImportPaths, "import-path", "I", nil, "Directory to resolve .proto imports in (repeatable, default: .)")
+	flags.StringVar(&grpcProtoset, "protoset", "", "Read message types from a FileDescriptorSet (protoc --descriptor_set_out)")
+	flags.DurationVar(&timeout, "timeout", 30*time.Second, "Request timeout")
+	flags.BoolVar(&offlineMode, "offline", false, "Use cached credentials only (no provider calls)")
+	flags.BoolVar(&noCache, "no-cache", false, "Skip cache and fetch fresh credentials")
+	flags.BoolVarP(&insecure, "insecure", "k", false, "Skip TLS certificate verification (refused in protected environments)")
+	grpcCmd.MarkFlagsMutuallyExclusive("proto", "protoset")
+}
+
+func runGRPC(cmd *cobra.Command, args []string) error {
+	startTime := time.Now()
+	svcName, methodName, ok := protobuf.SplitMethod(args[0])
+	if !ok {
+		return fmt.Errorf("invalid method %q (want package.Service/Method)", args[0])
+	}
+	method := svcName + "/" + methodName
+
+	if grpcOutput != "json" && grpcOutput != "raw" {
+		return fmt.Errorf("unknown output format: %s", grpcOutput)
+	}
+	if serviceName == "" {
+		return sreerrors.MissingRequiredFlag("service")
+	}
+
+	cfg, ctxName, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if insecure && cfg.IsProtectedEnv(environment) {
+		return sreerrors.InsecureTLSNotAllowed(environment)
+	}
+
+	metadata, err := parseHeaders(grpcMetadata)
+	if err != nil {
+		return err
+	}
+	input, err := readGRPCInput(grpcData)
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Println("Request Details:")
+		if ctxName != "" {
+			fmt.Printf("  Context:     %s\n", ctxName)
+		}
+		fmt.Printf("  Service:     %s\n", serviceName)
+		fmt.Printf("  Environment: %s\n", environment)
+		fmt.Printf("  Method:      %s\n", method)
+		if len(metadata) > 0 {
+			fmt.Println("  Metadata:")
+			hdrRed := redact.New()
+			hdrRed.SetReveal(secretsRevealed())
+			for k, v := range metadata {
+				fmt.Printf("    %s: %s\n", k, hdrRed.Header(k, v))
+			}
+		}
+		fmt.Printf("  Message:     %s\n", truncate(string(input), 100))
+		fmt.Println()
+	}
+
+	if dryRun {
+		fmt.Println("[DRY RUN] Would call:")
+		fmt.Printf("  grpc <base-url>/%s\n", method)
+		fmt.Println()
+		fmt.Println("Credentials would be resolved from configured providers.")
+		return nil
+	}
+
+	// Ctrl-C ends a stream cleanly, keeping its history entry
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var timing history.Timing
+	conn, target, err := grpcConnect(ctx, cfg, metadata, &timing)
+	if err != nil {
+		return err
+	}
+	red := target.red
+
+	reg, err := grpcRegistry(ctx, conn, method)
+	if err != nil {
+		return err
+	}
+	m, err := reg.Method(method)
+	if err != nil {
+		return err
+	}
+	if m.IsStreamingClient() {
+		return fmt.Errorf("%s is a client-streaming method, which sreq grpc does not support yet", method)
+	}
+	msg, err := reg.FromJSON(m.Input(), input)
+	if err != nil {
+		return sreerrors.InvalidGRPCMessage(method, err)
+	}
+
+	// --timeout bounds a unary call; a stream has no limit unless --max-time is set
+	limit := timeout
+	if maxTime > 0 || m.IsStreamingServer() {
+		limit = maxTime
+	}
+	if limit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limit)
+		defer cancel()
+	}
+
+	// Print each message as it arrives, keeping a sample for history
+	var sample bytes.Buffer
+	resp, err := conn.Invoke(ctx, method, msg, func(b []byte) error {
+		out, err := reg.ToJSON(m.Output(), b)
+		if err != nil {
+			return err
+		}
+		if sample.Len() < client.StreamSampleSize {
+			sample.Write(out)
+			sample.WriteByte('\n')
+		}
+		printGRPCMessage(out, grpcOutput, red)
+		return nil
+	})
+	duration := time.Since(startTime).Milliseconds()
+
+	if resp != nil {
+		if resp.Timing != nil {
+			timing.SetRequest(resp.Timing)
+		}
+		resp.Status = grpc.OK.String()
+		var st *grpc.Status
+		if errors.As(err, &st) {
+			resp.Status = strings.TrimPrefix(st.Error(), "rpc error: ")
+		}
+		resp.Body = sample.Bytes()
+		resp.Truncated = sample.Len() > client.StreamSampleSize
+		if resp.Truncated {
+			resp.Body = resp.Body[:client.StreamSampleSize]
+		}
+	}
+
+	if os.Getenv("SREQ_NO_HISTORY") != "1" {
+		req := &types.Request{
+			Method:      history.MethodGRPC,
+			Path:        "/" + method,
+			Service:     serviceName,
+			Environment: environment,
+			Body:        string(input),
+			Headers:     metadata,
+		}
+		histRed := redact.ForCredentials(target.creds)
+		histRed.Add(red.Secrets()...)
+		saveHistory(req, target.creds.BaseURL, resp, err, duration, timing, histRed)
+	}
+
+	var st *grpc.Status
+	switch {
+	case errors.As(err, &st):
+		return red.Error(err)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) && m.IsStreamingServer():
+		return fmt.Errorf("stream stopped after --max-time %s", maxTime)
+	case ctx.Err() != nil && m.IsStreamingServer():
+		return nil // Interrupted
+	case err != nil:
+		return red.Error(sreerrors.RequestFailed(red.URL(target.creds.BaseURL+"/"+method), err))
+	}
+	return nil
+}
+
+func runGRPCList(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Local descriptors need no connection
+	var conn *grpc.Conn
+	if !grpcLocalDescriptors() {
+		c, err := grpcConnectFor(ctx)
+		if err != nil {
+			return err
+		}
+		conn = c
+	}
+
+	if len(args) == 0 {
+		var services []string
+		if conn != nil {
+			var err error
+			if services, err = conn.ListServices(ctx); err != nil {
+				return sreerrors.GRPCReflectionFailed(serviceName, err)
+			}
+		} else {
+			reg, err := grpcRegistry(ctx, nil)
+			if err != nil {
+				return err
+			}
+			services = reg.Services()
+		}
+		for _, s := range services {
+			fmt.Println(s)
+		}
+		return nil
+	}
+
+	reg, err := grpcRegistry(ctx, conn, args[0])
+	if err != nil {
+		return err
+	}
+	svc, err := reg.Service(args[0])
+	if err != nil {
+		return err
+	}
+	methods := svc.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		fmt.Printf("%s/%s\n", svc.FullName(), methods.Get(i).Name())
+	}
+	return nil
+}
+
+func runGRPCDescribe(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var conn *grpc.Conn
+	if !grpcLocalDescriptors() {
+		c, err := grpcConnectFor(ctx)
+		if err != nil {
+			return err
+		}
+		conn = c
+	}
+
+	reg, err := grpcRegistry(ctx, conn, args[0])
+	if err != nil {
+		return err
+	}
+	desc, err := reg.Describe(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(desc)
+	return nil
+}
+
+// grpcConnectFor connects to the -s service for list and describe
+func grpcConnectFor(ctx context.Context) (*grpc.Conn, error) {
+	if serviceName == "" {
+		return nil, sreerrors.MissingRequiredFlag("service")
+	}
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if insecure && cfg.IsProtectedEnv(environment) {
+		return nil, sreerrors.InsecureTLSNotAllowed(environment)
+	}
+	metadata, err := parseHeaders(grpcMetadata)
+	if err != nil {
+		return nil, err
+	}
+	conn, _, err := grpcConnect(ctx, cfg, metadata, &history.Timing{})
+	return conn, err
+}
+
+// grpcConnect resolves the target like run does and opens a connection
+// over HTTP/2: h2 for https base URLs, h2c for http
+func grpcConnect(ctx context.Context, cfg *types.Config, metadata map[string]string, timing *history.Timing) (*grpc.Conn, *target, error) {
+	target, err := resolveTarget(ctx, cfg, timing)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target.creds.BaseURL == "" {
+		return nil, nil, sreerrors.BaseURLMissing(serviceName, environment)
+	}
+
+	opts, err := target.clientOptions(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	protocol := types.ProtocolHTTP2
+	if strings.HasPrefix(strings.ToLower(target.creds.BaseURL), "http://") {
+		protocol = types.ProtocolH2C
+	}
+	opts = append(opts, client.WithProtocol(protocol))
+
+	conn := grpc.NewConn(client.New(opts...), target.creds)
+	conn.Metadata = metadata
+	return conn, target, nil
+}
+
+// grpcLocalDescriptors reports whether message types come from files
+// rather than server reflection
+func grpcLocalDescriptors() bool {
+	return len(grpcProtos) > 0 || grpcProtoset != ""
+}
+
+// grpcRegistry loads the descriptors defining symbols, from --proto or
+// --protoset files if given, else by server reflection
+func grpcRegistry(ctx context.Context, conn *grpc.Conn, symbols ...string) (*protobuf.Registry, error) {
+	switch {
+	case grpcProtoset != "":
+		return protobuf.LoadFileSet(grpcProtoset)
+	case len(grpcProtos) > 0:
+		return protobuf.LoadFiles(grpcProtos, grpcImportPaths)
+	}
+
+	reg, err := conn.Reflect(ctx, symbols...)
+	if err != nil {
+		return nil, sreerrors.GRPCReflectionFailed(serviceName, err)
+	}
+	return reg, nil
+}
+
+// readGRPCInput returns the JSON request message from -d
+func readGRPCInput(data string) ([]byte, error) {
+	switch {
+	case data == "@-":
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message from stdin: %w", err)
+		}
+		return b, nil
+	case strings.HasPrefix(data, "@"):
+		b, err := os.ReadFile(data[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message file: %w", err)
+		}
+		return b, nil
+	}
+	return []byte(data), nil
+}
+
+// printGRPCMessage prints one response message, pretty-printed unless raw
+func printGRPCMessage(msg []byte, format string, red *redact.Redactor) {
+	if format == "json" {
+		printJSON(msg, red)
+		return
+	}
+	fmt.Println(red.String(string(msg)))
+}
@@ -124,6 +124,13 @@ func handleEntry(h *history.History, id int) error {
 		return err
 	}
 
+	if entry.Method == history.MethodGRPC && (historyCurl || historyHTTPie) {
+		return fmt.Errorf("gRPC calls cannot be exported as curl or HTTPie commands; use 'sreq history %d --replay'", entry.ID)
+	}
+	if entry.Method == history.MethodWS && (historyCurl || historyHTTPie) {
+		return fmt.Errorf("WebSocket sessions cannot be exported as curl or HTTPie commands; use 'sreq history %d --replay'", entry.ID)
+	}
+
 	// Export as curl
 	if historyCurl {
 		fmt.Println(entry.ToCurl())
@@ -237,7 +244,11 @@ func printEntryDetails(e *history.Entry) {
 			}
 		}
 		if e.Request.Body != "" {
-			fmt.Println("\nRequest Body:")
+			if e.Request.Compress != "" {
+				fmt.Printf("\nRequest Body (sent %s):\n", e.Request.Compress)
+			} else {
+				fmt.Println("\nRequest Body:")
+			}
 			fmt.Printf("  %s\n", e.Request.Body)
 		}
 	}
@@ -246,16 +257,64 @@ func printEntryDetails(e *history.Entry) {
 	if e.Response != nil {
 		if e.Response.Status != "" {
 			fmt.Printf("\nResponse: %s", e.Response.Status)
-			if e.Response.SizeBytes > 0 {
+			if e.Response.Protocol != "" {
+				fmt.Printf(" over %s", e.Response.Protocol)
+			}
+			if e.Response.SizeBytes > 0 && e.Response.Encoding != "" {
+				fmt.Printf(" (%d bytes, %d bytes %s)", e.Response.SizeBytes, e.Response.EncodedBytes, e.Response.Encoding)
+			} else if e.Response.SizeBytes > 0 {
 				fmt.Printf(" (%d bytes)", e.Response.SizeBytes)
 			}
 			fmt.Println()
 		}
+		if e.Response.Sample != "" {
+			fmt.Println("\nResponse Sample (streamed):")
+			fmt.Printf("  %s\n", e.Response.Sample)
+			if e.Response.Truncated {
+				fmt.Println("  ...")
+			}
+		}
+	}
+
+	// Latency breakdown
+	if e.Timing != nil {
+		fmt.Println()
+		printTiming(os.Stdout, *e.Timing)
+	}
+
+	// Redirect chain
+	if len(e.Redirects) > 0 {
+		fmt.Println("\nRedirects:")
+		for i, r := range e.Redirects {
+			fmt.Printf("  %d. %d -> %s %s", i+1, r.Status, r.Method, r.URL)
+			if len(r.Stripped) > 0 {
+				fmt.Printf(" (dropped %s)", strings.Join(r.Stripped, ", "))
+			}
+			fmt.Println()
+		}
+	}
+
+	// Retries
+	if len(e.Attempts) > 1 {
+		fmt.Println("\nAttempts:")
+		for i, a := range e.Attempts {
+			outcome := a.Error
+			if a.Status > 0 {
+				outcome = strconv.Itoa(a.Status)
+			}
+			fmt.Printf("  %d. %s (%dms)", i+1, outcome, a.Duration)
+			if a.Wait > 0 {
+				fmt.Printf(", retried after %dms", a.Wait)
+			}
+			fmt.Println()
+		}
 	}
 
 	// Export hints
 	fmt.Println()
-	fmt.Printf("Export: sreq history %d --curl\n", e.ID)
+	if e.Method != history.MethodGRPC && e.Method != history.MethodWS {
+		fmt.Printf("Export: sreq history %d --curl\n", e.ID)
+	}
 	fmt.Printf("Replay: sreq history %d --replay\n", e.ID)
 }
 
@@ -270,6 +329,24 @@ func replayEntry(e *history.Entry) error {
 	serviceName = e.Service
 	environment = e.Env
 
+	// gRPC calls are replayed with their request message
+	if e.Method == history.MethodGRPC {
+		if e.Request != nil && e.Request.Body != "" {
+			grpcData = e.Request.Body
+		}
+		return runGRPC(nil, []string{e.Path})
+	}
+
+	// WebSocket sessions send the same messages again
+	if e.Method == history.MethodWS {
+		body := ""
+		if e.Request != nil {
+			body = e.Request.Body
+		}
+		wsReplay = &body
+		return runWS(nil, []string{e.Path})
+	}
+
 	// Run the request
 	return runRun(nil, args)
 }
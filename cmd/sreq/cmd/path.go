@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/internal/template"
+)
+
+// renderPath fills {name} path parameters from vars, escaping each value
+// as a single path segment
+func renderPath(path string, vars map[string]string) (string, error) {
+	if !strings.ContainsAny(path, "{}") {
+		return path, nil
+	}
+
+	escaped := make(map[string]string, len(vars))
+	for k, v := range vars {
+		escaped[k] = url.PathEscape(v)
+	}
+
+	rendered, err := template.Render(path, escaped, template.Options{})
+	var unresolved *template.UnresolvedError
+	if errors.As(err, &unresolved) {
+		return "", fmt.Errorf("missing path parameters %s (pass them with --param name=value)", strings.Join(braced(unresolved.Names), ", "))
+	}
+	if err != nil {
+		return "", err
+	}
+	return rendered, nil
+}
+
+// pathVars collects path parameter values. Later sources win: resolved
+// custom values, then context variables, then --param flags.
+func pathVars(custom, contextVars map[string]string, params []string) (map[string]string, error) {
+	vars := make(map[string]string, len(custom)+len(contextVars)+len(params))
+	for k, v := range custom {
+		vars[k] = v
+	}
+	for k, v := range contextVars {
+		if v != "" {
+			vars[k] = v
+		}
+	}
+	for _, p := range params {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid path parameter: %s (expected 'name=value')", p)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// redactPathValues registers the resolved custom values that fill
+// placeholders in path, so the rendered URL does not leak them
+func redactPathValues(red *redact.Redactor, path string, custom, vars map[string]string) {
+	for _, name := range template.Names(path) {
+		if v, ok := custom[name]; ok && vars[name] == v {
+			red.Add(v, url.PathEscape(v))
+		}
+	}
+}
+
+// appendQuery adds -q key=value parameters to a path, encoded and in the
+// order given
+func appendQuery(path string, params []string) (string, error) {
+	if len(params) == 0 {
+		return path, nil
+	}
+
+	pairs := make([]string, 0, len(params))
+	for _, p := range params {
+		key, value, _ := strings.Cut(p, "=")
+		if key == "" {
+			return "", fmt.Errorf("invalid query parameter: %s (expected 'key=value')", p)
+		}
+		pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(value))
+	}
+
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + strings.Join(pairs, "&"), nil
+}
+
+// braced formats names as {name} placeholders
+func braced(names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = "{" + n + "}"
+	}
+	return out
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Priyans-hu/sreq/internal/history"
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestRenderPath(t *testing.T) {
+	vars := map[string]string{"id": "42", "name": "a b/c", "env": "dev"}
+
+	tests := []struct {
+		path, want string
+	}{
+		{"/users", "/users"},
+		{"/users/{id}", "/users/42"},
+		{"/files/{name}", "/files/a%20b%2Fc"},
+		{"/{env}/users/{id}?expand=true", "/dev/users/42?expand=true"},
+	}
+	for _, tt := range tests {
+		got, err := renderPath(tt.path, vars)
+		if err != nil {
+			t.Errorf("renderPath(%q) error = %v", tt.path, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("renderPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	_, err := renderPath("/orgs/{org}/users/{user}", vars)
+	if err == nil || !strings.Contains(err.Error(), "{org}, {user}") {
+		t.Errorf("renderPath() error = %v, want missing parameters listed", err)
+	}
+}
+
+func TestPathVars(t *testing.T) {
+	vars, err := pathVars(
+		map[string]string{"tenant": "custom", "region": "custom"},
+		map[string]string{"region": "us-east-1", "app": ""},
+		[]string{"tenant=flag"},
+	)
+	if err != nil {
+		t.Fatalf("pathVars() error = %v", err)
+	}
+	if vars["tenant"] != "flag" || vars["region"] != "us-east-1" {
+		t.Errorf("pathVars() = %v, want flags over context over custom", vars)
+	}
+	if _, ok := vars["app"]; ok {
+		t.Error("empty context values should not be set")
+	}
+
+	if _, err := pathVars(nil, nil, []string{"novalue"}); err == nil {
+		t.Error("expected error for a parameter without '='")
+	}
+}
+
+func TestAppendQuery(t *testing.T) {
+	tests := []struct {
+		path   string
+		params []string
+		want   string
+	}{
+		{"/search", nil, "/search"},
+		{"/search", []string{"q=a b&c", "page=2"}, "/search?q=a+b%26c&page=2"},
+		{"/search?sort=asc", []string{"flag"}, "/search?sort=asc&flag="},
+	}
+	for _, tt := range tests {
+		got, err := appendQuery(tt.path, tt.params)
+		if err != nil {
+			t.Errorf("appendQuery(%q) error = %v", tt.path, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("appendQuery(%q, %v) = %q, want %q", tt.path, tt.params, got, tt.want)
+		}
+	}
+
+	if _, err := appendQuery("/x", []string{"=v"}); err == nil {
+		t.Error("expected error for an empty key")
+	}
+}
+
+func TestSaveHistory_RedactsRenderedPath(t *testing.T) {
+	tmpDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: "https://api.example.com",
+		Custom:  map[string]string{"tenant_secret": "t3nant-s3cret-value"},
+	}
+	vars, err := pathVars(creds.Custom, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := renderPath("/tenants/{tenant_secret}/orders", vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &types.Request{Method: "GET", Path: path, Service: "orders", Environment: "dev"}
+	saveHistory(req, creds.BaseURL, nil, nil, 1, history.Timing{}, redact.ForCredentials(creds))
+
+	h, err := history.New(filepath.Join(tmpDir, ".sreq"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := h.List(history.ListOptions{})
+	if len(entries) != 1 {
+		t.Fatalf("history has %d entries, want 1", len(entries))
+	}
+	if got := entries[0].Path; strings.Contains(got, "t3nant-s3cret-value") || got != "/tenants/"+redact.Mask+"/orders" {
+		t.Errorf("saved Path = %q, want the secret masked", got)
+	}
+}
+
+func TestSaveHistory_RedactsBaseURL(t *testing.T) {
+	tmpDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL:  "https://api.example.com/t/b4se-s3cret?api_key=qu3ry-k3y",
+		Password: "b4se-s3cret",
+	}
+	req := &types.Request{Method: "GET", Path: "/orders", Service: "orders", Environment: "dev"}
+	saveHistory(req, creds.BaseURL, nil, nil, 1, history.Timing{}, redact.ForCredentials(creds))
+
+	h, err := history.New(filepath.Join(tmpDir, ".sreq"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := h.List(history.ListOptions{})
+	if len(entries) != 1 {
+		t.Fatalf("history has %d entries, want 1", len(entries))
+	}
+	if got := entries[0].BaseURL; strings.Contains(got, "b4se-s3cret") || strings.Contains(got, "qu3ry-k3y") {
+		t.Errorf("saved BaseURL = %q, want the secrets masked", got)
+	}
+}
+
+func TestRedactPathValues(t *testing.T) {
+	creds := &types.ResolvedCredentials{
+		Custom: map[string]string{"tenant": "acme/7731-internal", "region": "eu-west-1", "shard": "shard-0042"},
+	}
+	red := redact.ForCredentials(creds)
+	vars, err := pathVars(creds.Custom, nil, []string{"shard=shard-0001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := "/tenants/{tenant}/shards/{shard}"
+	redactPathValues(red, tmpl, creds.Custom, vars)
+	path, err := renderPath(tmpl, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := red.URL(path), "/tenants/"+redact.Mask+"/shards/shard-0001"; got != want {
+		t.Errorf("redacted path = %q, want %q", got, want)
+	}
+	// Only values that filled a placeholder are registered
+	for _, s := range red.Secrets() {
+		if s == "eu-west-1" || s == "shard-0042" {
+			t.Errorf("unused value %q registered as a secret", s)
+		}
+	}
+}
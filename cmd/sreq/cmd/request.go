@@ -3,8 +3,11 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"strings"
 	"time"
 
@@ -13,7 +16,7 @@ import (
 	"github.com/Priyans-hu/sreq/internal/config"
 	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
 	"github.com/Priyans-hu/sreq/internal/history"
-	"github.com/Priyans-hu/sreq/internal/resolver"
+	"github.com/Priyans-hu/sreq/internal/redact"
 	"github.com/Priyans-hu/sreq/pkg/types"
 	"github.com/spf13/cobra"
 )
@@ -36,22 +39,54 @@ Examples:
 
 var (
 	requestData    string
+	multipartForm  []string
+	queryParams    []string
+	pathParams     []string
+	urlencodedForm []string
 	requestHeaders []string
 	outputFormat   string
 	timeout        time.Duration
+	showTiming     bool
+	streamMode     bool
+	maxTime        time.Duration
 	offlineMode    bool
 	noCache        bool
+	insecure       bool
+	useCookies     bool
+
+	followRedirects bool
+	noFollow        bool
+	maxRedirects    int
+	locationTrusted bool
+	httpProtocol    string
+	compressBody    string
 )
 
 func init() {
 	rootCmd.AddCommand(requestCmd)
 
-	requestCmd.Flags().StringVarP(&requestData, "data", "d", "", "Request body (or @filename for file)")
+	requestCmd.Flags().StringVarP(&requestData, "data", "d", "", "Request body (@filename streams a file, @- reads stdin)")
+	requestCmd.Flags().StringArrayVarP(&multipartForm, "multipart", "F", nil, "Multipart field name=value or name=@file[;type=...][;filename=...] (repeatable)")
+	requestCmd.Flags().StringArrayVar(&urlencodedForm, "form", nil, "URL-encoded form field name=value (repeatable)")
+	requestCmd.Flags().StringVar(&compressBody, "compress", "", "Compress the request body: gzip or zstd (sets Content-Encoding)")
 	requestCmd.Flags().StringArrayVarP(&requestHeaders, "header", "H", nil, "Add header (repeatable)")
+	requestCmd.Flags().StringArrayVarP(&queryParams, "query", "q", nil, "Add query parameter key=value, URL-encoded (repeatable)")
+	requestCmd.Flags().StringArrayVarP(&pathParams, "param", "P", nil, "Fill a {name} path parameter with name=value (repeatable)")
 	requestCmd.Flags().StringVarP(&outputFormat, "output", "o", "json", "Output format (json/raw/headers)")
 	requestCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Request timeout")
+	requestCmd.Flags().DurationVar(&maxTime, "max-time", 0, "Limit for the whole command, including a streamed body (0: --timeout, none when streaming)")
+	requestCmd.Flags().BoolVar(&streamMode, "stream", false, "Print the body as it arrives (SSE, NDJSON, long downloads)")
+	requestCmd.Flags().BoolVar(&showTiming, "timing", false, "Show a latency breakdown (DNS, connect, TLS, TTFB, transfer)")
+	requestCmd.Flags().StringVar(&httpProtocol, "protocol", "", "Force an HTTP version: http1.1, h2, h2c or h3 (default: negotiated)")
+	requestCmd.Flags().BoolVar(&followRedirects, "follow", true, "Follow redirects")
+	requestCmd.Flags().BoolVar(&noFollow, "no-follow", false, "Return redirect responses instead of following them")
+	requestCmd.Flags().IntVar(&maxRedirects, "max-redirs", client.DefaultMaxRedirects, "Most redirects to follow")
+	requestCmd.Flags().BoolVar(&locationTrusted, "location-trusted", false, "Keep credentials on redirects to other hosts")
+	requestCmd.MarkFlagsMutuallyExclusive("follow", "no-follow")
 	requestCmd.Flags().BoolVar(&offlineMode, "offline", false, "Use cached credentials only (no provider calls)")
 	requestCmd.Flags().BoolVar(&noCache, "no-cache", false, "Skip cache and fetch fresh credentials")
+	requestCmd.Flags().BoolVar(&useCookies, "cookies", false, "Keep cookies in a persistent jar for this service and env (also 'cookies: true' in the service config)")
+	requestCmd.Flags().BoolVarP(&insecure, "insecure", "k", false, "Skip TLS certificate verification (refused in protected environments)")
 }
 
 func runRun(cmd *cobra.Command, args []string) error {
@@ -72,66 +107,73 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return sreerrors.MissingRequiredFlag("service")
 	}
 
-	// Load configuration
-	cfg, err := config.Load()
+	cfg, ctxName, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
-	// Load context values (from -c flag or default_context)
-	ctxName := contextName
-	if ctxName == "" {
-		ctxName = cfg.DefaultContext
+	if insecure && cfg.IsProtectedEnv(environment) {
+		return sreerrors.InsecureTLSNotAllowed(environment)
 	}
 
-	// Apply context values as base, then override with explicit CLI flags
-	if ctxName != "" {
-		if ctx, exists := cfg.Contexts[ctxName]; exists {
-			if environment == "" {
-				environment = ctx.Env
-			}
-			if region == "" {
-				region = ctx.Region
-			}
-			if project == "" {
-				project = ctx.Project
-			}
-			if app == "" {
-				app = ctx.App
-			}
-		} else if contextName != "" {
-			// Only error if user explicitly specified a context that doesn't exist
-			return sreerrors.ContextNotFound(contextName)
-		}
+	headers, err := parseHeaders(requestHeaders)
+	if err != nil {
+		return err
 	}
 
-	// Use default environment if still not specified
-	if environment == "" {
-		environment = cfg.DefaultEnv
-		if environment == "" {
-			environment = "dev"
+	// Build the body: raw data, a file streamed from disk, stdin, or a form
+	bodySources := 0
+	for _, set := range []bool{requestData != "", len(multipartForm) > 0, len(urlencodedForm) > 0} {
+		if set {
+			bodySources++
 		}
 	}
-
-	// Parse headers
-	headers := make(map[string]string)
-	for _, h := range requestHeaders {
-		parts := strings.SplitN(h, ":", 2)
-		if len(parts) != 2 {
-			return fmt.Errorf("invalid header format: %s (expected 'Key: Value')", h)
-		}
-		headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	if bodySources > 1 {
+		return fmt.Errorf("use only one of --data, --multipart and --form")
+	}
+	if err := client.ValidateCompression(compressBody); err != nil {
+		return err
+	}
+	if compressBody != "" && len(multipartForm) > 0 {
+		return fmt.Errorf("--compress cannot be used with --multipart")
 	}
 
-	// Handle @filename for body
 	body := requestData
-	if strings.HasPrefix(body, "@") {
-		filename := body[1:]
-		data, err := os.ReadFile(filename)
+	var bodyFile string
+	var formFields []types.FormField
+	switch {
+	case body == "@-":
+		data, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			return fmt.Errorf("failed to read body file: %w", err)
+			return fmt.Errorf("failed to read body from stdin: %w", err)
 		}
 		body = string(data)
+	case strings.HasPrefix(body, "@"):
+		bodyFile = body[1:]
+		if _, err := os.Stat(bodyFile); err != nil {
+			return fmt.Errorf("failed to read body file: %w", err)
+		}
+		body = ""
+	case len(multipartForm) > 0:
+		for _, spec := range multipartForm {
+			field, err := parseFormField(spec)
+			if err != nil {
+				return err
+			}
+			if field.File != "" {
+				if _, err := os.Stat(field.File); err != nil {
+					return fmt.Errorf("failed to read form file: %w", err)
+				}
+			}
+			formFields = append(formFields, field)
+		}
+	case len(urlencodedForm) > 0:
+		if body, err = urlencodedBody(urlencodedForm); err != nil {
+			return err
+		}
+		if !hasHeader(headers, "Content-Type") {
+			headers["Content-Type"] = "application/x-www-form-urlencoded"
+		}
 	}
 
 	if verbose {
@@ -154,13 +196,24 @@ func runRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Path:        %s\n", path)
 		if len(headers) > 0 {
 			fmt.Println("  Headers:")
+			hdrRed := redact.New()
+			hdrRed.SetReveal(secretsRevealed())
 			for k, v := range headers {
-				fmt.Printf("    %s: %s\n", k, v)
+				fmt.Printf("    %s: %s\n", k, hdrRed.Header(k, v))
 			}
 		}
 		if body != "" {
 			fmt.Printf("  Body:        %s\n", truncate(body, 100))
 		}
+		if bodyFile != "" {
+			fmt.Printf("  Body:        @%s (streamed)\n", bodyFile)
+		}
+		if compressBody != "" {
+			fmt.Printf("  Compress:    %s\n", compressBody)
+		}
+		for _, f := range formSummary(formFields) {
+			fmt.Printf("  Form:        %s\n", f)
+		}
 		fmt.Println()
 	}
 
@@ -172,89 +225,90 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	// Get config directory for cache
-	configDir, _ := config.GetConfigDir()
-
-	// Try to get credentials from cache first (unless --no-cache)
-	var creds *types.ResolvedCredentials
-	var credCache *cache.Cache
-	useCache := !noCache && !cache.IsDisabled() && cache.KeyExists(configDir)
-
-	if useCache {
-		credCache, _ = cache.New(cache.Config{ConfigDir: configDir})
-		if credCache != nil {
-			creds, _ = credCache.Get(serviceName, environment)
-			if creds != nil && verbose {
-				fmt.Println("Using cached credentials")
-			}
-		}
+	// --timeout bounds each attempt and, by default, the whole command.
+	// A streamed body has no limit unless --max-time is set.
+	limit := timeout
+	if maxTime > 0 || streamMode {
+		limit = maxTime
 	}
-
-	// If offline mode, we must have cached credentials
-	if offlineMode {
-		if creds == nil {
-			return fmt.Errorf("no cached credentials found for %s/%s (run 'sreq sync %s' first)",
-				serviceName, environment, environment)
-		}
+	ctx, cancel := context.WithCancel(context.Background())
+	if limit > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), limit)
 	}
-
-	// If no cached credentials, resolve from providers
-	if creds == nil {
-		// Create resolver
-		res, err := resolver.New(cfg)
-		if err != nil {
-			return fmt.Errorf("failed to create resolver: %w", err)
-		}
-
-		// Resolve credentials
-		if verbose {
-			fmt.Println("Resolving credentials from providers...")
-		}
-
-		creds, err = res.Resolve(ctx, resolver.ResolveOptions{
-			Service: serviceName,
-			Env:     environment,
-			Region:  region,
-			Project: project,
-			App:     app,
-		})
-		if err != nil {
-			return sreerrors.CredentialResolutionFailed(serviceName, environment, err)
-		}
-
-		// Cache the credentials for next time (unless --no-cache)
-		if useCache && credCache != nil {
-			if err := credCache.Set(serviceName, environment, creds); err != nil && verbose {
-				fmt.Printf("Warning: failed to cache credentials: %v\n", err)
-			}
-		}
+	defer cancel()
+	if streamMode {
+		// Ctrl-C ends a stream cleanly, keeping its history entry
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
 	}
 
-	if verbose {
-		fmt.Printf("  Base URL:  %s\n", creds.BaseURL)
-		if creds.Username != "" {
-			fmt.Printf("  Username:  %s\n", creds.Username)
-			fmt.Printf("  Password:  %s\n", maskPassword(creds.Password))
-		}
-		if creds.APIKey != "" {
-			fmt.Printf("  API Key:   %s\n", maskPassword(creds.APIKey))
-		}
-		fmt.Println()
+	var timing history.Timing
+	target, err := resolveTarget(ctx, cfg, &timing)
+	if err != nil {
+		return err
 	}
+	creds, credCache, red := target.creds, target.cache, target.red
 
 	if creds.BaseURL == "" {
 		return sreerrors.BaseURLMissing(serviceName, environment)
 	}
 
+	// Fill {name} path parameters and add -q query parameters
+	contextVars := map[string]string{
+		"service": serviceName,
+		"env":     environment,
+		"region":  region,
+		"project": project,
+		"app":     app,
+	}
+	vars, err := pathVars(creds.Custom, contextVars, pathParams)
+	if err != nil {
+		return err
+	}
+	redactPathValues(red, path, creds.Custom, vars)
+	if path, err = renderPath(path, vars); err != nil {
+		return err
+	}
+	if path, err = appendQuery(path, queryParams); err != nil {
+		return err
+	}
+
 	// Create HTTP client
-	httpClient := client.New(
-		client.WithTimeout(timeout),
-		client.WithVerbose(verbose),
-	)
+	clientOpts, err := target.clientOptions(cfg)
+	if err != nil {
+		return err
+	}
+	protocol, err := protocolFor(cfg, serviceName, environment)
+	if err != nil {
+		return err
+	}
+	clientOpts = append(clientOpts, client.WithProtocol(protocol))
+	if maxRedirects < 0 {
+		return fmt.Errorf("--max-redirs must not be negative")
+	}
+	clientOpts = append(clientOpts, client.WithRedirects(client.RedirectPolicy{
+		NoFollow: noFollow || !followRedirects,
+		Max:      maxRedirects,
+		Trusted:  locationTrusted,
+	}))
+
+	var jar *client.Jar
+	if useCookies || cookiesEnabled(cfg, serviceName, environment) {
+		var stored []types.Cookie
+		if credCache == nil && !cache.IsDisabled() && cache.KeyExists(target.configDir) {
+			credCache, _ = cache.New(cache.Config{ConfigDir: target.configDir})
+		}
+		if credCache != nil {
+			stored, _ = credCache.GetCookies(serviceName, environment)
+		} else if verbose {
+			fmt.Println("Cache unavailable, cookies will not be kept after this request")
+		}
+		jar = client.NewJar(stored)
+		jar.Redact(red)
+		clientOpts = append(clientOpts, client.WithCookieJar(jar))
+	}
+	httpClient := client.New(clientOpts...)
 
 	// Build request
 	req := &types.Request{
@@ -264,27 +318,73 @@ func runRun(cmd *cobra.Command, args []string) error {
 		Environment: environment,
 		Body:        body,
 		Headers:     headers,
+		BodyFile:    bodyFile,
+		Multipart:   formFields,
+		Compress:    compressBody,
 	}
 
 	// Execute request
-	resp, err := httpClient.Do(ctx, req, creds)
+	var resp *types.Response
+	if streamMode {
+		resp, err = httpClient.DoStream(ctx, req, creds, streamOutput(os.Stdout, outputFormat, red))
+	} else {
+		resp, err = httpClient.Do(ctx, req, creds)
+	}
 	duration := time.Since(startTime).Milliseconds()
+	if jar != nil && jar.Changed() && credCache != nil {
+		if err := credCache.SetCookies(serviceName, environment, jar.All()); err != nil && verbose {
+			fmt.Printf("Warning: failed to save cookies: %v\n", err)
+		}
+	}
+	if resp != nil && resp.Timing != nil {
+		timing.SetRequest(resp.Timing)
+	}
 
-	// Save to history (unless disabled or dry run)
+	// Save to history (unless disabled or dry run). History never
+	// stores secrets, even with --reveal-secrets.
 	if os.Getenv("SREQ_NO_HISTORY") != "1" && !dryRun {
-		saveHistory(method, path, serviceName, environment, creds.BaseURL, headers, body, resp, duration)
+		histRed := redact.ForCredentials(creds)
+		histRed.Add(red.Secrets()...)
+		saveHistory(req, creds.BaseURL, resp, err, duration, timing, histRed)
 	}
 
-	if err != nil {
-		return sreerrors.RequestFailed(creds.BaseURL+path, err)
+	if streamMode && resp != nil {
+		// The body was printed as it arrived; only report how it ended
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return fmt.Errorf("stream stopped after --max-time %s", maxTime)
+		case ctx.Err() != nil:
+			// Interrupted
+		case err != nil:
+			return red.Error(fmt.Errorf("stream failed: %w", err))
+		}
+	} else if err != nil {
+		return red.Error(sreerrors.RequestFailed(red.URL(creds.BaseURL+path), err))
+	} else if err := outputResponse(resp, outputFormat, red); err != nil {
+		return err
 	}
 
-	// Output response
-	return outputResponse(resp, outputFormat)
+	if showTiming {
+		printTiming(os.Stderr, timing)
+	}
+	return nil
 }
 
-// saveHistory saves the request to history
-func saveHistory(method, path, service, env, baseURL string, headers map[string]string, body string, resp *types.Response, durationMs int64) {
+// parseHeaders parses "Key: Value" flags
+func parseHeaders(specs []string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, h := range specs {
+		parts := strings.SplitN(h, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid header format: %s (expected 'Key: Value')", h)
+		}
+		headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return headers, nil
+}
+
+// saveHistory saves the request to history, masking secrets with red
+func saveHistory(req *types.Request, baseURL string, resp *types.Response, reqErr error, durationMs int64, timing history.Timing, red *redact.Redactor) {
 	configDir, err := config.GetConfigDir()
 	if err != nil {
 		return // Silently fail - history is optional
@@ -297,24 +397,72 @@ func saveHistory(method, path, service, env, baseURL string, headers map[string]
 
 	entry := history.Entry{
 		Timestamp: time.Now(),
-		Service:   service,
-		Env:       env,
-		Method:    method,
-		Path:      path,
-		BaseURL:   baseURL,
+		Service:   req.Service,
+		Env:       req.Environment,
+		Method:    req.Method,
+		Path:      red.URL(req.Path),
+		BaseURL:   red.URL(baseURL),
 		Duration:  durationMs,
 		Request: &history.Request{
-			Headers: headers,
-			Body:    body,
+			Headers:  red.Headers(req.Headers),
+			Body:     red.String(req.Body),
+			Compress: req.Compress,
 		},
 	}
+	// Uploads are recorded by path, as curl would take them
+	if req.BodyFile != "" {
+		entry.Request.Body = "@" + req.BodyFile
+	}
+	for _, f := range formSummary(req.Multipart) {
+		entry.Request.Form = append(entry.Request.Form, red.String(f))
+	}
 
 	// Add response info if available
 	if resp != nil {
+		entry.Timing = &timing
 		entry.Status = resp.StatusCode
 		entry.Response = &history.Response{
 			Status:    resp.Status,
-			SizeBytes: len(resp.Body),
+			Protocol:  resp.Proto,
+			SizeBytes: int(resp.Size),
+		}
+		if resp.Encoding != "" {
+			entry.Response.Encoding = resp.Encoding
+			entry.Response.EncodedBytes = int(resp.EncodedSize)
+		}
+		if resp.Streamed {
+			entry.Response.Sample = red.String(string(resp.Body))
+			entry.Response.Truncated = resp.Truncated
+		}
+	}
+
+	if resp != nil {
+		for _, r := range resp.Redirects {
+			entry.Redirects = append(entry.Redirects, history.Redirect{
+				Status:   r.StatusCode,
+				Method:   r.Method,
+				URL:      red.URL(r.URL),
+				Stripped: r.Stripped,
+			})
+		}
+	}
+
+	// Record retries, from the response or the final error
+	var attempts []types.Attempt
+	var retryErr *client.RetryError
+	if resp != nil {
+		attempts = resp.Attempts
+	} else if errors.As(reqErr, &retryErr) {
+		attempts = retryErr.Attempts
+	}
+	if len(attempts) > 1 {
+		for _, a := range attempts {
+			entry.Attempts = append(entry.Attempts, history.Attempt{
+				Status:   a.StatusCode,
+				Error:    red.URL(a.Error),
+				Duration: a.Duration.Milliseconds(),
+				Wait:     a.Wait.Milliseconds(),
+			})
 		}
 	}
 
@@ -336,7 +484,8 @@ func maskPassword(s string) string {
 	return s[:2] + strings.Repeat("*", len(s)-4) + s[len(s)-2:]
 }
 
-func outputResponse(resp *types.Response, format string) error {
+// outputResponse prints a response with secrets that it echoes masked
+func outputResponse(resp *types.Response, format string, red *redact.Redactor) error {
 	switch format {
 	case "json":
 		// Try to pretty-print if it's JSON
@@ -344,25 +493,29 @@ func outputResponse(resp *types.Response, format string) error {
 		if err := json.Unmarshal(resp.Body, &jsonData); err == nil {
 			prettyJSON, err := json.MarshalIndent(jsonData, "", "  ")
 			if err == nil {
-				fmt.Println(string(prettyJSON))
+				fmt.Println(red.String(string(prettyJSON)))
 				return nil
 			}
 		}
 		// Fall back to raw if not valid JSON
-		fmt.Println(string(resp.Body))
+		fmt.Println(red.String(string(resp.Body)))
 
 	case "raw":
-		fmt.Println(string(resp.Body))
+		fmt.Println(red.String(string(resp.Body)))
 
 	case "headers":
-		fmt.Printf("HTTP %s\n", resp.Status)
+		if resp.Proto != "" {
+			fmt.Printf("%s %s\n", resp.Proto, resp.Status)
+		} else {
+			fmt.Printf("HTTP %s\n", resp.Status)
+		}
 		for key, values := range resp.Headers {
 			for _, value := range values {
-				fmt.Printf("%s: %s\n", key, value)
+				fmt.Printf("%s: %s\n", key, red.Header(key, value))
 			}
 		}
 		fmt.Println()
-		fmt.Println(string(resp.Body))
+		fmt.Println(red.String(string(resp.Body)))
 
 	default:
 		return fmt.Errorf("unknown output format: %s", format)
@@ -370,3 +523,51 @@ func outputResponse(resp *types.Response, format string) error {
 
 	return nil
 }
+
+// cookiesEnabled reports whether the service config turns the cookie jar on
+func cookiesEnabled(cfg *types.Config, service, env string) bool {
+	svc, err := config.ResolveService(cfg, service, env)
+	return err == nil && svc.Cookies != nil && *svc.Cookies
+}
+
+// retryPolicyFor returns the retry policy of a service, falling back to the
+// global one
+func retryPolicyFor(cfg *types.Config, service, env string) (client.RetryPolicy, error) {
+	retryCfg := cfg.Retry
+	if svc, err := config.ResolveService(cfg, service, env); err == nil && svc.Retry != nil {
+		retryCfg = svc.Retry
+	}
+	policy, err := client.NewRetryPolicy(retryCfg)
+	if err != nil {
+		return client.RetryPolicy{}, sreerrors.InvalidRetryConfig(service, err)
+	}
+	return policy, nil
+}
+
+// protocolFor returns the HTTP version to pin: --protocol, else the
+// service's own, else none
+func protocolFor(cfg *types.Config, service, env string) (string, error) {
+	protocol := httpProtocol
+	if protocol == "" {
+		if svc, err := config.ResolveService(cfg, service, env); err == nil {
+			protocol = svc.Protocol
+		}
+	}
+	if err := client.ValidateProtocol(protocol); err != nil {
+		return "", sreerrors.InvalidProtocol(service, err)
+	}
+	return protocol, nil
+}
+
+// msSince returns the milliseconds elapsed since start
+func msSince(start time.Time) float64 {
+	return history.Milliseconds(time.Since(start))
+}
+
+// printTiming writes a latency breakdown
+func printTiming(w io.Writer, t history.Timing) {
+	_, _ = fmt.Fprintln(w, "Timing:")
+	for _, phase := range t.Phases() {
+		_, _ = fmt.Fprintf(w, "  %-13s %8.2fms\n", phase.Name+":", phase.Ms)
+	}
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 )
 
@@ -17,6 +19,8 @@ var (
 	contextName string
 	verbose     bool
 	dryRun      bool
+
+	revealSecrets bool
 )
 
 var rootCmd = &cobra.Command{
@@ -56,4 +60,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&app, "app", "a", "", "App name (overrides context)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Show what would be sent without executing")
+	rootCmd.PersistentFlags().BoolVar(&revealSecrets, "reveal-secrets", false, "Show secrets in terminal output for local debugging (also SREQ_REVEAL_SECRETS=1)")
+}
+
+// secretsRevealed reports whether terminal output should show secrets
+func secretsRevealed() bool {
+	return revealSecrets || os.Getenv("SREQ_REVEAL_SECRETS") == "1"
 }
@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/Priyans-hu/sreq/internal/config"
 	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/pkg/types"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -21,7 +24,12 @@ var serviceCmd = &cobra.Command{
 var serviceListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List configured services",
-	RunE:  runServiceList,
+	Long: `List configured services, including inheritance (extends) and
+per-environment overrides (envs).
+
+Pass -e to show the effective configuration for one environment:
+  sreq service list -e prod`,
+	RunE: runServiceList,
 }
 
 var serviceAddCmd = &cobra.Command{
@@ -77,20 +85,45 @@ func runServiceList(cmd *cobra.Command, args []string) error {
 	// Also try to load from services.yaml
 	servicesData, _ := loadServicesFile()
 
-	fmt.Println("Configured services:")
+	if environment != "" {
+		fmt.Printf("Configured services (effective for env: %s):\n", environment)
+	} else {
+		fmt.Println("Configured services:")
+	}
 	fmt.Println()
 
 	count := 0
 
 	// Services from main config
-	for name, svc := range cfg.Services {
+	names := make([]string, 0, len(cfg.Services))
+	for name := range cfg.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
 		count++
 		fmt.Printf("  %s\n", name)
-		if svc.ConsulKey != "" {
-			fmt.Printf("    consul_key: %s\n", svc.ConsulKey)
+
+		// Validate inheritance, and with -e show the merged result
+		effective, err := config.ResolveService(cfg, name, environment)
+		if err != nil {
+			fmt.Printf("    error: %s\n", strings.SplitN(err.Error(), "\n", 2)[0])
+			fmt.Println()
+			continue
 		}
-		if svc.AWSPrefix != "" {
-			fmt.Printf("    aws_prefix: %s\n", svc.AWSPrefix)
+
+		if environment != "" {
+			// Show what requests use, including the global fallbacks
+			resolved := *effective
+			resolved.Proxy = config.ProxyFor(cfg, effective, environment)
+			if resolved.Retry == nil {
+				resolved.Retry = cfg.Retry
+			}
+			printServiceConfig(&resolved)
+		} else {
+			svc := cfg.Services[name]
+			printServiceConfig(&svc)
 		}
 		fmt.Println()
 	}
@@ -134,6 +167,119 @@ func runServiceList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printServiceConfig prints a service's fields, including extends and env overrides
+func printServiceConfig(svc *types.ServiceConfig) {
+	if svc.Extends != "" {
+		fmt.Printf("    extends: %s\n", svc.Extends)
+	}
+	if svc.ConsulKey != "" {
+		fmt.Printf("    consul_key: %s\n", svc.ConsulKey)
+	}
+	if svc.AWSPrefix != "" {
+		fmt.Printf("    aws_prefix: %s\n", svc.AWSPrefix)
+	}
+	printPaths("    ", svc.Paths)
+	printSettings("    ", svc.Auth, svc.TLS, svc.Retry, svc.Proxy, svc.Protocol, svc.Cookies)
+
+	if len(svc.Envs) > 0 {
+		envs := make([]string, 0, len(svc.Envs))
+		for env := range svc.Envs {
+			envs = append(envs, env)
+		}
+		sort.Strings(envs)
+
+		fmt.Println("    envs:")
+		for _, env := range envs {
+			override := svc.Envs[env]
+			fmt.Printf("      %s:\n", env)
+			if override.ConsulKey != "" {
+				fmt.Printf("        consul_key: %s\n", override.ConsulKey)
+			}
+			if override.AWSPrefix != "" {
+				fmt.Printf("        aws_prefix: %s\n", override.AWSPrefix)
+			}
+			printPaths("        ", override.Paths)
+			printSettings("        ", override.Auth, override.TLS, override.Retry, override.Proxy, override.Protocol, override.Cookies)
+		}
+	}
+}
+
+// printSettings prints the auth, tls, retry, proxy, protocol and cookies
+// settings that are set
+func printSettings(indent string, auth *types.AuthConfig, tls *types.TLSConfig, retry *types.RetryConfig, proxy *types.ProxyConfig, protocol string, cookies *bool) {
+	if auth != nil {
+		fmt.Printf("%sauth: %s\n", indent, auth.Type)
+	}
+	if tls != nil {
+		fmt.Printf("%stls:\n", indent)
+		printField(indent+"  ", "cert", tls.Cert)
+		printField(indent+"  ", "key", tls.Key)
+		printField(indent+"  ", "ca", tls.CA)
+		printField(indent+"  ", "server_name", tls.ServerName)
+		if tls.InsecureSkipVerify {
+			fmt.Printf("%s  insecure_skip_verify: true\n", indent)
+		}
+	}
+	if retry != nil {
+		fmt.Printf("%sretry:\n", indent)
+		if retry.MaxAttempts != 0 {
+			fmt.Printf("%s  max_attempts: %d\n", indent, retry.MaxAttempts)
+		}
+		printField(indent+"  ", "backoff", retry.Backoff)
+		printField(indent+"  ", "max_backoff", retry.MaxBackoff)
+		if len(retry.RetryOn) > 0 {
+			fmt.Printf("%s  retry_on: %v\n", indent, retry.RetryOn)
+		}
+		if retry.NonIdempotent {
+			fmt.Printf("%s  non_idempotent: true\n", indent)
+		}
+	}
+	if proxy != nil {
+		fmt.Printf("%sproxy: %s\n", indent, displayURL(proxy.URL))
+		if len(proxy.NoProxy) > 0 {
+			fmt.Printf("%s  no_proxy: %s\n", indent, strings.Join(proxy.NoProxy, ", "))
+		}
+	}
+	printField(indent, "protocol", protocol)
+	if cookies != nil {
+		fmt.Printf("%scookies: %t\n", indent, *cookies)
+	}
+}
+
+// printField prints a non-empty string setting
+func printField(indent, name, value string) {
+	if value != "" {
+		fmt.Printf("%s%s: %s\n", indent, name, value)
+	}
+}
+
+// displayURL hides the password in a URL's user info
+func displayURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
+// printPaths prints path mappings sorted by key
+func printPaths(indent string, paths map[string]string) {
+	if len(paths) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(paths))
+	for key := range paths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Printf("%spaths:\n", indent)
+	for _, key := range keys {
+		fmt.Printf("%s  %s: %s\n", indent, key, paths[key])
+	}
+}
+
 func runServiceAdd(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
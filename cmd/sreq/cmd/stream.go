@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/Priyans-hu/sreq/internal/client"
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/internal/sse"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// ndjsonTypes are line-delimited JSON content types
+var ndjsonTypes = map[string]bool{
+	"application/x-ndjson": true,
+	"application/ndjson":   true,
+	"application/jsonl":    true,
+	"application/json-seq": true,
+}
+
+// streamOutput prints a streamed response as it arrives. With -o json,
+// SSE event data and NDJSON lines are pretty-printed one by one.
+func streamOutput(w io.Writer, format string, red *redact.Redactor) client.StreamFunc {
+	return func(resp *types.Response, body io.Reader) error {
+		if format == "headers" {
+			_, _ = fmt.Fprintf(w, "HTTP %s\n", resp.Status)
+			for key, values := range resp.Headers {
+				for _, value := range values {
+					_, _ = fmt.Fprintf(w, "%s: %s\n", key, red.Header(key, value))
+				}
+			}
+			_, _ = fmt.Fprintln(w)
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(http.Header(resp.Headers).Get("Content-Type"))
+		switch {
+		case mediaType == "text/event-stream" && format != "raw":
+			return streamEvents(w, body, format == "json", red)
+		case ndjsonTypes[mediaType] && format == "json":
+			return streamJSONLines(w, body, red)
+		default:
+			return streamRaw(w, body, red)
+		}
+	}
+}
+
+// streamEvents prints server-sent events, one block per event
+func streamEvents(w io.Writer, body io.Reader, pretty bool, red *redact.Redactor) error {
+	events := sse.NewReader(body)
+	for {
+		ev, err := events.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if ev.Event != "" {
+			_, _ = fmt.Fprintf(w, "event: %s\n", ev.Event)
+		}
+		if ev.ID != "" {
+			_, _ = fmt.Fprintf(w, "id: %s\n", ev.ID)
+		}
+		data := ev.Data
+		if pretty {
+			data = prettyJSON(data)
+		}
+		_, _ = fmt.Fprintf(w, "%s\n\n", red.String(data))
+	}
+}
+
+// streamJSONLines pretty-prints each line of a line-delimited JSON body
+func streamJSONLines(w io.Writer, body io.Reader, red *redact.Redactor) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintln(w, red.String(prettyJSON(string(line))))
+	}
+	return scanner.Err()
+}
+
+// streamRaw copies the body chunk by chunk. A secret split across two
+// chunks is not masked.
+func streamRaw(w io.Writer, body io.Reader, red *redact.Redactor) error {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, werr := io.WriteString(w, red.String(string(buf[:n]))); werr != nil {
+				return werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// prettyJSON indents s if it is JSON, and returns it unchanged otherwise
+func prettyJSON(s string) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return out.String()
+}
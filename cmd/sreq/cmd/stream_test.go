@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestStreamOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:        "sse pretty json",
+			format:      "json",
+			contentType: "text/event-stream; charset=utf-8",
+			body:        "event: update\nid: 7\ndata: {\"n\":1}\n\n",
+			want:        "event: update\nid: 7\n{\n  \"n\": 1\n}\n\n",
+		},
+		{
+			name:        "sse raw passthrough",
+			format:      "raw",
+			contentType: "text/event-stream",
+			body:        "data: {\"n\":1}\n\n",
+			want:        "data: {\"n\":1}\n\n",
+		},
+		{
+			name:        "ndjson lines",
+			format:      "json",
+			contentType: "application/x-ndjson",
+			body:        "{\"a\":1}\n\n{\"b\":2}\n",
+			want:        "{\n  \"a\": 1\n}\n{\n  \"b\": 2\n}\n",
+		},
+		{
+			name:        "secrets masked",
+			format:      "json",
+			contentType: "text/plain",
+			body:        "token=s3cr3t-token",
+			want:        "token=" + redact.Mask,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			resp := &types.Response{Status: "200 OK", Headers: map[string][]string{"Content-Type": {tt.contentType}}}
+
+			fn := streamOutput(&out, tt.format, redact.New("s3cr3t-token"))
+			if err := fn(resp, strings.NewReader(tt.body)); err != nil {
+				t.Fatalf("stream error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/cache"
+	"github.com/Priyans-hu/sreq/internal/client"
+	"github.com/Priyans-hu/sreq/internal/config"
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/internal/history"
+	"github.com/Priyans-hu/sreq/internal/oauth"
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/internal/resolver"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// loadConfig loads the configuration and fills the environment, region,
+// project and app flags from the context (-c or default_context) and the
+// default environment. It returns the context name used.
+func loadConfig() (*types.Config, string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Load context values (from -c flag or default_context)
+	ctxName := contextName
+	if ctxName == "" {
+		ctxName = cfg.DefaultContext
+	}
+
+	// Apply context values as base, then override with explicit CLI flags
+	if ctxName != "" {
+		if ctx, exists := cfg.Contexts[ctxName]; exists {
+			if environment == "" {
+				environment = ctx.Env
+			}
+			if region == "" {
+				region = ctx.Region
+			}
+			if project == "" {
+				project = ctx.Project
+			}
+			if app == "" {
+				app = ctx.App
+			}
+		} else if contextName != "" {
+			// Only error if user explicitly specified a context that doesn't exist
+			return nil, "", sreerrors.ContextNotFound(contextName)
+		}
+	}
+
+	// Use default environment if still not specified
+	if environment == "" {
+		environment = cfg.DefaultEnv
+		if environment == "" {
+			environment = "dev"
+		}
+	}
+
+	return cfg, ctxName, nil
+}
+
+// target is a service's resolved credentials in one environment
+type target struct {
+	creds     *types.ResolvedCredentials
+	cache     *cache.Cache // nil when caching is off
+	useCache  bool
+	configDir string
+
+	// red masks every resolved secret in terminal output and errors
+	red *redact.Redactor
+}
+
+// resolveTarget resolves the credentials of serviceName in environment,
+// from the cache unless --no-cache, else from the providers. --offline
+// requires cached credentials and --insecure turns off TLS verification.
+// The time spent is added to timing.
+func resolveTarget(ctx context.Context, cfg *types.Config, timing *history.Timing) (*target, error) {
+	// Get config directory for cache
+	configDir, _ := config.GetConfigDir()
+
+	// Try to get credentials from cache first (unless --no-cache)
+	var creds *types.ResolvedCredentials
+	var credCache *cache.Cache
+	useCache := !noCache && !cache.IsDisabled() && cache.KeyExists(configDir)
+
+	cacheStart := time.Now()
+	if useCache {
+		credCache, _ = cache.New(cache.Config{ConfigDir: configDir})
+		if credCache != nil {
+			creds, _ = credCache.Get(serviceName, environment)
+			if creds != nil && verbose {
+				fmt.Println("Using cached credentials")
+			}
+		}
+	}
+
+	timing.CacheLookup = msSince(cacheStart)
+
+	// If offline mode, we must have cached credentials
+	if offlineMode {
+		if creds == nil {
+			return nil, fmt.Errorf("no cached credentials found for %s/%s (run 'sreq sync %s' first)",
+				serviceName, environment, environment)
+		}
+	}
+
+	// If no cached credentials, resolve from providers
+	if creds == nil {
+		// Create resolver
+		res, err := resolver.New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resolver: %w", err)
+		}
+
+		// Resolve credentials
+		if verbose {
+			fmt.Println("Resolving credentials from providers...")
+		}
+
+		resolveStart := time.Now()
+		creds, err = res.Resolve(ctx, resolver.ResolveOptions{
+			Service: serviceName,
+			Env:     environment,
+			Region:  region,
+			Project: project,
+			App:     app,
+		})
+		timing.Resolve = msSince(resolveStart)
+		if err != nil {
+			return nil, sreerrors.CredentialResolutionFailed(serviceName, environment, err)
+		}
+
+		// Cache the credentials for next time (unless --no-cache)
+		if useCache && credCache != nil {
+			if err := credCache.Set(serviceName, environment, creds); err != nil && verbose {
+				fmt.Printf("Warning: failed to cache credentials: %v\n", err)
+			}
+		}
+	}
+
+	if insecure {
+		tlsCfg := types.ResolvedTLS{}
+		if creds.TLS != nil {
+			tlsCfg = *creds.TLS
+		}
+		tlsCfg.InsecureSkipVerify = true
+		creds.TLS = &tlsCfg
+	}
+
+	// Mask every resolved secret in terminal output and errors
+	red := redact.ForCredentials(creds)
+	if secretsRevealed() {
+		red.SetReveal(true)
+		fmt.Fprintln(os.Stderr, "Warning: secrets are not redacted in this output (--reveal-secrets)")
+	}
+
+	if verbose {
+		fmt.Printf("  Base URL:  %s\n", creds.BaseURL)
+		if creds.Username != "" {
+			fmt.Printf("  Username:  %s\n", creds.Username)
+			fmt.Printf("  Password:  %s\n", maskPassword(creds.Password))
+		}
+		if creds.APIKey != "" {
+			fmt.Printf("  API Key:   %s\n", maskPassword(creds.APIKey))
+		}
+		if creds.Auth != nil {
+			fmt.Printf("  Auth:      %s\n", creds.Auth.Type)
+		} else if creds.APIKey != "" {
+			fmt.Println("  Warning:   api_key resolved but no 'auth' block is configured; it will not be sent")
+		}
+		if creds.Proxy != nil {
+			fmt.Printf("  Proxy:     %s\n", creds.Proxy.URL)
+		}
+		fmt.Println()
+	}
+
+	return &target{
+		creds:     creds,
+		cache:     credCache,
+		useCache:  useCache,
+		configDir: configDir,
+		red:       red,
+	}, nil
+}
+
+// clientOptions returns the client options every command talking to the
+// target shares: timeout, verbose output, redaction, OAuth2 tokens and the
+// service's retry policy
+func (t *target) clientOptions(cfg *types.Config) ([]client.Option, error) {
+	opts := []client.Option{
+		client.WithTimeout(timeout),
+		client.WithVerbose(verbose),
+		client.WithRedactor(t.red),
+	}
+	if t.creds.Auth != nil && t.creds.Auth.Type == types.AuthOAuth2 {
+		var store oauth.Store
+		if t.useCache && t.cache != nil {
+			store = t.cache
+		}
+		// The token endpoint is reached through the service's proxy and TLS
+		tokenClient, err := client.HTTPClient(t.creds, timeout)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.WithTokenSource(
+			oauth.NewSource(t.creds.Auth, serviceName, environment, store, tokenClient),
+		))
+	}
+	retryPolicy, err := retryPolicyFor(cfg, serviceName, environment)
+	if err != nil {
+		return nil, err
+	}
+	return append(opts, client.WithRetry(retryPolicy)), nil
+}
@@ -0,0 +1,475 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/client"
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/internal/history"
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/internal/websocket"
+	"github.com/Priyans-hu/sreq/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var wsCmd = &cobra.Command{
+	Use:   "ws <path>",
+	Short: "Open a WebSocket to a service",
+	Long: `Open a WebSocket to a service and exchange text messages.
+
+Credentials are resolved exactly like 'run' and sent with the handshake;
+http:// and https:// base URLs are dialled as ws:// and wss://.
+
+In a terminal, each line you type is sent as a message and received
+messages are printed as they arrive; Ctrl-D closes the connection. With
+--send, or when stdin is not a terminal, each line of the input is sent
+as a message and received frames are printed as JSON lines.
+
+Examples:
+  sreq ws /v1/stream -s notifications
+  sreq ws /v1/stream -s notifications --send messages.jsonl --wait 10s
+  echo '{"op":"subscribe","topic":"orders"}' | sreq ws /v1/stream -s notifications --wait 0
+  sreq ws /socket -s chat --subprotocol graphql-transport-ws --token-query access_token`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWS,
+}
+
+var (
+	wsHeaders      []string
+	wsSend         string
+	wsWait         time.Duration
+	wsSubprotocols []string
+	wsTokenQuery   string
+	wsPing         time.Duration
+
+	// wsReplay holds the messages of a replayed session, sent instead of --send
+	wsReplay *string
+)
+
+// wsCloseWait is how long to wait for the server to acknowledge a close
+const wsCloseWait = 2 * time.Second
+
+func init() {
+	rootCmd.AddCommand(wsCmd)
+
+	wsCmd.Flags().StringArrayVarP(&wsHeaders, "header", "H", nil, "Add handshake header (repeatable)")
+	wsCmd.Flags().StringVar(&wsSend, "send", "", "Send each line of a file as a message and print frames as JSON lines (- for stdin)")
+	wsCmd.Flags().DurationVar(&wsWait, "wait", 5*time.Second, "How long to keep reading after the last message is sent (0: until the server closes)")
+	wsCmd.Flags().StringArrayVar(&wsSubprotocols, "subprotocol", nil, "Offer a subprotocol (repeatable, in order of preference)")
+	wsCmd.Flags().StringVar(&wsTokenQuery, "token-query", "", "Send the bearer token as this query parameter instead of the Authorization header")
+	wsCmd.Flags().DurationVar(&wsPing, "ping", 0, "Send a ping at this interval to keep the connection open")
+	wsCmd.Flags().DurationVar(&maxTime, "max-time", 0, "Limit for the whole session")
+	wsCmd.Flags().StringArrayVarP(&queryParams, "query", "q", nil, "Add query parameter key=value, URL-encoded (repeatable)")
+	wsCmd.Flags().StringArrayVarP(&pathParams, "param", "P", nil, "Fill a {name} path parameter with name=value (repeatable)")
+	wsCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Handshake timeout")
+	wsCmd.Flags().BoolVar(&offlineMode, "offline", false, "Use cached credentials only (no provider calls)")
+	wsCmd.Flags().BoolVar(&noCache, "no-cache", false, "Skip cache and fetch fresh credentials")
+	wsCmd.Flags().BoolVarP(&insecure, "insecure", "k", false, "Skip TLS certificate verification (refused in protected environments)")
+}
+
+func runWS(cmd *cobra.Command, args []string) error {
+	startTime := time.Now()
+	path := args[0]
+	if serviceName == "" {
+		return sreerrors.MissingRequiredFlag("service")
+	}
+
+	cfg, ctxName, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if insecure && cfg.IsProtectedEnv(environment) {
+		return sreerrors.InsecureTLSNotAllowed(environment)
+	}
+
+	headers, err := parseHeaders(wsHeaders)
+	if err != nil {
+		return err
+	}
+
+	batch := wsSend != "" || wsReplay != nil || !stdinIsTerminal()
+
+	if verbose {
+		fmt.Println("Request Details:")
+		if ctxName != "" {
+			fmt.Printf("  Context:     %s\n", ctxName)
+		}
+		fmt.Printf("  Service:     %s\n", serviceName)
+		fmt.Printf("  Environment: %s\n", environment)
+		fmt.Printf("  Path:        %s\n", path)
+		fmt.Println()
+	}
+
+	if dryRun {
+		fmt.Println("[DRY RUN] Would open:")
+		fmt.Printf("  ws <base-url>%s\n", path)
+		fmt.Println()
+		fmt.Println("Credentials would be resolved from configured providers.")
+		return nil
+	}
+
+	// Open the messages first so a missing file fails before connecting
+	input, err := openWSMessages()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = input.Close() }()
+
+	// Ctrl-C closes the connection cleanly, keeping its history entry
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if maxTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, maxTime)
+		defer cancel()
+	}
+
+	var timing history.Timing
+	target, err := resolveTarget(ctx, cfg, &timing)
+	if err != nil {
+		return err
+	}
+	creds, red := target.creds, target.red
+	if creds.BaseURL == "" {
+		return sreerrors.BaseURLMissing(serviceName, environment)
+	}
+
+	contextVars := map[string]string{
+		"service": serviceName,
+		"env":     environment,
+		"region":  region,
+		"project": project,
+		"app":     app,
+	}
+	vars, err := pathVars(creds.Custom, contextVars, pathParams)
+	if err != nil {
+		return err
+	}
+	redactPathValues(red, path, creds.Custom, vars)
+	if path, err = renderPath(path, vars); err != nil {
+		return err
+	}
+	if path, err = appendQuery(path, queryParams); err != nil {
+		return err
+	}
+
+	// The handshake is HTTP/1.1, so the service's protocol pin is not used
+	clientOpts, err := target.clientOptions(cfg)
+	if err != nil {
+		return err
+	}
+	if wsTokenQuery != "" {
+		clientOpts = append(clientOpts, client.WithTokenQuery(wsTokenQuery))
+	}
+
+	req := &types.Request{
+		Method:      history.MethodWS,
+		Path:        path,
+		Service:     serviceName,
+		Environment: environment,
+		Headers:     headers,
+	}
+	conn, resp, err := websocket.Dial(ctx, client.New(clientOpts...), req, creds, wsSubprotocols)
+	if resp != nil && resp.Timing != nil {
+		timing.SetRequest(resp.Timing)
+	}
+
+	s := &wsSession{conn: conn, red: red, out: os.Stdout, jsonLines: batch}
+	switch {
+	case err != nil:
+	case batch:
+		// --wait 0 reads until the server closes
+		wait := wsWait
+		if wait == 0 {
+			wait = -1
+		}
+		err = s.run(ctx, input, wait)
+	default:
+		fmt.Fprintf(os.Stderr, "Connected to %s", red.URL(websocket.URL(creds.BaseURL)+path))
+		if conn.Subprotocol != "" {
+			fmt.Fprintf(os.Stderr, " (%s)", conn.Subprotocol)
+		}
+		fmt.Fprintln(os.Stderr, ". Type a message and press Enter; Ctrl-D closes.")
+		err = s.run(ctx, input, 0)
+	}
+	duration := time.Since(startTime).Milliseconds()
+
+	if os.Getenv("SREQ_NO_HISTORY") != "1" {
+		if resp != nil && conn != nil {
+			resp.Body, resp.Size, resp.Truncated = s.sample()
+			resp.Streamed = true
+		}
+		req.Body = strings.Join(s.sent, "\n")
+		histRed := redact.ForCredentials(creds)
+		histRed.Add(red.Secrets()...)
+		saveHistory(req, creds.BaseURL, resp, err, duration, timing, histRed)
+	}
+
+	// Once connected, the close is already printed; usage would only bury it
+	if cmd != nil && conn != nil {
+		cmd.SilenceUsage = true
+	}
+
+	var he *websocket.HandshakeError
+	switch {
+	case errors.As(err, &he):
+		return red.Error(sreerrors.WebSocketUpgradeRefused(red.URL(websocket.URL(creds.BaseURL)+path),
+			he.Response.Status, truncate(strings.TrimSpace(string(he.Response.Body)), 200)))
+	case conn == nil && err != nil:
+		return red.Error(sreerrors.RequestFailed(red.URL(websocket.URL(creds.BaseURL)+path), err))
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("websocket closed after --max-time %s", maxTime)
+	case err != nil:
+		return red.Error(err)
+	}
+	return nil
+}
+
+// wsSession exchanges messages over one connection and keeps what history
+// records of it
+type wsSession struct {
+	conn      *websocket.Conn
+	red       *redact.Redactor
+	out       io.Writer
+	jsonLines bool // print frames as JSON lines rather than for a terminal
+
+	sent []string
+
+	mu       sync.Mutex
+	received []byte // sample of the text received, one message per line
+	size     int64
+}
+
+// run sends each line of input as a message while printing the messages
+// that arrive. Once input ends it keeps reading for wait, or until the
+// server closes if wait is negative, then closes the connection.
+func (s *wsSession) run(ctx context.Context, input io.Reader, wait time.Duration) error {
+	readDone := s.read()
+	stopPing := s.keepAlive()
+	defer stopPing()
+
+	// Reading stdin cannot be interrupted, so it runs on its own
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(input)
+		scanner.Buffer(make([]byte, 0, 4096), websocket.MaxMessageSize)
+		for scanner.Scan() {
+			lines <- strings.TrimRight(scanner.Text(), "\r")
+		}
+	}()
+
+	for lines != nil {
+		select {
+		case err := <-readDone:
+			return serverClosed(err)
+		case <-ctx.Done():
+			return s.finish(readDone, nil)
+		case line, ok := <-lines:
+			if !ok {
+				lines = nil
+				break
+			}
+			if line == "" {
+				continue
+			}
+			if err := s.send(line); err != nil {
+				return s.finish(readDone, err)
+			}
+		}
+	}
+
+	var linger <-chan time.Time
+	if wait >= 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		linger = timer.C
+	}
+	select {
+	case err := <-readDone:
+		return serverClosed(err)
+	case <-linger:
+	case <-ctx.Done():
+	}
+	return s.finish(readDone, nil)
+}
+
+// read prints messages until the connection ends, and returns the final
+// error on the channel
+func (s *wsSession) read() <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := s.conn.ReadMessage()
+			if err != nil {
+				var ce *websocket.CloseError
+				if errors.As(err, &ce) {
+					s.printClose(ce)
+				}
+				done <- err
+				return
+			}
+			s.record(msg)
+			s.print(msg)
+		}
+	}()
+	return done
+}
+
+// send sends a text message
+func (s *wsSession) send(msg string) error {
+	if err := s.conn.WriteMessage(websocket.OpText, []byte(msg)); err != nil {
+		return err
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+// keepAlive pings the server every --ping until the returned func is called
+func (s *wsSession) keepAlive() func() {
+	if wsPing <= 0 {
+		return func() {}
+	}
+	ticker := time.NewTicker(wsPing)
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				_ = s.conn.Ping(nil)
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(stop)
+	}
+}
+
+// finish starts the closing handshake, waits for the server to answer it
+// and closes the connection. It returns err, else the read error.
+func (s *wsSession) finish(readDone <-chan error, err error) error {
+	_ = s.conn.CloseWithReason(websocket.CloseNormal, "")
+	select {
+	case readErr := <-readDone:
+		if err == nil {
+			err = serverClosed(readErr)
+		}
+	case <-time.After(wsCloseWait):
+	}
+	_ = s.conn.Close()
+	return err
+}
+
+// serverClosed maps the end of the read loop to the command's result: a
+// normal close is success
+func serverClosed(err error) error {
+	var ce *websocket.CloseError
+	if errors.As(err, &ce) && (ce.Code == websocket.CloseNormal || ce.Code == websocket.CloseNoStatus) {
+		return nil
+	}
+	return err
+}
+
+// record keeps a sample of the received text for history
+func (s *wsSession) record(msg *websocket.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.size += int64(len(msg.Data))
+	if room := client.StreamSampleSize - len(s.received); room > 0 {
+		line := msg.Data
+		if msg.Type == websocket.OpBinary {
+			line = []byte(base64.StdEncoding.EncodeToString(msg.Data))
+		}
+		s.received = append(s.received, line[:min(len(line), room)]...)
+		s.received = append(s.received, '\n')
+	}
+}
+
+// sample returns the received sample, the total size and whether the
+// sample is cut short
+func (s *wsSession) sample() ([]byte, int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.received, s.size, len(s.received) >= client.StreamSampleSize
+}
+
+// wsFrame is a received frame as printed in batch mode
+type wsFrame struct {
+	Time   string `json:"time"`
+	Type   string `json:"type"`
+	Data   string `json:"data,omitempty"` // base64 for binary messages
+	Code   int    `json:"code,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// print prints a received message
+func (s *wsSession) print(msg *websocket.Message) {
+	data := s.red.String(string(msg.Data))
+	if msg.Type == websocket.OpBinary {
+		data = base64.StdEncoding.EncodeToString(msg.Data)
+	}
+
+	if s.jsonLines {
+		s.printJSON(wsFrame{Type: msg.Type.String(), Data: data})
+		return
+	}
+	if msg.Type == websocket.OpBinary {
+		_, _ = fmt.Fprintf(s.out, "< [binary, %d bytes] %s\n", len(msg.Data), data)
+		return
+	}
+	_, _ = fmt.Fprintf(s.out, "< %s\n", data)
+}
+
+// printClose reports how the connection ended
+func (s *wsSession) printClose(ce *websocket.CloseError) {
+	reason := s.red.String(ce.Reason)
+	if s.jsonLines {
+		s.printJSON(wsFrame{Type: websocket.OpClose.String(), Code: ce.Code, Reason: reason})
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Connection closed: %d %s\n", ce.Code, reason)
+}
+
+// printJSON prints a frame as one line of JSON
+func (s *wsSession) printJSON(f wsFrame) {
+	f.Time = time.Now().Format(time.RFC3339Nano)
+	line, _ := json.Marshal(f)
+	_, _ = fmt.Fprintf(s.out, "%s\n", line)
+}
+
+// openWSMessages opens the lines to send: a replayed session, the
+// --send file, or stdin
+func openWSMessages() (io.ReadCloser, error) {
+	switch {
+	case wsReplay != nil:
+		return io.NopCloser(strings.NewReader(*wsReplay)), nil
+	case wsSend == "" || wsSend == "-":
+		return io.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(wsSend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open messages file: %w", err)
+	}
+	return f, nil
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal
+func stdinIsTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,330 @@
+// Package bench sends a request repeatedly from concurrent workers and
+// summarises throughput, status codes and latency.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Options controls a run. It stops after Requests requests or when
+// Duration has passed, whichever comes first; at least one must be set.
+type Options struct {
+	Concurrency int
+	Requests    int
+	Duration    time.Duration
+
+	// Rate caps requests per second across all workers, 0 for no cap
+	Rate float64
+}
+
+// MaxRate is the highest Rate accepted
+const MaxRate = 1e6
+
+// Validate checks the options
+func (o Options) Validate() error {
+	switch {
+	case o.Concurrency < 1:
+		return fmt.Errorf("concurrency must be at least 1, got %d", o.Concurrency)
+	case o.Requests < 0:
+		return fmt.Errorf("requests must not be negative, got %d", o.Requests)
+	case o.Duration < 0:
+		return fmt.Errorf("duration must not be negative, got %s", o.Duration)
+	case o.Rate < 0 || o.Rate > MaxRate || math.IsNaN(o.Rate):
+		return fmt.Errorf("rate must be between 0 and %g, got %g", float64(MaxRate), o.Rate)
+	case o.Requests == 0 && o.Duration == 0:
+		return fmt.Errorf("set a number of requests or a duration")
+	}
+	return nil
+}
+
+// Func sends one request and returns its status code
+type Func func(ctx context.Context) (status int, err error)
+
+// result is the outcome of one request
+type result struct {
+	status  int
+	err     error
+	latency time.Duration
+}
+
+// Run calls fn from opts.Concurrency workers until the run ends. Requests
+// in flight when Duration ends are allowed to finish; cancelling ctx stops
+// them and reports what completed.
+func Run(ctx context.Context, opts Options, fn Func) (*Report, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	// stop ends issuing new requests, ctx ends those in flight too
+	stop, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if opts.Duration > 0 {
+		var cancelTimeout context.CancelFunc
+		stop, cancelTimeout = context.WithTimeout(stop, opts.Duration)
+		defer cancelTimeout()
+	}
+
+	var tick <-chan time.Time
+	if opts.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	workers := opts.Concurrency
+	if opts.Requests > 0 && opts.Requests < workers {
+		workers = opts.Requests
+	}
+
+	var issued atomic.Int64
+	results := make([][]result, workers)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for {
+				if tick != nil {
+					select {
+					case <-tick:
+					case <-stop.Done():
+						return
+					}
+				}
+				if stop.Err() != nil {
+					return
+				}
+				if opts.Requests > 0 && issued.Add(1) > int64(opts.Requests) {
+					return
+				}
+
+				t := time.Now()
+				status, err := fn(ctx)
+				if err != nil && ctx.Err() != nil {
+					return // interrupted, not a failure of the service
+				}
+				results[w] = append(results[w], result{status: status, err: err, latency: time.Since(t)})
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	var all []result
+	for _, r := range results {
+		all = append(all, r...)
+	}
+	report := summarise(all, time.Since(start))
+	report.Concurrency = workers
+	report.Interrupted = ctx.Err() != nil
+	return report, nil
+}
+
+// Report summarises a run
+type Report struct {
+	Requests    int            `json:"requests"`
+	Errors      int            `json:"errors"`
+	Concurrency int            `json:"concurrency"`
+	DurationMs  float64        `json:"duration_ms"`
+	Throughput  float64        `json:"requests_per_sec"`
+	Statuses    map[int]int    `json:"statuses"`
+	ErrorCounts map[string]int `json:"error_messages,omitempty"`
+	Latency     Latency        `json:"latency_ms"`
+	Histogram   []Bucket       `json:"histogram,omitempty"`
+	Interrupted bool           `json:"interrupted,omitempty"`
+}
+
+// Latency holds latency statistics in milliseconds, over the requests
+// that got a response
+type Latency struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// Bucket counts the responses no slower than UpTo milliseconds, and
+// slower than the bucket before it
+type Bucket struct {
+	UpTo  float64 `json:"le"`
+	Count int     `json:"count"`
+}
+
+// histogramBuckets is the number of histogram buckets
+const histogramBuckets = 10
+
+func summarise(results []result, elapsed time.Duration) *Report {
+	r := &Report{
+		Requests:   len(results),
+		DurationMs: ms(elapsed),
+		Statuses:   map[int]int{},
+	}
+	if elapsed > 0 {
+		r.Throughput = float64(len(results)) / elapsed.Seconds()
+	}
+
+	var latencies []time.Duration
+	for _, res := range results {
+		if res.err != nil {
+			r.Errors++
+			if r.ErrorCounts == nil {
+				r.ErrorCounts = map[string]int{}
+			}
+			r.ErrorCounts[errorKey(res.err)]++
+			continue
+		}
+		r.Statuses[res.status]++
+		latencies = append(latencies, res.latency)
+	}
+	if len(latencies) == 0 {
+		return r
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+	r.Latency = Latency{
+		Min:  ms(latencies[0]),
+		Mean: ms(total / time.Duration(len(latencies))),
+		P50:  ms(percentile(latencies, 50)),
+		P90:  ms(percentile(latencies, 90)),
+		P99:  ms(percentile(latencies, 99)),
+		Max:  ms(latencies[len(latencies)-1]),
+	}
+	r.Histogram = histogram(latencies)
+	return r
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// histogram spreads sorted latencies over equal-width buckets from the
+// fastest to the slowest
+func histogram(sorted []time.Duration) []Bucket {
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	width := (hi - lo) / histogramBuckets
+	if width <= 0 {
+		return []Bucket{{UpTo: ms(hi), Count: len(sorted)}}
+	}
+
+	buckets := make([]Bucket, histogramBuckets)
+	for i := range buckets {
+		buckets[i].UpTo = ms(lo + width*time.Duration(i+1))
+	}
+	buckets[len(buckets)-1].UpTo = ms(hi)
+	for _, l := range sorted {
+		i := int((l - lo) / width)
+		if i >= histogramBuckets {
+			i = histogramBuckets - 1
+		}
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+// errorKey groups errors by their message, without the per-request detail
+// of a wrapped URL error
+func errorKey(err error) string {
+	for {
+		u := errors.Unwrap(err)
+		if u == nil || !strings.Contains(err.Error(), "://") {
+			return err.Error()
+		}
+		err = u
+	}
+}
+
+// ms converts d to milliseconds, rounded to microseconds
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// WriteText prints the report for a terminal
+func (r *Report) WriteText(w io.Writer) {
+	p := func(format string, a ...any) { _, _ = fmt.Fprintf(w, format, a...) }
+
+	p("Summary:\n")
+	p("  Requests:    %d", r.Requests)
+	if r.Errors > 0 {
+		p(" (%d errors)", r.Errors)
+	}
+	if r.Interrupted {
+		p(" (interrupted)")
+	}
+	p("\n")
+	p("  Concurrency: %d\n", r.Concurrency)
+	p("  Duration:    %s\n", formatMs(r.DurationMs))
+	p("  Throughput:  %.1f req/s\n", r.Throughput)
+
+	if len(r.Statuses) > 0 {
+		p("\nStatus codes:\n")
+		codes := make([]int, 0, len(r.Statuses))
+		for code := range r.Statuses {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			p("  %d  %d\n", code, r.Statuses[code])
+		}
+	}
+
+	if len(r.ErrorCounts) > 0 {
+		p("\nErrors:\n")
+		msgs := make([]string, 0, len(r.ErrorCounts))
+		for msg := range r.ErrorCounts {
+			msgs = append(msgs, msg)
+		}
+		sort.Slice(msgs, func(i, j int) bool { return r.ErrorCounts[msgs[i]] > r.ErrorCounts[msgs[j]] })
+		for _, msg := range msgs {
+			p("  %d  %s\n", r.ErrorCounts[msg], msg)
+		}
+	}
+
+	if len(r.Histogram) == 0 {
+		return
+	}
+	p("\nLatency:\n")
+	for _, s := range []struct {
+		name string
+		v    float64
+	}{{"min", r.Latency.Min}, {"mean", r.Latency.Mean}, {"p50", r.Latency.P50}, {"p90", r.Latency.P90}, {"p99", r.Latency.P99}, {"max", r.Latency.Max}} {
+		p("  %-5s %10s\n", s.name, formatMs(s.v))
+	}
+
+	p("\nHistogram:\n")
+	most := 0
+	for _, b := range r.Histogram {
+		most = max(most, b.Count)
+	}
+	for _, b := range r.Histogram {
+		bar := strings.Repeat("■", int(math.Ceil(float64(b.Count)/float64(most)*40)))
+		p("  %10s [%d]\t%s\n", formatMs(b.UpTo), b.Count, bar)
+	}
+}
+
+// formatMs formats milliseconds with a unit suited to their size
+func formatMs(v float64) string {
+	if v >= 1000 {
+		return fmt.Sprintf("%.2fs", v/1000)
+	}
+	return fmt.Sprintf("%.2fms", v)
+}
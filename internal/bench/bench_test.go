@@ -0,0 +1,171 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun_Requests(t *testing.T) {
+	var calls atomic.Int64
+	fn := func(ctx context.Context) (int, error) {
+		n := calls.Add(1)
+		switch {
+		case n%10 == 0:
+			return 0, errors.New("connection reset")
+		case n%5 == 0:
+			return 503, nil
+		}
+		return 200, nil
+	}
+
+	r, err := Run(context.Background(), Options{Concurrency: 4, Requests: 100}, fn)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if calls.Load() != 100 || r.Requests != 100 {
+		t.Errorf("calls = %d, Requests = %d; want 100", calls.Load(), r.Requests)
+	}
+	if r.Errors != 10 || r.ErrorCounts["connection reset"] != 10 {
+		t.Errorf("Errors = %d, ErrorCounts = %v; want 10 connection resets", r.Errors, r.ErrorCounts)
+	}
+	if r.Statuses[200] != 80 || r.Statuses[503] != 10 {
+		t.Errorf("Statuses = %v, want 80 200s and 10 503s", r.Statuses)
+	}
+	if r.Concurrency != 4 || r.Throughput <= 0 {
+		t.Errorf("Concurrency = %d, Throughput = %g", r.Concurrency, r.Throughput)
+	}
+}
+
+func TestRun_FewerRequestsThanWorkers(t *testing.T) {
+	r, err := Run(context.Background(), Options{Concurrency: 20, Requests: 3}, func(ctx context.Context) (int, error) { return 204, nil })
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if r.Requests != 3 || r.Concurrency != 3 {
+		t.Errorf("Requests = %d, Concurrency = %d; want 3, 3", r.Requests, r.Concurrency)
+	}
+}
+
+func TestRun_DurationAndRate(t *testing.T) {
+	r, err := Run(context.Background(), Options{Concurrency: 5, Duration: 300 * time.Millisecond, Rate: 50}, func(ctx context.Context) (int, error) {
+		return 200, nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	// 50/s for 0.3s is about 15, with slack for slow machines
+	if r.Requests < 5 || r.Requests > 20 {
+		t.Errorf("Requests = %d, want about 15", r.Requests)
+	}
+}
+
+func TestRun_DurationLetsInFlightFinish(t *testing.T) {
+	r, err := Run(context.Background(), Options{Concurrency: 2, Duration: 50 * time.Millisecond}, func(ctx context.Context) (int, error) {
+		select {
+		case <-time.After(80 * time.Millisecond):
+			return 200, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if r.Requests != 2 || r.Errors != 0 {
+		t.Errorf("Requests = %d, Errors = %d; want 2 completed", r.Requests, r.Errors)
+	}
+}
+
+func TestRun_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int64
+	r, err := Run(ctx, Options{Concurrency: 2, Requests: 1000}, func(ctx context.Context) (int, error) {
+		if calls.Add(1) == 10 {
+			cancel()
+		}
+		select {
+		case <-time.After(time.Millisecond):
+			return 200, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !r.Interrupted || r.Errors != 0 || r.Requests >= 1000 {
+		t.Errorf("Interrupted = %v, Errors = %d, Requests = %d", r.Interrupted, r.Errors, r.Requests)
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	for _, o := range []Options{
+		{Concurrency: 0, Requests: 1},
+		{Concurrency: 1},
+		{Concurrency: 1, Requests: -1},
+		{Concurrency: 1, Requests: 1, Rate: -1},
+		{Concurrency: 1, Requests: 1, Rate: 2e9}, // would tick every 0ns
+		{Concurrency: 1, Requests: 1, Rate: math.NaN()},
+	} {
+		if err := o.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", o)
+		}
+	}
+
+	if err := (Options{Concurrency: 1, Requests: 1, Rate: MaxRate}).Validate(); err != nil {
+		t.Errorf("Validate() at MaxRate error = %v", err)
+	}
+}
+
+func TestSummarise(t *testing.T) {
+	var results []result
+	for i := 1; i <= 100; i++ {
+		results = append(results, result{status: 200, latency: time.Duration(i) * time.Millisecond})
+	}
+	r := summarise(results, time.Second)
+
+	want := Latency{Min: 1, Mean: 50.5, P50: 50, P90: 90, P99: 99, Max: 100}
+	if r.Latency != want {
+		t.Errorf("Latency = %+v, want %+v", r.Latency, want)
+	}
+	if r.Throughput != 100 {
+		t.Errorf("Throughput = %g, want 100", r.Throughput)
+	}
+
+	if len(r.Histogram) != histogramBuckets {
+		t.Fatalf("Histogram has %d buckets, want %d", len(r.Histogram), histogramBuckets)
+	}
+	total := 0
+	for _, b := range r.Histogram {
+		total += b.Count
+	}
+	if total != 100 || r.Histogram[len(r.Histogram)-1].UpTo != 100 {
+		t.Errorf("Histogram = %+v, want 100 responses up to 100ms", r.Histogram)
+	}
+}
+
+func TestReport_WriteText(t *testing.T) {
+	results := []result{
+		{status: 200, latency: 10 * time.Millisecond},
+		{status: 200, latency: 20 * time.Millisecond},
+		{status: 500, latency: 1500 * time.Millisecond},
+		{err: errors.New("timeout")},
+	}
+	r := summarise(results, 2*time.Second)
+	r.Concurrency = 2
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+	out := buf.String()
+	for _, want := range []string{"Requests:    4 (1 errors)", "Throughput:  2.0 req/s", "200  2", "500  1", "1  timeout", "p99", "1.50s", "Histogram:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -19,6 +19,15 @@ const (
 
 	// CacheFileExtension is the extension for cache files
 	CacheFileExtension = ".enc"
+
+	// tokenFileSuffix marks cache files holding OAuth2 tokens
+	tokenFileSuffix = ".token"
+
+	// cookieFileSuffix marks cache files holding cookie jars
+	cookieFileSuffix = ".cookies"
+
+	// CookieSessionTTL is how long a jar of session cookies is kept
+	CookieSessionTTL = 24 * time.Hour
 )
 
 // Entry represents a cached credential entry
@@ -27,7 +36,9 @@ type Entry struct {
 	Env         string                   `json:"env"`
 	CachedAt    time.Time                `json:"cached_at"`
 	TTLSeconds  int                      `json:"ttl_seconds"`
-	Credentials *types.ResolvedCredentials `json:"credentials"`
+	Credentials *types.ResolvedCredentials `json:"credentials,omitempty"`
+	Token       *types.OAuthToken          `json:"token,omitempty"`
+	Cookies     []types.Cookie             `json:"cookies,omitempty"`
 }
 
 // IsExpired checks if the cache entry has expired
@@ -89,10 +100,146 @@ func (c *Cache) cacheFilePath(service, env string) string {
 	return filepath.Join(c.cacheDir, env, filename)
 }
 
+// tokenFilePath returns the path to the OAuth2 token file for a service/env.
+// key identifies the token endpoint and client, so a changed oauth2 config
+// does not reuse a token issued for the old one.
+func (c *Cache) tokenFilePath(service, env, key string) string {
+	filename := fmt.Sprintf("%s-%s.%s%s%s", service, env, key, tokenFileSuffix, CacheFileExtension)
+	return filepath.Join(c.cacheDir, env, filename)
+}
+
+// tokenFiles returns every OAuth2 token file of a service/env, whatever
+// its key, including files from before keys were added
+func (c *Cache) tokenFiles(service, env string) []string {
+	dir := filepath.Join(c.cacheDir, env)
+	files, _ := filepath.Glob(filepath.Join(dir, fmt.Sprintf("%s-%s.*%s%s", service, env, tokenFileSuffix, CacheFileExtension)))
+	return append(files, filepath.Join(dir, fmt.Sprintf("%s-%s%s%s", service, env, tokenFileSuffix, CacheFileExtension)))
+}
+
+// cookieFilePath returns the path to the cookie jar file for a service/env
+func (c *Cache) cookieFilePath(service, env string) string {
+	filename := fmt.Sprintf("%s-%s%s%s", service, env, cookieFileSuffix, CacheFileExtension)
+	return filepath.Join(c.cacheDir, env, filename)
+}
+
 // Get retrieves cached credentials for a service/env
 func (c *Cache) Get(service, env string) (*types.ResolvedCredentials, error) {
-	path := c.cacheFilePath(service, env)
+	entry, err := c.read(c.cacheFilePath(service, env))
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return entry.Credentials, nil
+}
+
+// Set caches credentials for a service/env
+func (c *Cache) Set(service, env string, creds *types.ResolvedCredentials) error {
+	return c.write(c.cacheFilePath(service, env), Entry{
+		Service:     service,
+		Env:         env,
+		CachedAt:    time.Now(),
+		TTLSeconds:  int(c.ttl.Seconds()),
+		Credentials: creds,
+	})
+}
+
+// GetToken retrieves a cached OAuth2 token for a service/env.
+// The access token may be expired if a refresh token is still usable.
+func (c *Cache) GetToken(service, env, key string) (*types.OAuthToken, error) {
+	entry, err := c.read(c.tokenFilePath(service, env, key))
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return entry.Token, nil
+}
+
+// SetToken caches an OAuth2 token for a service/env. The entry lives until
+// the access token expires, or for at least the cache TTL when it carries
+// a refresh token.
+func (c *Cache) SetToken(service, env, key string, token *types.OAuthToken) error {
+	now := time.Now()
+	ttl := c.ttl
+	if !token.Expiry.IsZero() {
+		ttl = token.Expiry.Sub(now)
+		if token.RefreshToken != "" && ttl < c.ttl {
+			ttl = c.ttl
+		}
+	}
+	if ttl <= 0 {
+		return nil
+	}
+
+	// Tokens for an older oauth2 config of the service are no use
+	for _, path := range c.tokenFiles(service, env) {
+		_ = os.Remove(path)
+	}
+	return c.write(c.tokenFilePath(service, env, key), Entry{
+		Service:    service,
+		Env:        env,
+		CachedAt:   now,
+		TTLSeconds: int(ttl.Seconds()),
+		Token:      token,
+	})
+}
+
+// GetCookies retrieves the stored cookie jar for a service/env, without
+// expired cookies
+func (c *Cache) GetCookies(service, env string) ([]types.Cookie, error) {
+	entry, err := c.read(c.cookieFilePath(service, env))
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var cookies []types.Cookie
+	for _, cookie := range entry.Cookies {
+		if !cookie.Expired(now) {
+			cookies = append(cookies, cookie)
+		}
+	}
+	return cookies, nil
+}
 
+// SetCookies stores the cookie jar for a service/env. The entry lives until
+// its last cookie expires, and at least CookieSessionTTL for session
+// cookies. An empty jar deletes the entry.
+func (c *Cache) SetCookies(service, env string, cookies []types.Cookie) error {
+	if len(cookies) == 0 {
+		return c.DeleteCookies(service, env)
+	}
+
+	now := time.Now()
+	ttl := time.Duration(0)
+	for _, cookie := range cookies {
+		lifetime := CookieSessionTTL
+		if !cookie.Expires.IsZero() {
+			lifetime = cookie.Expires.Sub(now)
+		}
+		ttl = max(ttl, lifetime)
+	}
+	if ttl <= 0 {
+		return c.DeleteCookies(service, env)
+	}
+
+	return c.write(c.cookieFilePath(service, env), Entry{
+		Service:    service,
+		Env:        env,
+		CachedAt:   now,
+		TTLSeconds: int(ttl.Seconds()),
+		Cookies:    cookies,
+	})
+}
+
+// DeleteCookies removes the cookie jar for a service/env
+func (c *Cache) DeleteCookies(service, env string) error {
+	if err := os.Remove(c.cookieFilePath(service, env)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete cookies: %w", err)
+	}
+	return nil
+}
+
+// read loads and decrypts a cache entry. Missing, corrupted and expired
+// entries are reported as a miss (nil, nil).
+func (c *Cache) read(path string) (*Entry, error) {
 	// Read encrypted file
 	ciphertext, err := os.ReadFile(path)
 	if err != nil {
@@ -124,19 +271,11 @@ func (c *Cache) Get(service, env string) (*types.ResolvedCredentials, error) {
 		return nil, nil
 	}
 
-	return entry.Credentials, nil
+	return &entry, nil
 }
 
-// Set caches credentials for a service/env
-func (c *Cache) Set(service, env string, creds *types.ResolvedCredentials) error {
-	entry := Entry{
-		Service:     service,
-		Env:         env,
-		CachedAt:    time.Now(),
-		TTLSeconds:  int(c.ttl.Seconds()),
-		Credentials: creds,
-	}
-
+// write encrypts and stores a cache entry
+func (c *Cache) write(path string, entry Entry) error {
 	// Marshal
 	plaintext, err := json.Marshal(entry)
 	if err != nil {
@@ -150,7 +289,6 @@ func (c *Cache) Set(service, env string, creds *types.ResolvedCredentials) error
 	}
 
 	// Ensure env directory exists
-	path := c.cacheFilePath(service, env)
 	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
@@ -163,11 +301,12 @@ func (c *Cache) Set(service, env string, creds *types.ResolvedCredentials) error
 	return nil
 }
 
-// Delete removes cached credentials for a service/env
+// Delete removes cached credentials and tokens for a service/env
 func (c *Cache) Delete(service, env string) error {
-	path := c.cacheFilePath(service, env)
-	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete cache: %w", err)
+	for _, path := range append([]string{c.cacheFilePath(service, env)}, c.tokenFiles(service, env)...) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete cache: %w", err)
+		}
 	}
 	return nil
 }
@@ -208,6 +347,8 @@ type EntryInfo struct {
 	ExpiresAt time.Time
 	Size      int64
 	Expired   bool
+	Token     bool // OAuth2 token rather than credentials
+	Cookies   int  // number of cookies in a cookie jar entry
 }
 
 // Status returns cache status
@@ -255,6 +396,8 @@ func (c *Cache) Status() (*Status, error) {
 			ExpiresAt: entry.ExpiresAt(),
 			Size:      info.Size(),
 			Expired:   entry.IsExpired(),
+			Token:     entry.Token != nil,
+			Cookies:   len(entry.Cookies),
 		})
 
 		return nil
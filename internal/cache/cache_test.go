@@ -130,6 +130,123 @@ func TestCache_Expiry(t *testing.T) {
 	}
 }
 
+func TestCache_Token(t *testing.T) {
+	c, tmpDir := setupTestCache(t)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	token := &types.OAuthToken{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(5 * time.Minute).Round(time.Second),
+	}
+	if err := c.SetToken("orders", "dev", "k1", token); err != nil {
+		t.Fatalf("SetToken failed: %v", err)
+	}
+
+	got, err := c.GetToken("orders", "dev", "k1")
+	if err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	if got == nil || got.AccessToken != "access" || got.RefreshToken != "refresh" || !got.Expiry.Equal(token.Expiry) {
+		t.Errorf("GetToken() = %+v, want %+v", got, token)
+	}
+
+	// Tokens live in their own file and don't shadow credentials
+	if creds, _ := c.Get("orders", "dev"); creds != nil {
+		t.Errorf("Get() = %+v, want nil", creds)
+	}
+
+	status, err := c.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.EntryCount != 1 || !status.Entries[0].Token {
+		t.Errorf("Status entries = %+v, want one token entry", status.Entries)
+	}
+	// Refresh token keeps the entry for the cache TTL, not just 5 minutes
+	if ttl := time.Until(status.Entries[0].ExpiresAt); ttl < 50*time.Minute {
+		t.Errorf("token entry TTL = %v, want about 1h", ttl)
+	}
+
+	// A token cached for another oauth2 config is not returned, and is
+	// replaced when the new one is stored
+	if got, _ := c.GetToken("orders", "dev", "k2"); got != nil {
+		t.Errorf("GetToken() with another key = %+v, want nil", got)
+	}
+	if err := c.SetToken("orders", "dev", "k2", token); err != nil {
+		t.Fatalf("SetToken failed: %v", err)
+	}
+	if got, _ := c.GetToken("orders", "dev", "k1"); got != nil {
+		t.Error("token for the old key should be removed")
+	}
+
+	if err := c.Delete("orders", "dev"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if got, _ := c.GetToken("orders", "dev", "k2"); got != nil {
+		t.Error("token should be deleted")
+	}
+}
+
+func TestCache_SetToken_ExpiredIsNotStored(t *testing.T) {
+	c, tmpDir := setupTestCache(t)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	token := &types.OAuthToken{AccessToken: "a", Expiry: time.Now().Add(-time.Minute)}
+	if err := c.SetToken("orders", "dev", "k1", token); err != nil {
+		t.Fatalf("SetToken failed: %v", err)
+	}
+	if got, _ := c.GetToken("orders", "dev", "k1"); got != nil {
+		t.Errorf("GetToken() = %+v, want nil", got)
+	}
+}
+
+func TestCache_Cookies(t *testing.T) {
+	c, tmpDir := setupTestCache(t)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	cookies := []types.Cookie{
+		{Name: "session", Value: "abc", Domain: "api.example.com", HostOnly: true, Path: "/"},
+		{Name: "pref", Value: "dark", Domain: "example.com", Path: "/", Expires: time.Now().Add(48 * time.Hour).Round(time.Second)},
+	}
+	if err := c.SetCookies("orders", "dev", cookies); err != nil {
+		t.Fatalf("SetCookies failed: %v", err)
+	}
+
+	got, err := c.GetCookies("orders", "dev")
+	if err != nil {
+		t.Fatalf("GetCookies failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Value != "abc" || !got[1].Expires.Equal(cookies[1].Expires) {
+		t.Errorf("GetCookies() = %+v, want %+v", got, cookies)
+	}
+
+	// Cookie jars don't shadow credentials
+	if creds, _ := c.Get("orders", "dev"); creds != nil {
+		t.Errorf("Get() = %+v, want nil", creds)
+	}
+
+	status, err := c.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.EntryCount != 1 || status.Entries[0].Cookies != 2 {
+		t.Errorf("Status entries = %+v, want one jar of 2 cookies", status.Entries)
+	}
+	// The jar lives as long as its longest-lived cookie
+	if ttl := time.Until(status.Entries[0].ExpiresAt); ttl < 47*time.Hour {
+		t.Errorf("cookie entry TTL = %v, want about 48h", ttl)
+	}
+
+	// An empty jar removes the entry
+	if err := c.SetCookies("orders", "dev", nil); err != nil {
+		t.Fatalf("SetCookies failed: %v", err)
+	}
+	if got, _ := c.GetCookies("orders", "dev"); got != nil {
+		t.Errorf("GetCookies() = %+v, want nil", got)
+	}
+}
+
 func TestCache_Delete(t *testing.T) {
 	c, tmpDir := setupTestCache(t)
 	defer func() { _ = os.RemoveAll(tmpDir) }()
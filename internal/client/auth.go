@@ -0,0 +1,267 @@
+package client
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/jwt"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// applyAuth attaches credentials to a request according to the resolved
+// auth scheme. Digest auth needs a server challenge first, so it is
+// handled by digestAuthorization after a 401.
+func (c *Client) applyAuth(ctx context.Context, httpReq *http.Request, creds *types.ResolvedCredentials) error {
+	auth := creds.Auth
+	if auth == nil {
+		// Legacy behavior: basic auth when both fields resolved
+		if creds.Username != "" && creds.Password != "" {
+			httpReq.SetBasicAuth(creds.Username, creds.Password)
+		}
+		return nil
+	}
+
+	switch auth.Type {
+	case types.AuthBasic:
+		httpReq.SetBasicAuth(auth.Username, auth.Password)
+
+	case types.AuthBearer:
+		c.setBearer(httpReq, auth.Token)
+
+	case types.AuthAPIKey:
+		if auth.In == "query" {
+			q := httpReq.URL.Query()
+			q.Set(auth.Name, auth.Value)
+			httpReq.URL.RawQuery = q.Encode()
+		} else {
+			httpReq.Header.Set(auth.Name, auth.Value)
+		}
+
+	case types.AuthOAuth2:
+		token := auth.Token
+		if c.tokenSource != nil {
+			var err error
+			if token, err = c.tokenSource.Token(ctx); err != nil {
+				return err
+			}
+		}
+		if token != "" {
+			c.addSecret(token)
+			c.setBearer(httpReq, token)
+		}
+
+	case types.AuthJWT:
+		token, err := mintJWT(auth, time.Now())
+		if err != nil {
+			return err
+		}
+		c.addSecret(token)
+		c.setBearer(httpReq, token)
+	}
+
+	return nil
+}
+
+// setBearer sends a bearer token in the Authorization header, or in the
+// query when WithTokenQuery is set
+func (c *Client) setBearer(httpReq *http.Request, token string) {
+	if c.tokenQuery == "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		return
+	}
+	q := httpReq.URL.Query()
+	q.Set(c.tokenQuery, token)
+	httpReq.URL.RawQuery = q.Encode()
+}
+
+// addSecret registers a value obtained while sending with the redactor
+func (c *Client) addSecret(secret string) {
+	if c.redactor != nil {
+		c.redactor.Add(secret)
+	}
+}
+
+// jwtClockSkew backdates iat so servers with a slightly slow clock accept
+// freshly minted tokens
+const jwtClockSkew = 60 * time.Second
+
+// mintJWT signs a new token from the configured claims, valid from now
+// until now + ExpiresIn
+func mintJWT(auth *types.ResolvedAuth, now time.Time) (string, error) {
+	key := auth.PrivateKey
+	if auth.Algorithm == jwt.HS256 {
+		key = auth.Secret
+	}
+
+	signer, err := jwt.NewSigner(auth.Algorithm, key, auth.KeyID)
+	if err != nil {
+		return "", fmt.Errorf("jwt auth: %w", err)
+	}
+
+	claims := make(map[string]any, len(auth.Claims)+3)
+	for name, value := range auth.Claims {
+		claims[name] = value
+	}
+	claims["iat"] = now.Add(-jwtClockSkew).Unix()
+	claims["exp"] = now.Add(auth.ExpiresIn).Unix()
+	if _, ok := claims["jti"]; !ok {
+		jti, err := newCnonce()
+		if err != nil {
+			return "", err
+		}
+		claims["jti"] = jti
+	}
+
+	token, err := signer.Sign(claims)
+	if err != nil {
+		return "", fmt.Errorf("jwt auth: %w", err)
+	}
+	return token, nil
+}
+
+// digestChallenge holds the parameters of a WWW-Authenticate: Digest header
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+}
+
+// parseDigestChallenge parses a WWW-Authenticate header value.
+// Returns false if the header is not a Digest challenge.
+func parseDigestChallenge(header string) (*digestChallenge, bool) {
+	scheme, params, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Digest") {
+		return nil, false
+	}
+
+	values := parseAuthParams(params)
+	ch := &digestChallenge{
+		realm:     values["realm"],
+		nonce:     values["nonce"],
+		opaque:    values["opaque"],
+		algorithm: values["algorithm"],
+	}
+	if ch.nonce == "" {
+		return nil, false
+	}
+
+	// Prefer "auth"; auth-int would require hashing the body
+	for _, q := range strings.Split(values["qop"], ",") {
+		if strings.TrimSpace(q) == "auth" {
+			ch.qop = "auth"
+		}
+	}
+
+	return ch, true
+}
+
+// parseAuthParams parses comma-separated key=value pairs, where values may
+// be quoted strings containing commas
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ,")
+		eq := strings.IndexByte(s, '=')
+		if eq == -1 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimLeft(s[eq+1:], " ")
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			value = strings.ReplaceAll(s[1:min(end, len(s))], `\"`, `"`)
+			s = s[min(end+1, len(s)):]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end == -1 {
+				end = len(s)
+			}
+			value = strings.TrimSpace(s[:end])
+			s = s[end:]
+		}
+		params[key] = value
+	}
+	return params
+}
+
+// digestAuthorization computes the Authorization header answering a
+// Digest challenge (RFC 7616) for the given request
+func digestAuthorization(ch *digestChallenge, auth *types.ResolvedAuth, method, uri string) (string, error) {
+	algorithm := strings.ToUpper(ch.algorithm)
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(algorithm, "-SESS") {
+	case "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm '%s'", ch.algorithm)
+	}
+
+	h := func(parts ...string) string {
+		d := newHash()
+		d.Write([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(d.Sum(nil))
+	}
+
+	cnonce, err := newCnonce()
+	if err != nil {
+		return "", err
+	}
+	const nc = "00000001"
+
+	ha1 := h(auth.Username, ch.realm, auth.Password)
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = h(ha1, ch.nonce, cnonce)
+	}
+	ha2 := h(method, uri)
+
+	var response string
+	if ch.qop != "" {
+		response = h(ha1, ch.nonce, nc, cnonce, ch.qop, ha2)
+	} else {
+		response = h(ha1, ch.nonce, ha2)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `Digest username="%s", realm="%s", nonce="%s", uri="%s", algorithm=%s, response="%s"`,
+		auth.Username, ch.realm, ch.nonce, uri, algorithm, response)
+	if ch.opaque != "" {
+		fmt.Fprintf(&sb, `, opaque="%s"`, ch.opaque)
+	}
+	if ch.qop != "" {
+		fmt.Fprintf(&sb, `, qop=%s, nc=%s, cnonce="%s"`, ch.qop, nc, cnonce)
+	}
+	return sb.String(), nil
+}
+
+// newCnonce returns a random client nonce
+func newCnonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate cnonce: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
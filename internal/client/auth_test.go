@@ -0,0 +1,272 @@
+package client
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestClient_Do_AuthSchemes(t *testing.T) {
+	tests := []struct {
+		name  string
+		creds types.ResolvedCredentials
+		check func(t *testing.T, r *http.Request)
+	}{
+		{
+			name:  "legacy basic",
+			creds: types.ResolvedCredentials{Username: "u", Password: "p"},
+			check: func(t *testing.T, r *http.Request) {
+				if u, p, ok := r.BasicAuth(); !ok || u != "u" || p != "p" {
+					t.Errorf("BasicAuth() = %q, %q, %v", u, p, ok)
+				}
+			},
+		},
+		{
+			name: "none ignores username",
+			creds: types.ResolvedCredentials{
+				Username: "u", Password: "p",
+				Auth: &types.ResolvedAuth{Type: types.AuthNone},
+			},
+			check: func(t *testing.T, r *http.Request) {
+				if h := r.Header.Get("Authorization"); h != "" {
+					t.Errorf("Authorization = %q, want empty", h)
+				}
+			},
+		},
+		{
+			name:  "bearer",
+			creds: types.ResolvedCredentials{Auth: &types.ResolvedAuth{Type: types.AuthBearer, Token: "tok"}},
+			check: func(t *testing.T, r *http.Request) {
+				if h := r.Header.Get("Authorization"); h != "Bearer tok" {
+					t.Errorf("Authorization = %q, want %q", h, "Bearer tok")
+				}
+			},
+		},
+		{
+			name: "api key header",
+			creds: types.ResolvedCredentials{Auth: &types.ResolvedAuth{
+				Type: types.AuthAPIKey, In: "header", Name: "X-Service-Token", Value: "k",
+			}},
+			check: func(t *testing.T, r *http.Request) {
+				if h := r.Header.Get("X-Service-Token"); h != "k" {
+					t.Errorf("X-Service-Token = %q, want %q", h, "k")
+				}
+			},
+		},
+		{
+			name: "api key query keeps existing params",
+			creds: types.ResolvedCredentials{Auth: &types.ResolvedAuth{
+				Type: types.AuthAPIKey, In: "query", Name: "key", Value: "a b",
+			}},
+			check: func(t *testing.T, r *http.Request) {
+				q := r.URL.Query()
+				if q.Get("key") != "a b" || q.Get("page") != "2" {
+					t.Errorf("query = %q", r.URL.RawQuery)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.check(t, r)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			creds := tt.creds
+			creds.BaseURL = server.URL
+			req := &types.Request{Method: "GET", Path: "/items?page=2"}
+
+			if _, err := New().Do(context.Background(), req, &creds); err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_Do_DigestAuth(t *testing.T) {
+	const (
+		realm = "sreq"
+		nonce = "abc123"
+		user  = "alice"
+		pass  = "secret"
+	)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		authz := r.Header.Get("Authorization")
+		if authz == "" {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Digest realm="%s", nonce="%s", qop="auth,auth-int", opaque="xyz"`, realm, nonce))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		p := parseAuthParams(strings.TrimPrefix(authz, "Digest "))
+		md5hex := func(s string) string {
+			sum := md5.Sum([]byte(s))
+			return hex.EncodeToString(sum[:])
+		}
+		ha1 := md5hex(user + ":" + realm + ":" + pass)
+		ha2 := md5hex(r.Method + ":" + p["uri"])
+		want := md5hex(strings.Join([]string{ha1, nonce, p["nc"], p["cnonce"], p["qop"], ha2}, ":"))
+
+		if p["response"] != want || p["opaque"] != "xyz" || p["uri"] != r.URL.RequestURI() {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("retry lost Content-Type header")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: server.URL,
+		Auth:    &types.ResolvedAuth{Type: types.AuthDigest, Username: user, Password: pass},
+	}
+	req := &types.Request{Method: "POST", Path: "/orders?x=1", Body: `{"a":1}`}
+
+	resp, err := New().Do(context.Background(), req, creds)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestParseDigestChallenge(t *testing.T) {
+	ch, ok := parseDigestChallenge(`Digest realm="a, b", nonce="n", algorithm=SHA-256, qop="auth"`)
+	if !ok {
+		t.Fatal("expected digest challenge")
+	}
+	if ch.realm != "a, b" || ch.nonce != "n" || ch.algorithm != "SHA-256" || ch.qop != "auth" {
+		t.Errorf("challenge = %+v", ch)
+	}
+
+	if _, ok := parseDigestChallenge(`Basic realm="x"`); ok {
+		t.Error("Basic challenge should not parse as digest")
+	}
+}
+
+func TestDigestAuthorization_UnsupportedAlgorithm(t *testing.T) {
+	ch := &digestChallenge{nonce: "n", algorithm: "SHA-512-256"}
+	if _, err := digestAuthorization(ch, &types.ResolvedAuth{}, "GET", "/"); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+}
+
+// fakeTokenSource hands out numbered tokens, advancing on Invalidate
+type fakeTokenSource struct {
+	n int
+}
+
+func (f *fakeTokenSource) Token(ctx context.Context) (string, error) {
+	return fmt.Sprintf("tok-%d", f.n), nil
+}
+
+func (f *fakeTokenSource) Invalidate() { f.n++ }
+
+func TestClient_Do_OAuth2RefreshesOn401(t *testing.T) {
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authz := r.Header.Get("Authorization")
+		seen = append(seen, authz)
+		if authz != "Bearer tok-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: server.URL,
+		Auth:    &types.ResolvedAuth{Type: types.AuthOAuth2},
+	}
+	c := New(WithTokenSource(&fakeTokenSource{}))
+
+	resp, err := c.Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, creds)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if len(seen) != 2 || seen[0] != "Bearer tok-0" {
+		t.Errorf("Authorization headers = %v, want [Bearer tok-0 Bearer tok-1]", seen)
+	}
+}
+
+func TestClient_Do_JWT(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: server.URL,
+		Auth: &types.ResolvedAuth{
+			Type:      types.AuthJWT,
+			Algorithm: "HS256",
+			Secret:    "secret",
+			Claims:    map[string]string{"iss": "sreq", "aud": "orders"},
+			ExpiresIn: 10 * time.Minute,
+		},
+	}
+
+	c := New()
+	for i := 0; i < 2; i++ {
+		if _, err := c.Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, creds); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+
+	if len(tokens) != 2 || tokens[0] == tokens[1] {
+		t.Fatalf("expected a fresh token per request, got %v", tokens)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(tokens[0], ".")[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims struct {
+		Iss string `json:"iss"`
+		Aud string `json:"aud"`
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+		Jti string `json:"jti"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Iss != "sreq" || claims.Aud != "orders" || claims.Jti == "" {
+		t.Errorf("claims = %+v", claims)
+	}
+	if lifetime := claims.Exp - time.Now().Unix(); lifetime < 590 || lifetime > 600 {
+		t.Errorf("exp is %ds from now, want ~600", lifetime)
+	}
+	if claims.Iat >= time.Now().Unix() {
+		t.Errorf("iat = %d, want backdated", claims.Iat)
+	}
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// requestBody is a request body that can be opened again for every send,
+// so retries, auth challenges and signing never hold files in memory
+type requestBody struct {
+	open        func() (io.ReadCloser, error)
+	length      int64
+	contentType string
+
+	// inMemory is the body text when it came from Request.Body
+	inMemory string
+	streamed bool
+}
+
+// newRequestBody picks the body source of a request: multipart fields, a
+// file, or the in-memory body. It returns nil for requests without a body.
+func newRequestBody(req *types.Request) (*requestBody, error) {
+	switch {
+	case len(req.Multipart) > 0:
+		return multipartBody(req.Multipart)
+	case req.BodyFile != "":
+		info, err := os.Stat(req.BodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body file: %w", err)
+		}
+		name := req.BodyFile
+		return &requestBody{
+			open:        func() (io.ReadCloser, error) { return os.Open(name) },
+			length:      info.Size(),
+			contentType: "application/json",
+			streamed:    true,
+		}, nil
+	case req.Body != "":
+		body := req.Body
+		return &requestBody{
+			open:        func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(body)), nil },
+			length:      int64(len(body)),
+			contentType: "application/json",
+			inMemory:    body,
+		}, nil
+	}
+	return nil, nil
+}
+
+// sha256Hex hashes the body, reading streamed bodies once from disk
+func (b *requestBody) sha256Hex() (string, error) {
+	if b == nil {
+		sum := sha256.Sum256(nil)
+		return hex.EncodeToString(sum[:]), nil
+	}
+	r, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = r.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", fmt.Errorf("failed to hash request body: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// multipartBody lays out a multipart/form-data body as in-memory segments
+// (boundaries, headers, values) around the files, which are opened only
+// when the body is read. Its length is known up front.
+func multipartBody(fields []types.FormField) (*requestBody, error) {
+	boundary, err := randomBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	type segment struct {
+		data []byte
+		file string
+	}
+	var segments []segment
+	var length int64
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.SetBoundary(boundary); err != nil {
+		return nil, err
+	}
+
+	for _, f := range fields {
+		h := make(textproto.MIMEHeader)
+		if f.File == "" {
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(f.Name)))
+			if f.ContentType != "" {
+				h.Set("Content-Type", f.ContentType)
+			}
+			part, err := w.CreatePart(h)
+			if err != nil {
+				return nil, err
+			}
+			_, _ = io.WriteString(part, f.Value)
+			continue
+		}
+
+		info, err := os.Stat(f.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read form file: %w", err)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("form file %s is a directory", f.File)
+		}
+		filename := f.Filename
+		if filename == "" {
+			filename = filepath.Base(f.File)
+		}
+		contentType := f.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(f.Name), escapeQuotes(filename)))
+		h.Set("Content-Type", contentType)
+		if _, err := w.CreatePart(h); err != nil {
+			return nil, err
+		}
+
+		// Cut the in-memory segment; the file's content goes in between
+		segments = append(segments, segment{data: bytes.Clone(buf.Bytes())}, segment{file: f.File})
+		length += int64(buf.Len()) + info.Size()
+		buf.Reset()
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	segments = append(segments, segment{data: bytes.Clone(buf.Bytes())})
+	length += int64(buf.Len())
+
+	open := func() (io.ReadCloser, error) {
+		var readers []io.Reader
+		var files []*os.File
+		for _, s := range segments {
+			if s.file == "" {
+				readers = append(readers, bytes.NewReader(s.data))
+				continue
+			}
+			f, err := os.Open(s.file)
+			if err != nil {
+				for _, opened := range files {
+					_ = opened.Close()
+				}
+				return nil, fmt.Errorf("failed to open form file: %w", err)
+			}
+			files = append(files, f)
+			readers = append(readers, f)
+		}
+		return &multiReadCloser{Reader: io.MultiReader(readers...), files: files}, nil
+	}
+
+	return &requestBody{
+		open:        open,
+		length:      length,
+		contentType: w.FormDataContentType(),
+		streamed:    true,
+	}, nil
+}
+
+// multiReadCloser closes the files behind a multipart body
+type multiReadCloser struct {
+	io.Reader
+	files []*os.File
+}
+
+func (m *multiReadCloser) Close() error {
+	var first error
+	for _, f := range m.files {
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// randomBoundary returns a multipart boundary
+func randomBoundary() (string, error) {
+	var b [24]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return "sreq-" + hex.EncodeToString(b[:]), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
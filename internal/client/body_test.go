@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestClient_Do_Multipart(t *testing.T) {
+	report := writeTempFile(t, "report.csv", "id,total\n1,42\n")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength <= 0 {
+			t.Errorf("ContentLength = %d, want the precomputed length", r.ContentLength)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("ParseMultipartForm() error = %v", err)
+		}
+		if got := r.FormValue("title"); got != "Q3" {
+			t.Errorf("title = %q, want %q", got, "Q3")
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile() error = %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != "id,total\n1,42\n" {
+			t.Errorf("file = %q", data)
+		}
+		if header.Filename != "q3.csv" || header.Header.Get("Content-Type") != "text/csv" {
+			t.Errorf("filename = %q, type = %q", header.Filename, header.Header.Get("Content-Type"))
+		}
+	}))
+	defer server.Close()
+
+	req := &types.Request{
+		Method: "POST",
+		Path:   "/upload",
+		Multipart: []types.FormField{
+			{Name: "title", Value: "Q3"},
+			{Name: "file", File: report, Filename: "q3.csv", ContentType: "text/csv"},
+		},
+	}
+	if _, err := New().Do(context.Background(), req, &types.ResolvedCredentials{BaseURL: server.URL}); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+}
+
+func TestClient_Do_BodyFileReplayedOnRetry(t *testing.T) {
+	payload := strings.Repeat("a", 100_000)
+	path := writeTempFile(t, "payload.json", payload)
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if string(data) != payload {
+			t.Errorf("call %d: body has %d bytes, want %d", calls.Load()+1, len(data), len(payload))
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	c := New(WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond, MaxBackoff: time.Millisecond, RetryOn: DefaultRetryStatuses}))
+	resp, err := c.Do(context.Background(), &types.Request{Method: "PUT", Path: "/", BodyFile: path}, &types.ResolvedCredentials{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("Do() = %d after %d calls, want 200 after 2", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestSignHMAC_StreamedBodyRejectsBodyPlaceholder(t *testing.T) {
+	path := writeTempFile(t, "payload.json", "{}")
+	body, err := newRequestBody(&types.Request{BodyFile: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	httpReq, _ := http.NewRequest("POST", "https://api.example.com/", nil)
+	auth := &types.ResolvedAuth{Type: types.AuthHMAC, Secret: "s", Algorithm: "sha256", Canonical: "{method}\n{body}", SignatureHeader: "X-Signature", SignatureFormat: "{signature}"}
+	if err := signHMAC(httpReq, body, auth); err == nil {
+		t.Error("expected an error for {body} with a file upload")
+	}
+
+	auth.Canonical = "{method}\n{body_sha256}"
+	if err := signHMAC(httpReq, body, auth); err != nil {
+		t.Errorf("signHMAC() error = %v", err)
+	}
+}
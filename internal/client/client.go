@@ -1,20 +1,49 @@
 package client
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/Priyans-hu/sreq/internal/redact"
 	"github.com/Priyans-hu/sreq/pkg/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
 )
 
 // Client is the HTTP client for making requests
 type Client struct {
-	httpClient *http.Client
-	verbose    bool
+	httpClient  *http.Client
+	verbose     bool
+	tokenSource TokenSource
+	redactor    *redact.Redactor
+	retry       RetryPolicy
+	redirects   RedirectPolicy
+	protocol    string
+	tokenQuery  string
+
+	// The client derived for a service's tls, proxy or protocol settings,
+	// kept so its connections are reused
+	derivedMu  sync.Mutex
+	derivedFor *types.ResolvedCredentials
+	derived    *http.Client
+
+	// SigV4 credentials, loaded on first use
+	awsMu     sync.Mutex
+	awsCreds  aws.CredentialsProvider
+	awsRegion string
+}
+
+// TokenSource supplies OAuth2 access tokens for oauth2 auth
+type TokenSource interface {
+	// Token returns a valid access token, fetching or refreshing it if needed
+	Token(ctx context.Context) (string, error)
+
+	// Invalidate discards the current access token after it was rejected
+	Invalidate()
 }
 
 // New creates a new HTTP client
@@ -23,6 +52,7 @@ func New(opts ...Option) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		redirects: DefaultRedirectPolicy(),
 	}
 
 	for _, opt := range opts {
@@ -49,18 +79,231 @@ func WithVerbose(v bool) Option {
 	}
 }
 
-// Do executes an HTTP request
+// WithRedactor masks secrets in verbose output and errors. Tokens obtained
+// while sending (OAuth2, JWT) are added to it. Without a redactor, only
+// sensitive header names are masked.
+func WithRedactor(r *redact.Redactor) Option {
+	return func(c *Client) {
+		c.redactor = r
+	}
+}
+
+// WithTokenSource sets the source of access tokens for oauth2 auth
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) {
+		c.tokenSource = ts
+	}
+}
+
+// WithIdleConns keeps up to n idle connections per host, for callers
+// sending many requests at once. The default keeps 2.
+func WithIdleConns(n int) Option {
+	return func(c *Client) {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.MaxIdleConns = n
+		t.MaxIdleConnsPerHost = n
+		c.httpClient.Transport = t
+	}
+}
+
+// Do executes an HTTP request, retrying it according to the retry policy
 func (c *Client) Do(ctx context.Context, req *types.Request, creds *types.ResolvedCredentials) (*types.Response, error) {
+	return c.do(ctx, req, creds, nil)
+}
+
+// do runs the attempts of a request. With a stream func, the final body is
+// handed to it instead of being buffered.
+func (c *Client) do(ctx context.Context, req *types.Request, creds *types.ResolvedCredentials, stream StreamFunc) (*types.Response, error) {
+	hc, err := c.httpClientFor(creds)
+	if err != nil {
+		return nil, err
+	}
+	if stream != nil {
+		// The timeout only bounds waiting for headers, see attemptContext
+		streamHC := *hc
+		streamHC.Timeout = 0
+		hc = &streamHC
+	}
+
+	maxAttempts := c.retry.attemptsFor(req)
+	var attempts []types.Attempt
+
+	for n := 1; ; n++ {
+		start := time.Now()
+		tr := newTracer()
+		actx, headersDone, cancelAttempt := c.attemptContext(tr.context(ctx), stream != nil)
+		resp, redirects, err := c.attempt(actx, hc, req, creds)
+		if !headersDone() && err != nil {
+			err = fmt.Errorf("no response within %s: %w", c.httpClient.Timeout, err)
+		}
+
+		a := types.Attempt{Duration: time.Since(start)}
+		if err != nil {
+			a.Error = err.Error()
+		} else {
+			a.StatusCode = resp.StatusCode
+		}
+
+		retry := n < maxAttempts && c.retry.retryable(ctx, resp, err)
+		if retry {
+			a.Wait = c.retry.backoff(n)
+			if resp != nil {
+				// Honour Retry-After, but no longer than MaxBackoff
+				if d, ok := retryAfter(resp.Header, time.Now()); ok {
+					a.Wait = min(d, c.retry.MaxBackoff)
+				}
+			}
+			// Give up rather than sleep past the overall deadline
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < a.Wait {
+				retry = false
+				a.Wait = 0
+			}
+		}
+		attempts = append(attempts, a)
+
+		if !retry {
+			defer cancelAttempt()
+			if err != nil {
+				if len(attempts) > 1 {
+					return nil, &RetryError{Attempts: attempts, Err: err}
+				}
+				return nil, err
+			}
+			if stream != nil {
+				return streamResponse(resp, attempts, redirects, tr, stream)
+			}
+			return readResponse(resp, attempts, redirects, tr)
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		cancelAttempt()
+		if c.verbose {
+			fmt.Printf("* Retrying in %s (attempt %d/%d)\n", a.Wait.Round(time.Millisecond), n+1, maxAttempts)
+		}
+
+		timer := time.NewTimer(a.Wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, &RetryError{Attempts: attempts, Err: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends a request once, answering auth challenges on the way. It
+// returns the redirects followed to the final response.
+func (c *Client) attempt(ctx context.Context, hc *http.Client, req *types.Request, creds *types.ResolvedCredentials) (*http.Response, []types.Redirect, error) {
+	var redirects []types.Redirect
+	hc = c.redirectClient(hc, creds, &redirects)
+
+	httpReq, err := c.newRequest(ctx, req, creds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.send(hc, httpReq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Digest auth: answer the server's challenge once
+	if creds.Auth != nil && creds.Auth.Type == types.AuthDigest && resp.StatusCode == http.StatusUnauthorized {
+		if ch, ok := parseDigestChallenge(resp.Header.Get("WWW-Authenticate")); ok {
+			authz, err := digestAuthorization(ch, creds.Auth, req.Method, httpReq.URL.RequestURI())
+			if err != nil {
+				_ = resp.Body.Close()
+				return nil, nil, fmt.Errorf("digest auth failed: %w", err)
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+
+			if httpReq, err = c.newRequest(ctx, req, creds); err != nil {
+				return nil, nil, err
+			}
+			httpReq.Header.Set("Authorization", authz)
+			redirects = nil
+			if resp, err = c.send(hc, httpReq); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	// OAuth2: a rejected token may have been revoked early, refresh it once
+	if creds.Auth != nil && creds.Auth.Type == types.AuthOAuth2 && c.tokenSource != nil && resp.StatusCode == http.StatusUnauthorized {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		c.tokenSource.Invalidate()
+		if httpReq, err = c.newRequest(ctx, req, creds); err != nil {
+			return nil, nil, err
+		}
+		redirects = nil
+		if resp, err = c.send(hc, httpReq); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return resp, redirects, nil
+}
+
+// readResponse reads and closes the final response
+func readResponse(resp *http.Response, attempts []types.Attempt, redirects []types.Redirect, tr *tracer) (*types.Response, error) {
+	defer func() { _ = resp.Body.Close() }()
+
+	wire := &countingReader{r: resp.Body}
+	body, encoding, closeDecoder, err := decodeBody(wire, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeDecoder()
+
+	respBody, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	out := &types.Response{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Proto:      resp.Proto,
+		Headers:    resp.Header,
+		Body:       respBody,
+		Trailers:   resp.Trailer,
+		Size:       int64(len(respBody)),
+		Attempts:   attempts,
+		Redirects:  redirects,
+		Timing:     tr.timing(time.Now()),
+	}
+	if encoding != "" {
+		out.Encoding, out.EncodedSize = encoding, wire.n
+	}
+	return out, nil
+}
+
+// newRequest builds the HTTP request with headers and auth applied
+func (c *Client) newRequest(ctx context.Context, req *types.Request, creds *types.ResolvedCredentials) (*http.Request, error) {
 	// Build the full URL
-	url := fmt.Sprintf("%s%s", creds.BaseURL, req.Path)
+	url, err := buildURL(creds.BaseURL, req.Path)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create the HTTP request
-	var body io.Reader
-	if req.Body != "" {
-		body = bytes.NewBufferString(req.Body)
+	body, err := newRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil && req.Compress != "" {
+		if body, err = compressBody(body, req.Compress); err != nil {
+			return nil, err
+		}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, req.Method, url, body)
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -69,10 +312,16 @@ func (c *Client) Do(ctx context.Context, req *types.Request, creds *types.Resolv
 	for key, value := range req.Headers {
 		httpReq.Header.Set(key, value)
 	}
+	if httpReq.Header.Get("Accept-Encoding") == "" {
+		httpReq.Header.Set("Accept-Encoding", AcceptEncoding)
+	}
+	if body != nil && req.Compress != "" {
+		httpReq.Header.Set("Content-Encoding", req.Compress)
+	}
 
-	// Set auth if available
-	if creds.Username != "" && creds.Password != "" {
-		httpReq.SetBasicAuth(creds.Username, creds.Password)
+	// Set auth according to the service's scheme
+	if err := c.applyAuth(ctx, httpReq, creds); err != nil {
+		return nil, err
 	}
 
 	// Set additional credential headers
@@ -80,38 +329,65 @@ func (c *Client) Do(ctx context.Context, req *types.Request, creds *types.Resolv
 		httpReq.Header.Set(key, value)
 	}
 
-	// Set default content type for requests with body
-	if body != nil && httpReq.Header.Get("Content-Type") == "" {
-		httpReq.Header.Set("Content-Type", "application/json")
+	// Set default content type for requests with body, and open the body
+	// last so an auth error leaves no file open
+	if body != nil {
+		if httpReq.Header.Get("Content-Type") == "" {
+			httpReq.Header.Set("Content-Type", body.contentType)
+		}
+		if httpReq.Body, err = body.open(); err != nil {
+			return nil, err
+		}
+		httpReq.ContentLength = body.length
+		httpReq.GetBody = body.open
+		if body.length == 0 {
+			_ = httpReq.Body.Close()
+			httpReq.Body = http.NoBody
+		}
+	}
+
+	// Signing schemes cover the final request, so they run after all headers are set
+	var signErr error
+	if creds.Auth != nil {
+		switch creds.Auth.Type {
+		case types.AuthSigV4:
+			signErr = c.signSigV4(ctx, httpReq, body, creds.Auth)
+		case types.AuthHMAC:
+			signErr = signHMAC(httpReq, body, creds.Auth)
+		}
+	}
+	if signErr != nil {
+		if httpReq.Body != nil {
+			_ = httpReq.Body.Close()
+		}
+		return nil, signErr
 	}
 
+	return httpReq, nil
+}
+
+// send executes a request, printing it first in verbose mode
+func (c *Client) send(hc *http.Client, httpReq *http.Request) (*http.Response, error) {
 	if c.verbose {
-		fmt.Printf("> %s %s\n", req.Method, url)
+		fmt.Printf("> %s %s\n", httpReq.Method, c.redactor.URL(httpReq.URL.String()))
 		for key, values := range httpReq.Header {
 			for _, value := range values {
-				fmt.Printf("> %s: %s\n", key, value)
+				fmt.Printf("> %s: %s\n", key, c.redactor.Header(key, value))
 			}
 		}
 		fmt.Println(">")
 	}
 
-	// Execute the request
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := hc.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		var rle *redirectLimitError
+		if errors.As(err, &rle) {
+			return nil, c.redactor.Error(rle)
+		}
+		return nil, c.redactor.Error(&connError{err: err})
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	// Read the response body
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	if c.verbose {
+		fmt.Printf("< %s %s\n", resp.Proto, resp.Status)
 	}
-
-	return &types.Response{
-		StatusCode: resp.StatusCode,
-		Status:     resp.Status,
-		Headers:    resp.Header,
-		Body:       respBody,
-	}, nil
+	return resp, nil
 }
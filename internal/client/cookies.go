@@ -0,0 +1,203 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// Jar is an http.CookieJar whose cookies can be saved between runs. It
+// implements the RFC 6265 domain and path rules; public suffixes are not
+// checked, since a jar only ever serves one service.
+type Jar struct {
+	mu      sync.Mutex
+	cookies []types.Cookie
+	changed bool
+	now     func() time.Time
+	red     *redact.Redactor
+}
+
+// NewJar creates a jar holding previously saved cookies
+func NewJar(cookies []types.Cookie) *Jar {
+	return &Jar{cookies: append([]types.Cookie(nil), cookies...), now: time.Now}
+}
+
+// Redact registers the stored cookie values with r, and any values
+// responses set later, so output and history mask them
+func (j *Jar) Redact(r *redact.Redactor) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.red = r
+	for _, c := range j.cookies {
+		r.Add(c.Value)
+	}
+}
+
+// WithCookieJar stores cookies from responses and sends them on requests
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(c *Client) {
+		c.httpClient.Jar = jar
+	}
+}
+
+// SetCookies stores the cookies a response from u set
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	host := canonicalHost(u.Host)
+	now := j.now()
+
+	for _, hc := range cookies {
+		if j.red != nil {
+			j.red.Add(hc.Value)
+		}
+		c := types.Cookie{
+			Name:     hc.Name,
+			Value:    hc.Value,
+			Path:     hc.Path,
+			Secure:   hc.Secure,
+			HTTPOnly: hc.HttpOnly,
+		}
+
+		if hc.Domain == "" {
+			c.Domain = host
+			c.HostOnly = true
+		} else {
+			c.Domain = strings.TrimPrefix(strings.ToLower(hc.Domain), ".")
+			// A server may only set cookies for its own domain
+			if !domainMatch(host, c.Domain) || net.ParseIP(host) != nil && host != c.Domain {
+				continue
+			}
+		}
+		if c.Path == "" || !strings.HasPrefix(c.Path, "/") {
+			c.Path = defaultPath(u.Path)
+		}
+
+		switch {
+		case hc.MaxAge < 0:
+			c.Expires = now.Add(-time.Second)
+		case hc.MaxAge > 0:
+			c.Expires = now.Add(time.Duration(hc.MaxAge) * time.Second)
+		case !hc.Expires.IsZero():
+			c.Expires = hc.Expires
+		}
+
+		j.replace(c, now)
+	}
+}
+
+// replace stores c over any cookie with the same name, domain and path.
+// Expired cookies delete the stored one.
+func (j *Jar) replace(c types.Cookie, now time.Time) {
+	kept := j.cookies[:0]
+	for _, old := range j.cookies {
+		if old.Name == c.Name && old.Domain == c.Domain && old.Path == c.Path {
+			continue
+		}
+		kept = append(kept, old)
+	}
+	j.cookies = kept
+	if !c.Expired(now) {
+		j.cookies = append(j.cookies, c)
+	}
+	j.changed = true
+}
+
+// Cookies returns the cookies to send to u, longest path first
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	host := canonicalHost(u.Host)
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	secure := u.Scheme == "https" || u.Scheme == "wss"
+	now := j.now()
+
+	var matched []types.Cookie
+	for _, c := range j.cookies {
+		if c.Expired(now) || c.Secure && !secure || !pathMatch(path, c.Path) {
+			continue
+		}
+		if c.HostOnly && host != c.Domain || !c.HostOnly && !domainMatch(host, c.Domain) {
+			continue
+		}
+		matched = append(matched, c)
+	}
+	sort.SliceStable(matched, func(a, b int) bool { return len(matched[a].Path) > len(matched[b].Path) })
+
+	result := make([]*http.Cookie, len(matched))
+	for i, c := range matched {
+		result[i] = &http.Cookie{Name: c.Name, Value: c.Value}
+	}
+	return result
+}
+
+// All returns the unexpired cookies, for saving
+func (j *Jar) All() []types.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := j.now()
+	var cookies []types.Cookie
+	for _, c := range j.cookies {
+		if !c.Expired(now) {
+			cookies = append(cookies, c)
+		}
+	}
+	return cookies
+}
+
+// Changed reports whether responses set or removed cookies
+func (j *Jar) Changed() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.changed
+}
+
+// canonicalHost lowercases a host and strips its port
+func canonicalHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// domainMatch reports whether host is domain or one of its subdomains
+func domainMatch(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// pathMatch implements RFC 6265 section 5.1.4
+func pathMatch(reqPath, cookiePath string) bool {
+	if reqPath == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(reqPath, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || reqPath[len(cookiePath)] == '/'
+}
+
+// defaultPath implements RFC 6265 section 5.1.4
+func defaultPath(reqPath string) string {
+	if !strings.HasPrefix(reqPath, "/") {
+		return "/"
+	}
+	i := strings.LastIndex(reqPath, "/")
+	if i == 0 {
+		return "/"
+	}
+	return reqPath[:i]
+}
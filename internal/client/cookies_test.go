@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func cookieNames(cookies []*http.Cookie) []string {
+	var names []string
+	for _, c := range cookies {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestJar_DomainAndPath(t *testing.T) {
+	jar := NewJar(nil)
+	u, _ := url.Parse("https://api.example.com/v1/users")
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "host", Value: "1"},
+		{Name: "domain", Value: "2", Domain: ".example.com", Path: "/"},
+		{Name: "v1", Value: "3", Path: "/v1"},
+		{Name: "secure", Value: "4", Secure: true},
+		{Name: "foreign", Value: "5", Domain: "other.com"},
+	})
+
+	tests := []struct {
+		url  string
+		want []string
+	}{
+		// Longest path first
+		{"https://api.example.com/v1/orders", []string{"host", "v1", "secure", "domain"}},
+		{"https://api.example.com/v2", []string{"domain"}},
+		{"https://www.example.com/v1", []string{"domain"}},
+		{"http://api.example.com/v1/x", []string{"host", "v1", "domain"}},
+		{"https://other.com/", nil},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		got := cookieNames(jar.Cookies(u))
+		if len(got) != len(tt.want) {
+			t.Errorf("Cookies(%s) = %v, want %v", tt.url, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Cookies(%s) = %v, want %v", tt.url, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestJar_ReplaceAndExpire(t *testing.T) {
+	u, _ := url.Parse("https://api.example.com/")
+	jar := NewJar([]types.Cookie{
+		{Name: "session", Value: "old", Domain: "api.example.com", HostOnly: true, Path: "/"},
+		{Name: "stale", Value: "x", Domain: "api.example.com", HostOnly: true, Path: "/", Expires: time.Now().Add(-time.Hour)},
+	})
+	if jar.Changed() {
+		t.Error("a loaded jar should not be changed")
+	}
+
+	jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "new", MaxAge: 60}})
+	all := jar.All()
+	if len(all) != 1 || all[0].Value != "new" || all[0].Expires.IsZero() {
+		t.Fatalf("All() = %+v, want the replaced session cookie", all)
+	}
+	if !jar.Changed() {
+		t.Error("Changed() = false after SetCookies")
+	}
+
+	jar.SetCookies(u, []*http.Cookie{{Name: "session", MaxAge: -1}})
+	if all := jar.All(); len(all) != 0 {
+		t.Errorf("All() = %+v, want deleted", all)
+	}
+}
+
+func TestClient_Do_CookieJar(t *testing.T) {
+	var gotCookie string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/"})
+			return
+		}
+		gotCookie = r.Header.Get("Cookie")
+	}))
+	defer server.Close()
+
+	jar := NewJar(nil)
+	creds := &types.ResolvedCredentials{BaseURL: server.URL}
+
+	if _, err := New(WithCookieJar(jar)).Do(context.Background(), &types.Request{Method: "POST", Path: "/login"}, creds); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	// A new client, as on the next invocation, sends the saved cookie
+	saved := jar.All()
+	if _, err := New(WithCookieJar(NewJar(saved))).Do(context.Background(), &types.Request{Method: "GET", Path: "/me"}, creds); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if gotCookie != "session=abc123" {
+		t.Errorf("Cookie header = %q, want session=abc123", gotCookie)
+	}
+}
+
+func TestJar_Redact(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "fresh-session", Path: "/"})
+			http.Redirect(w, r, "/home", http.StatusFound)
+			return
+		}
+	}))
+	defer server.Close()
+
+	red := redact.New()
+	jar := NewJar([]types.Cookie{{Name: "saved", Value: "stored-session", Domain: "127.0.0.1", Path: "/"}})
+	jar.Redact(red)
+
+	// Set on a redirect hop, which the caller never sees
+	if _, err := New(WithCookieJar(jar)).Do(context.Background(), &types.Request{Method: "GET", Path: "/login"}, &types.ResolvedCredentials{BaseURL: server.URL}); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	out := red.String("saved=stored-session; session=fresh-session")
+	if strings.Contains(out, "stored-session") || strings.Contains(out, "fresh-session") {
+		t.Errorf("String() = %q, want cookie values masked", out)
+	}
+}
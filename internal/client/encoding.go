@@ -0,0 +1,215 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// AcceptEncoding is sent on requests that set no Accept-Encoding of their
+// own. Responses in any of these codings are decoded transparently.
+const AcceptEncoding = "gzip, deflate, br, zstd"
+
+// Request body codings for Request.Compress
+const (
+	EncodingGzip = "gzip"
+	EncodingZstd = "zstd"
+)
+
+// maxCodings bounds the Content-Encoding layers undone for one response
+const maxCodings = 4
+
+// ValidateCompression checks that encoding names a supported request body
+// coding. Empty sends the body uncompressed.
+func ValidateCompression(encoding string) error {
+	switch encoding {
+	case "", EncodingGzip, EncodingZstd:
+		return nil
+	}
+	return fmt.Errorf("unknown compression %q (want %s or %s)", encoding, EncodingGzip, EncodingZstd)
+}
+
+// compressBody returns b compressed with encoding. In-memory bodies are
+// compressed up front so their length is known; streamed bodies are
+// compressed as they are read and sent chunked.
+func compressBody(b *requestBody, encoding string) (*requestBody, error) {
+	if err := ValidateCompression(encoding); err != nil {
+		return nil, err
+	}
+
+	if !b.streamed {
+		var buf bytes.Buffer
+		w, err := newEncoder(&buf, encoding)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(w, b.inMemory); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress request body: %w", err)
+		}
+		data := buf.String()
+		return &requestBody{
+			open:        func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(data)), nil },
+			length:      int64(len(data)),
+			contentType: b.contentType,
+			inMemory:    data,
+		}, nil
+	}
+
+	open := b.open
+	return &requestBody{
+		open: func() (io.ReadCloser, error) {
+			src, err := open()
+			if err != nil {
+				return nil, err
+			}
+			pr, pw := io.Pipe()
+			go func() {
+				defer func() { _ = src.Close() }()
+				w, err := newEncoder(pw, encoding)
+				if err == nil {
+					_, err = io.Copy(w, src)
+					if cerr := w.Close(); err == nil {
+						err = cerr
+					}
+				}
+				_ = pw.CloseWithError(err)
+			}()
+			return pr, nil
+		},
+		length:      -1,
+		contentType: b.contentType,
+		streamed:    true,
+	}, nil
+}
+
+// newEncoder returns a writer compressing into w
+func newEncoder(w io.Writer, encoding string) (io.WriteCloser, error) {
+	switch encoding {
+	case EncodingGzip:
+		return gzip.NewWriter(w), nil
+	case EncodingZstd:
+		// One goroutine keeps the output identical for every send, which
+		// signing relies on
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	}
+	return nil, fmt.Errorf("unknown compression %q", encoding)
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// decodeBody undoes the codings listed in a Content-Encoding header, last
+// applied first. It returns the codings it decoded, empty when the body is
+// passed through: for identity, an empty body or an unknown coding.
+// closeFn releases the decoders.
+func decodeBody(body io.Reader, contentEncoding string) (decoded io.Reader, codings string, closeFn func(), err error) {
+	var list []string
+	for _, c := range strings.Split(contentEncoding, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c != "" && c != "identity" {
+			list = append(list, c)
+		}
+	}
+	closeFn = func() {}
+	if len(list) == 0 || len(list) > maxCodings {
+		return body, "", closeFn, nil
+	}
+	for _, c := range list {
+		if !knownCoding(c) {
+			return body, "", closeFn, nil
+		}
+	}
+
+	// HEAD responses and 204s may name a coding without a body to decode
+	br := bufio.NewReader(body)
+	if _, err := br.Peek(1); err == io.EOF {
+		return br, "", closeFn, nil
+	}
+
+	var closers []func()
+	closeFn = func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+	decoded = br
+	for i := len(list) - 1; i >= 0; i-- {
+		r, closer, err := newDecoder(decoded, list[i])
+		if err != nil {
+			closeFn()
+			return nil, "", func() {}, fmt.Errorf("failed to decode %s response body: %w", list[i], err)
+		}
+		decoded = r
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+	}
+	return decoded, strings.Join(list, ", "), closeFn, nil
+}
+
+// knownCoding reports whether decodeBody can undo a coding
+func knownCoding(c string) bool {
+	switch c {
+	case "gzip", "x-gzip", "deflate", "br", "zstd":
+		return true
+	}
+	return false
+}
+
+// newDecoder returns a reader decoding one coding of r
+func newDecoder(r io.Reader, coding string) (io.Reader, func(), error) {
+	switch coding {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, func() { _ = zr.Close() }, nil
+	case "deflate":
+		// deflate means zlib, but some servers send raw deflate
+		br := bufio.NewReader(r)
+		if hdr, err := br.Peek(2); err == nil && isZlibHeader(hdr) {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, nil, err
+			}
+			return zr, func() { _ = zr.Close() }, nil
+		}
+		fr := flate.NewReader(br)
+		return fr, func() { _ = fr.Close() }, nil
+	case "br":
+		return brotli.NewReader(r), nil, nil
+	case "zstd":
+		zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, zr.Close, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported coding %q", coding)
+}
+
+// isZlibHeader reports whether b starts a zlib stream (RFC 1950)
+func isZlibHeader(b []byte) bool {
+	return b[0]&0x0F == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
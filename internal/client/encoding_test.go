@@ -0,0 +1,223 @@
+package client
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// encode compresses data with each coding in turn
+func encode(t *testing.T, data []byte, codings ...string) []byte {
+	t.Helper()
+	for _, c := range codings {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		switch c {
+		case "gzip":
+			w = gzip.NewWriter(&buf)
+		case "deflate":
+			w = zlib.NewWriter(&buf)
+		case "raw-deflate":
+			w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+		case "br":
+			w = brotli.NewWriter(&buf)
+		case "zstd":
+			w, _ = zstd.NewWriter(&buf)
+		}
+		_, _ = w.Write(data)
+		_ = w.Close()
+		data = buf.Bytes()
+	}
+	return data
+}
+
+func TestClient_DecodesResponses(t *testing.T) {
+	body := []byte(strings.Repeat(`{"id":1,"name":"order"}`, 100))
+
+	tests := []struct {
+		name         string
+		header       string
+		wire         []byte
+		wantEncoding string
+	}{
+		{"gzip", "gzip", encode(t, body, "gzip"), "gzip"},
+		{"deflate", "deflate", encode(t, body, "deflate"), "deflate"},
+		{"raw deflate", "deflate", encode(t, body, "raw-deflate"), "deflate"},
+		{"brotli", "br", encode(t, body, "br"), "br"},
+		{"zstd", "zstd", encode(t, body, "zstd"), "zstd"},
+		{"stacked", "gzip, br", encode(t, body, "gzip", "br"), "gzip, br"},
+		{"identity", "identity", body, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Accept-Encoding"); got != AcceptEncoding {
+					t.Errorf("Accept-Encoding = %q, want %q", got, AcceptEncoding)
+				}
+				w.Header().Set("Content-Encoding", tt.header)
+				_, _ = w.Write(tt.wire)
+			}))
+			defer server.Close()
+
+			resp, err := New().Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, &types.ResolvedCredentials{BaseURL: server.URL})
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			if !bytes.Equal(resp.Body, body) {
+				t.Errorf("Body = %.40q..., want the decoded body", resp.Body)
+			}
+			if resp.Encoding != tt.wantEncoding {
+				t.Errorf("Encoding = %q, want %q", resp.Encoding, tt.wantEncoding)
+			}
+			if tt.wantEncoding != "" && resp.EncodedSize != int64(len(tt.wire)) {
+				t.Errorf("EncodedSize = %d, want %d", resp.EncodedSize, len(tt.wire))
+			}
+			if resp.Size != int64(len(body)) {
+				t.Errorf("Size = %d, want %d", resp.Size, len(body))
+			}
+		})
+	}
+}
+
+func TestClient_DecodePassThrough(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/unknown":
+			w.Header().Set("Content-Encoding", "compress")
+			_, _ = io.WriteString(w, "LZW bytes")
+		case "/empty":
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(http.StatusNoContent)
+		case "/own":
+			// A caller's Accept-Encoding is sent as is
+			_, _ = io.WriteString(w, r.Header.Get("Accept-Encoding"))
+		}
+	}))
+	defer server.Close()
+
+	creds := &types.ResolvedCredentials{BaseURL: server.URL}
+	resp, err := New().Do(context.Background(), &types.Request{Method: "GET", Path: "/unknown"}, creds)
+	if err != nil || string(resp.Body) != "LZW bytes" || resp.Encoding != "" {
+		t.Errorf("unknown coding: Body = %q, Encoding = %q, err = %v; want the raw body", resp.Body, resp.Encoding, err)
+	}
+
+	resp, err = New().Do(context.Background(), &types.Request{Method: "GET", Path: "/empty"}, creds)
+	if err != nil || len(resp.Body) != 0 {
+		t.Errorf("empty body: Body = %q, err = %v", resp.Body, err)
+	}
+
+	req := &types.Request{Method: "GET", Path: "/own", Headers: map[string]string{"Accept-Encoding": "identity"}}
+	resp, err = New().Do(context.Background(), req, creds)
+	if err != nil || string(resp.Body) != "identity" {
+		t.Errorf("Accept-Encoding sent = %q, err = %v; want identity", resp.Body, err)
+	}
+}
+
+func TestClient_DoStreamDecodes(t *testing.T) {
+	wire := encode(t, []byte("data: one\n\ndata: two\n\n"), "gzip")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(wire)
+	}))
+	defer server.Close()
+
+	var got []byte
+	resp, err := New().DoStream(context.Background(), &types.Request{Method: "GET", Path: "/"}, &types.ResolvedCredentials{BaseURL: server.URL},
+		func(resp *types.Response, body io.Reader) (err error) {
+			got, err = io.ReadAll(body)
+			return err
+		})
+	if err != nil {
+		t.Fatalf("DoStream() error = %v", err)
+	}
+	if string(got) != "data: one\n\ndata: two\n\n" {
+		t.Errorf("stream = %q, want the decoded events", got)
+	}
+	if resp.Encoding != "gzip" || resp.EncodedSize != int64(len(wire)) {
+		t.Errorf("Encoding = %q, EncodedSize = %d; want gzip, %d", resp.Encoding, resp.EncodedSize, len(wire))
+	}
+}
+
+func TestClient_CompressRequest(t *testing.T) {
+	payload := strings.Repeat(`{"event":"click"}`, 200)
+	file := filepath.Join(t.TempDir(), "events.json")
+	if err := os.WriteFile(file, []byte(payload), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotEncoding string
+	var gotLength int64
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding, gotLength = r.Header.Get("Content-Encoding"), r.ContentLength
+		var body io.Reader = r.Body
+		switch gotEncoding {
+		case "gzip":
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("gzip.NewReader() error = %v", err)
+				return
+			}
+			body = zr
+		case "zstd":
+			zr, err := zstd.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("zstd.NewReader() error = %v", err)
+				return
+			}
+			defer zr.Close()
+			body = zr
+		}
+		gotBody, _ = io.ReadAll(body)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		req   *types.Request
+		fixed bool // Content-Length known up front
+	}{
+		{"gzip", &types.Request{Method: "POST", Path: "/", Body: payload, Compress: EncodingGzip}, true},
+		{"zstd", &types.Request{Method: "POST", Path: "/", Body: payload, Compress: EncodingZstd}, true},
+		{"zstd file", &types.Request{Method: "POST", Path: "/", BodyFile: file, Compress: EncodingZstd}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New().Do(context.Background(), tt.req, &types.ResolvedCredentials{BaseURL: server.URL}); err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			if gotEncoding != tt.req.Compress {
+				t.Errorf("Content-Encoding = %q, want %q", gotEncoding, tt.req.Compress)
+			}
+			if string(gotBody) != payload {
+				t.Errorf("decoded body = %.40q..., want the payload", gotBody)
+			}
+			if tt.fixed && (gotLength <= 0 || gotLength >= int64(len(payload))) {
+				t.Errorf("Content-Length = %d, want the compressed length", gotLength)
+			}
+			if !tt.fixed && gotLength != -1 {
+				t.Errorf("Content-Length = %d, want chunked", gotLength)
+			}
+		})
+	}
+
+	req := &types.Request{Method: "POST", Path: "/", Body: payload, Compress: "br"}
+	if _, err := New().Do(context.Background(), req, &types.ResolvedCredentials{BaseURL: server.URL}); err == nil || !strings.Contains(err.Error(), "unknown compression") {
+		t.Errorf("Do() error = %v, want unknown compression", err)
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/template"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// signHMAC signs a fully built request with an HMAC over the canonical
+// string and sets the signature, timestamp and nonce headers
+func signHMAC(httpReq *http.Request, body *requestBody, auth *types.ResolvedAuth) error {
+	newHash, ok := hmacAlgorithms[auth.Algorithm]
+	if !ok {
+		return fmt.Errorf("unsupported hmac algorithm '%s'", auth.Algorithm)
+	}
+
+	nonce, err := newCnonce()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var timestamp string
+	switch auth.TimestampFormat {
+	case "unix_ms":
+		timestamp = strconv.FormatInt(now.UnixMilli(), 10)
+	case "rfc3339":
+		timestamp = now.Format(time.RFC3339)
+	default:
+		timestamp = strconv.FormatInt(now.Unix(), 10)
+	}
+
+	var bodyText string
+	if body != nil {
+		// Streamed uploads are hashed from disk, never held as text
+		if body.streamed && strings.Contains(auth.Canonical, "{body}") {
+			return fmt.Errorf("hmac canonical uses {body}, which is not available for file uploads; use {body_sha256}")
+		}
+		bodyText = body.inMemory
+	}
+	bodySHA256, err := body.sha256Hex()
+	if err != nil {
+		return err
+	}
+
+	vars := map[string]string{
+		"method":       httpReq.Method,
+		"path":         httpReq.URL.RequestURI(),
+		"query":        httpReq.URL.RawQuery,
+		"host":         httpReq.URL.Host,
+		"content_type": httpReq.Header.Get("Content-Type"),
+		"timestamp":    timestamp,
+		"nonce":        nonce,
+		"body":         bodyText,
+		"body_sha256":  bodySHA256,
+		"key_id":       auth.KeyID,
+	}
+
+	canonical, err := template.Render(auth.Canonical, vars, template.Options{})
+	if err != nil {
+		return fmt.Errorf("hmac canonical string: %w", err)
+	}
+
+	mac := hmac.New(newHash, []byte(auth.Secret))
+	mac.Write([]byte(canonical))
+	sum := mac.Sum(nil)
+
+	switch auth.Encoding {
+	case "base64":
+		vars["signature"] = base64.StdEncoding.EncodeToString(sum)
+	case "base64url":
+		vars["signature"] = base64.RawURLEncoding.EncodeToString(sum)
+	default:
+		vars["signature"] = hex.EncodeToString(sum)
+	}
+
+	signature, err := template.Render(auth.SignatureFormat, vars, template.Options{})
+	if err != nil {
+		return fmt.Errorf("hmac signature_format: %w", err)
+	}
+
+	httpReq.Header.Set(auth.SignatureHeader, signature)
+	if auth.TimestampHeader != "" {
+		httpReq.Header.Set(auth.TimestampHeader, timestamp)
+	}
+	if auth.NonceHeader != "" {
+		httpReq.Header.Set(auth.NonceHeader, nonce)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestClient_Do_HMAC(t *testing.T) {
+	const secret = "whsec_123"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts := r.Header.Get("X-Timestamp")
+
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil || time.Since(time.Unix(sec, 0)) > time.Minute {
+			t.Errorf("X-Timestamp = %q, want current unix time", ts)
+		}
+
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(r.Method + "\n" + r.URL.RequestURI() + "\n" + ts + "\n" + string(body)))
+		want := hex.EncodeToString(mac.Sum(nil))
+
+		if got := r.Header.Get("X-Signature"); got != want {
+			t.Errorf("X-Signature = %q, want %q", got, want)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: server.URL,
+		Auth: &types.ResolvedAuth{
+			Type:            types.AuthHMAC,
+			Secret:          secret,
+			Algorithm:       "sha256",
+			Encoding:        "hex",
+			Canonical:       "{method}\n{path}\n{timestamp}\n{body}",
+			SignatureHeader: "X-Signature",
+			SignatureFormat: "{signature}",
+			TimestampHeader: "X-Timestamp",
+			TimestampFormat: "unix",
+		},
+	}
+
+	req := &types.Request{Method: "POST", Path: "/payments?dry_run=1", Body: `{"amount":100}`}
+	if _, err := New().Do(context.Background(), req, creds); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+}
+
+func TestClient_Do_HMAC_CustomFormat(t *testing.T) {
+	const secret = "s3cret"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		nonce := r.Header.Get("X-Nonce")
+		if len(nonce) != 32 {
+			t.Errorf("X-Nonce = %q, want 32 hex chars", nonce)
+		}
+		ts := r.Header.Get("X-Request-Time")
+		if _, err := time.Parse(time.RFC3339, ts); err != nil {
+			t.Errorf("X-Request-Time = %q, want RFC 3339", ts)
+		}
+
+		bodySum := sha256.Sum256(body)
+		mac := hmac.New(sha512.New, []byte(secret))
+		mac.Write([]byte(ts + ":" + nonce + ":" + hex.EncodeToString(bodySum[:])))
+		want := "HMAC-SHA512 key-1:" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+		if got := r.Header.Get("Authorization"); got != want {
+			t.Errorf("Authorization = %q, want %q", got, want)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: server.URL,
+		Auth: &types.ResolvedAuth{
+			Type:            types.AuthHMAC,
+			Secret:          secret,
+			KeyID:           "key-1",
+			Algorithm:       "sha512",
+			Encoding:        "base64",
+			Canonical:       "{timestamp}:{nonce}:{body_sha256}",
+			SignatureHeader: "Authorization",
+			SignatureFormat: "HMAC-SHA512 {key_id}:{signature}",
+			TimestampHeader: "X-Request-Time",
+			TimestampFormat: "rfc3339",
+			NonceHeader:     "X-Nonce",
+		},
+	}
+
+	req := &types.Request{Method: "PUT", Path: "/webhooks/1", Body: `{"url":"https://example.com"}`}
+	if _, err := New().Do(context.Background(), req, creds); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+}
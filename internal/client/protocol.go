@@ -0,0 +1,87 @@
+package client
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+	"github.com/quic-go/quic-go/http3"
+)
+
+// WithProtocol pins requests to an HTTP version, one of the
+// types.Protocol constants. Empty keeps the default negotiation.
+func WithProtocol(protocol string) Option {
+	return func(c *Client) {
+		c.protocol = protocol
+	}
+}
+
+// ValidateProtocol checks that protocol names a supported HTTP version
+func ValidateProtocol(protocol string) error {
+	switch protocol {
+	case "", types.ProtocolHTTP1, types.ProtocolHTTP2, types.ProtocolH2C, types.ProtocolHTTP3:
+		return nil
+	default:
+		return fmt.Errorf("unknown protocol %q (want %s, %s, %s or %s)", protocol, types.ProtocolHTTP1, types.ProtocolHTTP2, types.ProtocolH2C, types.ProtocolHTTP3)
+	}
+}
+
+// protocolsFor returns the transport protocols for a pinned version, or
+// nil for the default. h3 is not served by http.Transport, see h3Transport.
+func protocolsFor(protocol, baseURL string) (*http.Protocols, error) {
+	if err := ValidateProtocol(protocol); err != nil {
+		return nil, err
+	}
+
+	secure := true
+	if u, err := url.Parse(baseURL); err == nil {
+		secure = u.Scheme != "http"
+	}
+
+	var p http.Protocols
+	switch protocol {
+	case "":
+		return nil, nil
+	case types.ProtocolHTTP1:
+		p.SetHTTP1(true)
+	case types.ProtocolHTTP2:
+		if !secure {
+			return nil, fmt.Errorf("h2 needs an https base URL, use h2c for cleartext HTTP/2")
+		}
+		p.SetHTTP2(true)
+	case types.ProtocolH2C:
+		if secure {
+			return nil, fmt.Errorf("h2c is cleartext HTTP/2, use h2 for an https base URL")
+		}
+		p.SetUnencryptedHTTP2(true)
+	case types.ProtocolHTTP3:
+		if !secure {
+			return nil, fmt.Errorf("h3 runs over QUIC and needs an https base URL")
+		}
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// h3Transport returns an HTTP/3 transport for creds. QUIC cannot be
+// tunnelled through an HTTP proxy, so a configured proxy is refused
+// rather than silently bypassed.
+func h3Transport(creds *types.ResolvedCredentials) (http.RoundTripper, error) {
+	if creds.Proxy != nil && creds.Proxy.URL != types.ProxyDirect {
+		return nil, fmt.Errorf("h3 cannot be used through a proxy, use h2 or set the proxy to direct")
+	}
+
+	tlsConfig := &tls.Config{}
+	if creds.TLS != nil {
+		var err error
+		if tlsConfig, err = buildTLSConfig(creds.TLS); err != nil {
+			return nil, err
+		}
+	}
+	tlsConfig.MinVersion = tls.VersionTLS13 // required by QUIC
+
+	// Bodies are decoded by readResponse, as with the other transports
+	return &http3.Transport{TLSClientConfig: tlsConfig, DisableCompression: true}, nil
+}
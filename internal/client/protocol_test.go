@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+	"github.com/quic-go/quic-go/http3"
+)
+
+func TestProtocolsFor_Errors(t *testing.T) {
+	tests := []struct {
+		protocol string
+		baseURL  string
+	}{
+		{types.ProtocolHTTP2, "http://api.example.com"},
+		{types.ProtocolH2C, "https://api.example.com"},
+		{types.ProtocolHTTP3, "http://api.example.com"},
+		{"spdy", "https://api.example.com"},
+	}
+
+	for _, tt := range tests {
+		if _, err := protocolsFor(tt.protocol, tt.baseURL); err == nil {
+			t.Errorf("protocolsFor(%q, %q) error = nil, want error", tt.protocol, tt.baseURL)
+		}
+	}
+}
+
+func TestClient_Do_ProtocolTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: server.URL,
+		TLS:     &types.ResolvedTLS{InsecureSkipVerify: true},
+	}
+
+	tests := []struct {
+		protocol string
+		want     string
+	}{
+		{"", "HTTP/2.0"},
+		{types.ProtocolHTTP2, "HTTP/2.0"},
+		{types.ProtocolHTTP1, "HTTP/1.1"},
+	}
+
+	for _, tt := range tests {
+		resp, err := New(WithProtocol(tt.protocol)).Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, creds)
+		if err != nil {
+			t.Fatalf("Do(%q) error = %v", tt.protocol, err)
+		}
+		if resp.Proto != tt.want {
+			t.Errorf("Do(%q) Proto = %q, want %q", tt.protocol, resp.Proto, tt.want)
+		}
+	}
+}
+
+func TestClient_Do_H2C(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	creds := &types.ResolvedCredentials{BaseURL: server.URL}
+
+	resp, err := New(WithProtocol(types.ProtocolH2C)).Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, creds)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.Proto != "HTTP/2.0" {
+		t.Errorf("Proto = %q, want HTTP/2.0", resp.Proto)
+	}
+
+	resp, err = New().Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, creds)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.Proto != "HTTP/1.1" {
+		t.Errorf("default Proto = %q, want HTTP/1.1", resp.Proto)
+	}
+}
+
+func TestClient_Do_HTTP3(t *testing.T) {
+	// Borrow a certificate for localhost from an httptest TLS server
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP unavailable: %v", err)
+	}
+	server := &http3.Server{
+		TLSConfig: http3.ConfigureTLSConfig(tlsServer.TLS.Clone()),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, r.Proto)
+		}),
+	}
+	go func() { _ = server.Serve(udp) }()
+	defer func() { _ = server.Close() }()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: "https://" + udp.LocalAddr().String(),
+		TLS:     &types.ResolvedTLS{InsecureSkipVerify: true},
+	}
+	resp, err := New(WithProtocol(types.ProtocolHTTP3)).Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, creds)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.Proto != "HTTP/3.0" || string(resp.Body) != "HTTP/3.0" {
+		t.Errorf("Proto = %q, body = %q; want HTTP/3.0", resp.Proto, resp.Body)
+	}
+
+	creds.Proxy = &types.ResolvedProxy{URL: "http://proxy.internal:3128"}
+	if _, err := New(WithProtocol(types.ProtocolHTTP3)).Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, creds); err == nil {
+		t.Error("Do() through a proxy error = nil, want h3 refused")
+	}
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// proxyFunc returns the transport's Proxy function for a resolved proxy.
+// A "direct" proxy disables proxying, including HTTP_PROXY.
+func proxyFunc(p *types.ResolvedProxy) (func(*http.Request) (*url.URL, error), error) {
+	if p.URL == types.ProxyDirect {
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	if p.Username != "" {
+		proxyURL.User = url.UserPassword(p.Username, p.Password)
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL.Hostname(), p.NoProxy) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// bypassProxy reports whether host matches a no_proxy entry. Entries are
+// "*", IPs or CIDR ranges, exact hosts (also matching their subdomains) or
+// ".domain" and "*.domain" suffixes.
+func bypassProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		case ip != nil:
+			if _, cidr, err := net.ParseCIDR(entry); err == nil {
+				if cidr.Contains(ip) {
+					return true
+				}
+			} else if entryIP := net.ParseIP(entry); entryIP != nil && entryIP.Equal(ip) {
+				return true
+			}
+		default:
+			if h, _, err := net.SplitHostPort(entry); err == nil {
+				entry = h
+			}
+			suffix := strings.TrimPrefix(entry, "*")
+			if strings.HasPrefix(suffix, ".") {
+				if host == suffix[1:] || strings.HasSuffix(host, suffix) {
+					return true
+				}
+			} else if host == entry || strings.HasSuffix(host, "."+entry) {
+				return true
+			}
+		}
+	}
+	return false
+}
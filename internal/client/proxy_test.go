@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestBypassProxy(t *testing.T) {
+	noProxy := []string{"localhost", ".internal", "*.svc.local", "10.0.0.0/8", "192.168.1.5", "example.com:8443"}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"localhost", true},
+		{"orders.internal", true},
+		{"internal", true},
+		{"a.b.svc.local", true},
+		{"10.1.2.3", true},
+		{"192.168.1.5", true},
+		{"api.example.com", true},
+		{"example.com", true},
+		{"notexample.com", false},
+		{"11.0.0.1", false},
+		{"api.partner.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := bypassProxy(tt.host, noProxy); got != tt.want {
+			t.Errorf("bypassProxy(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+
+	if !bypassProxy("anything", []string{"*"}) {
+		t.Error(`"*" should bypass every host`)
+	}
+}
+
+func TestClient_Do_HTTPProxy(t *testing.T) {
+	var gotURL, gotAuth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		gotAuth = r.Header.Get("Proxy-Authorization")
+		_, _ = w.Write([]byte("via proxy"))
+	}))
+	defer proxy.Close()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: "http://orders.example.test",
+		Proxy:   &types.ResolvedProxy{URL: proxy.URL, Username: "svc", Password: "hunter22"},
+	}
+
+	resp, err := New().Do(context.Background(), &types.Request{Method: "GET", Path: "/orders"}, creds)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if string(resp.Body) != "via proxy" {
+		t.Errorf("Body = %q, want proxied response", resp.Body)
+	}
+	if gotURL != "http://orders.example.test/orders" {
+		t.Errorf("proxy saw %q, want absolute target url", gotURL)
+	}
+	if want := "Basic " + base64.StdEncoding.EncodeToString([]byte("svc:hunter22")); gotAuth != want {
+		t.Errorf("Proxy-Authorization = %q, want %q", gotAuth, want)
+	}
+}
+
+func TestProxyFunc_Direct(t *testing.T) {
+	proxy, err := proxyFunc(&types.ResolvedProxy{URL: types.ProxyDirect})
+	if err != nil {
+		t.Fatalf("proxyFunc() error = %v", err)
+	}
+	if proxy != nil {
+		t.Error("direct should disable proxying, including HTTP_PROXY")
+	}
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// DefaultMaxRedirects is how many redirects are followed by default
+const DefaultMaxRedirects = 10
+
+// RedirectPolicy controls how redirects are followed
+type RedirectPolicy struct {
+	// NoFollow returns 3xx responses instead of following them
+	NoFollow bool
+
+	// Max is the most redirects followed before giving up
+	Max int
+
+	// Trusted keeps credentials on redirects to other hosts
+	Trusted bool
+}
+
+// DefaultRedirectPolicy follows up to DefaultMaxRedirects redirects,
+// dropping credentials when one leaves the base URL's host
+func DefaultRedirectPolicy() RedirectPolicy {
+	return RedirectPolicy{Max: DefaultMaxRedirects}
+}
+
+// WithRedirects sets the redirect policy
+func WithRedirects(p RedirectPolicy) Option {
+	return func(c *Client) {
+		c.redirects = p
+	}
+}
+
+// redirectLimitError stops a redirect loop. Unlike connection errors, it
+// is not retried.
+type redirectLimitError struct {
+	max int
+}
+
+func (e *redirectLimitError) Error() string {
+	return fmt.Sprintf("stopped after %d redirects", e.max)
+}
+
+// redirectClient returns a copy of hc that follows redirects for one
+// attempt by the policy, recording each hop in chain
+func (c *Client) redirectClient(hc *http.Client, creds *types.ResolvedCredentials, chain *[]types.Redirect) *http.Client {
+	base, _ := url.Parse(creds.BaseURL)
+	rc := *hc
+	rc.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if c.redirects.NoFollow {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > c.redirects.Max {
+			return &redirectLimitError{max: c.redirects.Max}
+		}
+
+		hop := types.Redirect{Method: req.Method, URL: req.URL.String()}
+		if req.Response != nil {
+			hop.StatusCode = req.Response.StatusCode
+		}
+		if base != nil && !strings.EqualFold(req.URL.Host, base.Host) && !c.redirects.Trusted {
+			hop.Stripped = stripCredentials(req.Header, creds)
+		}
+		*chain = append(*chain, hop)
+
+		if c.verbose {
+			fmt.Printf("* Following %d redirect to %s\n", hop.StatusCode, c.redactor.URL(hop.URL))
+			if len(hop.Stripped) > 0 {
+				fmt.Printf("* Dropped credentials for %s: %s\n", req.URL.Host, strings.Join(hop.Stripped, ", "))
+			}
+		}
+		return nil
+	}
+	return &rc
+}
+
+// stripCredentials removes headers carrying credentials and returns their
+// names. Besides Authorization and Cookie, this covers the service's
+// credential headers and anything named like a secret or signature.
+func stripCredentials(h http.Header, creds *types.ResolvedCredentials) []string {
+	credHeaders := map[string]bool{}
+	for name := range creds.Headers {
+		credHeaders[http.CanonicalHeaderKey(name)] = true
+	}
+
+	var stripped []string
+	for name := range h {
+		if credHeaders[name] || redact.IsSensitiveHeader(name) || strings.HasPrefix(name, "X-Amz-") {
+			h.Del(name)
+			stripped = append(stripped, name)
+		}
+	}
+	sort.Strings(stripped)
+	return stripped
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestClient_Do_RedirectStripsCredentialsOffHost(t *testing.T) {
+	var gotAuth, gotKey, gotAccept string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotKey = r.Header.Get("X-Service-Key")
+		gotAccept = r.Header.Get("Accept")
+	}))
+	defer other.Close()
+
+	var sameHostAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start":
+			http.Redirect(w, r, "/moved", http.StatusFound)
+		case "/moved":
+			sameHostAuth = r.Header.Get("Authorization")
+			http.Redirect(w, r, other.URL+"/elsewhere", http.StatusMovedPermanently)
+		}
+	}))
+	defer server.Close()
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: server.URL,
+		Auth:    &types.ResolvedAuth{Type: types.AuthBearer, Token: "tok"},
+		Headers: map[string]string{"X-Service-Key": "k"},
+	}
+	req := &types.Request{Method: "GET", Path: "/start", Headers: map[string]string{"Accept": "application/json"}}
+
+	resp, err := New().Do(context.Background(), req, creds)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if sameHostAuth != "Bearer tok" {
+		t.Errorf("same-host redirect Authorization = %q, want kept", sameHostAuth)
+	}
+	if gotAuth != "" || gotKey != "" {
+		t.Errorf("cross-host redirect sent Authorization=%q X-Service-Key=%q, want both dropped", gotAuth, gotKey)
+	}
+	if gotAccept != "application/json" {
+		t.Errorf("Accept = %q, want kept", gotAccept)
+	}
+
+	if len(resp.Redirects) != 2 {
+		t.Fatalf("Redirects = %+v, want 2", resp.Redirects)
+	}
+	if r := resp.Redirects[0]; r.StatusCode != http.StatusFound || !strings.HasSuffix(r.URL, "/moved") || len(r.Stripped) != 0 {
+		t.Errorf("Redirects[0] = %+v", r)
+	}
+	if r := resp.Redirects[1]; r.StatusCode != http.StatusMovedPermanently || strings.Join(r.Stripped, ",") != "Authorization,X-Service-Key" {
+		t.Errorf("Redirects[1] = %+v", r)
+	}
+
+	// Trusted redirects keep credentials
+	if _, err := New(WithRedirects(RedirectPolicy{Max: 10, Trusted: true})).Do(context.Background(), req, creds); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if gotAuth != "Bearer tok" {
+		t.Errorf("trusted redirect Authorization = %q, want kept", gotAuth)
+	}
+}
+
+func TestClient_Do_NoFollow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/next", http.StatusSeeOther)
+	}))
+	defer server.Close()
+
+	c := New(WithRedirects(RedirectPolicy{NoFollow: true}))
+	resp, err := c.Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, &types.ResolvedCredentials{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusSeeOther || resp.Headers["Location"][0] != "/next" || len(resp.Redirects) != 0 {
+		t.Errorf("Do() = %d %v %+v, want the 303 itself", resp.StatusCode, resp.Headers["Location"], resp.Redirects)
+	}
+}
+
+func TestClient_Do_MaxRedirects(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	}))
+	defer server.Close()
+
+	c := New(
+		WithRedirects(RedirectPolicy{Max: 2}),
+		WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+	)
+	_, err := c.Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, &types.ResolvedCredentials{BaseURL: server.URL})
+	if err == nil || !strings.Contains(err.Error(), "stopped after 2 redirects") {
+		t.Fatalf("Do() error = %v, want redirect limit", err)
+	}
+	// The original request and two redirects, with no retries
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server calls = %d, want 3", got)
+	}
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+const (
+	// DefaultRetryBackoff is the wait before the first retry
+	DefaultRetryBackoff = 200 * time.Millisecond
+
+	// DefaultRetryMaxBackoff caps the exponential backoff
+	DefaultRetryMaxBackoff = 5 * time.Second
+)
+
+// DefaultRetryStatuses are retried when retry_on is not configured
+var DefaultRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// idempotentMethods may be repeated without changing the outcome
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// RetryPolicy controls how Do retries failed attempts. The zero value
+// makes a single attempt.
+type RetryPolicy struct {
+	MaxAttempts   int
+	Backoff       time.Duration
+	MaxBackoff    time.Duration
+	RetryOn       []int
+	NonIdempotent bool
+}
+
+// NewRetryPolicy builds a policy from config, applying defaults. A nil
+// config disables retries.
+func NewRetryPolicy(cfg *types.RetryConfig) (RetryPolicy, error) {
+	if cfg == nil {
+		return RetryPolicy{}, nil
+	}
+	if cfg.MaxAttempts < 0 {
+		return RetryPolicy{}, fmt.Errorf("max_attempts must not be negative, got %d", cfg.MaxAttempts)
+	}
+
+	p := RetryPolicy{
+		MaxAttempts:   cfg.MaxAttempts,
+		Backoff:       DefaultRetryBackoff,
+		MaxBackoff:    DefaultRetryMaxBackoff,
+		RetryOn:       DefaultRetryStatuses,
+		NonIdempotent: cfg.NonIdempotent,
+	}
+
+	var err error
+	if cfg.Backoff != "" {
+		if p.Backoff, err = time.ParseDuration(cfg.Backoff); err != nil || p.Backoff < 0 {
+			return RetryPolicy{}, fmt.Errorf("invalid backoff %q", cfg.Backoff)
+		}
+	}
+	if cfg.MaxBackoff != "" {
+		if p.MaxBackoff, err = time.ParseDuration(cfg.MaxBackoff); err != nil || p.MaxBackoff < 0 {
+			return RetryPolicy{}, fmt.Errorf("invalid max_backoff %q", cfg.MaxBackoff)
+		}
+	}
+	if p.MaxBackoff < p.Backoff {
+		p.MaxBackoff = p.Backoff
+	}
+
+	if len(cfg.RetryOn) > 0 {
+		for _, code := range cfg.RetryOn {
+			if code < 100 || code > 599 {
+				return RetryPolicy{}, fmt.Errorf("invalid status code %d in retry_on", code)
+			}
+		}
+		p.RetryOn = cfg.RetryOn
+	}
+
+	return p, nil
+}
+
+// WithRetry sets the retry policy
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// RetryError is returned when a request was retried and every attempt
+// failed without a response
+type RetryError struct {
+	Attempts []types.Attempt
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.Err, len(e.Attempts))
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// connError marks a failure to get any response, which is safe to retry
+type connError struct {
+	err error
+}
+
+func (e *connError) Error() string { return "request failed: " + e.err.Error() }
+func (e *connError) Unwrap() error { return e.err }
+
+// attemptsFor returns how many attempts req may get
+func (p RetryPolicy) attemptsFor(req *types.Request) int {
+	if p.MaxAttempts <= 1 {
+		return 1
+	}
+	if idempotentMethods[strings.ToUpper(req.Method)] || p.NonIdempotent {
+		return p.MaxAttempts
+	}
+	// A client-supplied idempotency key makes POST and PATCH safe to repeat
+	for key, value := range req.Headers {
+		if strings.EqualFold(key, "Idempotency-Key") && value != "" {
+			return p.MaxAttempts
+		}
+	}
+	return 1
+}
+
+// retryable reports whether an attempt's outcome should be retried
+func (p RetryPolicy) retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		var ce *connError
+		return ctx.Err() == nil && errors.As(err, &ce)
+	}
+	for _, code := range p.RetryOn {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the wait before retry n (1-based): exponential growth
+// capped at MaxBackoff, with the upper half jittered
+func (p RetryPolicy) backoff(n int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < n && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// retryAfter parses a Retry-After header, in seconds or as an HTTP date
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := strings.TrimSpace(h.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		if secs > math.MaxInt64/int(time.Second) {
+			return math.MaxInt64, true
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestNewRetryPolicy(t *testing.T) {
+	p, err := NewRetryPolicy(nil)
+	if err != nil || p.MaxAttempts != 0 {
+		t.Fatalf("NewRetryPolicy(nil) = %+v, %v; want zero policy", p, err)
+	}
+
+	p, err = NewRetryPolicy(&types.RetryConfig{MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("NewRetryPolicy() error = %v", err)
+	}
+	if p.Backoff != DefaultRetryBackoff || p.MaxBackoff != DefaultRetryMaxBackoff || len(p.RetryOn) != len(DefaultRetryStatuses) {
+		t.Errorf("defaults not applied: %+v", p)
+	}
+
+	invalid := []*types.RetryConfig{
+		{MaxAttempts: -1},
+		{MaxAttempts: 3, Backoff: "soon"},
+		{MaxAttempts: 3, MaxBackoff: "-1s"},
+		{MaxAttempts: 3, RetryOn: []int{42}},
+	}
+	for _, cfg := range invalid {
+		if _, err := NewRetryPolicy(cfg); err == nil {
+			t.Errorf("NewRetryPolicy(%+v) should fail", cfg)
+		}
+	}
+}
+
+func TestRetryPolicy_AttemptsFor(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3}
+
+	tests := []struct {
+		name string
+		req  *types.Request
+		opt  bool
+		want int
+	}{
+		{"GET", &types.Request{Method: "GET"}, false, 3},
+		{"DELETE", &types.Request{Method: "DELETE"}, false, 3},
+		{"POST", &types.Request{Method: "POST"}, false, 1},
+		{"POST opted in", &types.Request{Method: "POST"}, true, 3},
+		{"POST with idempotency key", &types.Request{Method: "POST", Headers: map[string]string{"idempotency-key": "abc"}}, false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p.NonIdempotent = tt.opt
+			if got := p.attemptsFor(tt.req); got != tt.want {
+				t.Errorf("attemptsFor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+
+	for n, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 5: 300 * time.Millisecond} {
+		for i := 0; i < 20; i++ {
+			if d := p.backoff(n); d < max/2 || d > max {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", n, d, max/2, max)
+			}
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"2", 2 * time.Second, true},
+		{"99999999999", math.MaxInt64, true}, // would overflow
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second, true},
+		{"later", 0, false},
+	}
+
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.value != "" {
+			h.Set("Retry-After", tt.value)
+		}
+		got, ok := retryAfter(h, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("retryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestClient_Do_RetriesStatus(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := New(WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond, RetryOn: DefaultRetryStatuses}))
+	resp, err := c.Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, &types.ResolvedCredentials{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || string(resp.Body) != "ok" {
+		t.Errorf("Do() = %d %q, want 200 ok", resp.StatusCode, resp.Body)
+	}
+	if len(resp.Attempts) != 3 || resp.Attempts[0].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Attempts = %+v, want 3 with first 503", resp.Attempts)
+	}
+}
+
+func TestClient_Do_CapsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// Uncapped, the day-long wait would pass the deadline and not retry
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := New(WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, RetryOn: DefaultRetryStatuses}))
+	resp, err := c.Do(ctx, &types.Request{Method: "GET", Path: "/"}, &types.ResolvedCredentials{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if len(resp.Attempts) != 2 || resp.Attempts[0].Wait != 10*time.Millisecond {
+		t.Errorf("Attempts = %+v, want a retry after MaxBackoff", resp.Attempts)
+	}
+}
+
+func TestClient_Do_NoRetryForPOST(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := New(WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond, RetryOn: DefaultRetryStatuses}))
+	resp, err := c.Do(context.Background(), &types.Request{Method: "POST", Path: "/", Body: "{}"}, &types.ResolvedCredentials{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusBadGateway || calls.Load() != 1 {
+		t.Errorf("Do() = %d after %d calls, want 502 after 1", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestClient_Do_RetriesConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	c := New(WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+	_, err := c.Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, &types.ResolvedCredentials{BaseURL: url})
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Do() error = %v, want *RetryError", err)
+	}
+	if len(retryErr.Attempts) != 2 || retryErr.Attempts[0].Error == "" {
+		t.Errorf("Attempts = %+v, want 2 failed attempts", retryErr.Attempts)
+	}
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/pkg/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// signSigV4 signs a fully built request with AWS Signature Version 4.
+// It must run last, after every header and query parameter is set.
+func (c *Client) signSigV4(ctx context.Context, httpReq *http.Request, body *requestBody, auth *types.ResolvedAuth) error {
+	creds, region, err := c.awsCredentials(ctx, auth)
+	if err != nil {
+		return sreerrors.SigV4SigningFailed(auth.SigningService, err)
+	}
+
+	payloadHash, err := body.sha256Hex()
+	if err != nil {
+		return sreerrors.SigV4SigningFailed(auth.SigningService, err)
+	}
+	httpReq.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	if err := v4.NewSigner().SignHTTP(ctx, creds, httpReq, payloadHash, auth.SigningService, region, time.Now().UTC()); err != nil {
+		return sreerrors.SigV4SigningFailed(auth.SigningService, err)
+	}
+	return nil
+}
+
+// awsCredentials returns signing credentials and region for a sigv4 auth
+// block. Credentials from the AWS chain are loaded once per client.
+func (c *Client) awsCredentials(ctx context.Context, auth *types.ResolvedAuth) (aws.Credentials, string, error) {
+	c.awsMu.Lock()
+	defer c.awsMu.Unlock()
+
+	if c.awsCreds == nil {
+		provider, region, err := loadAWSCredentials(ctx, auth)
+		if err != nil {
+			return aws.Credentials{}, "", err
+		}
+		c.awsCreds = provider
+		c.awsRegion = region
+	}
+
+	if c.awsRegion == "" {
+		return aws.Credentials{}, "", fmt.Errorf("no region configured")
+	}
+
+	creds, err := c.awsCreds.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, "", fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	return creds, c.awsRegion, nil
+}
+
+// loadAWSCredentials builds the credentials provider for an auth block:
+// static keys, or the shared config chain for the profile, optionally
+// assuming a role
+func loadAWSCredentials(ctx context.Context, auth *types.ResolvedAuth) (aws.CredentialsProvider, string, error) {
+	region := auth.Region
+
+	if auth.AccessKeyID != "" && auth.RoleARN == "" {
+		if region == "" {
+			region = os.Getenv("AWS_REGION")
+		}
+		return credentials.NewStaticCredentialsProvider(auth.AccessKeyID, auth.SecretAccessKey, auth.SessionToken), region, nil
+	}
+
+	var opts []func(*config.LoadOptions) error
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+	if auth.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(auth.Profile))
+	}
+	if auth.AccessKeyID != "" {
+		// Static keys used only to assume the role
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(auth.AccessKeyID, auth.SecretAccessKey, auth.SessionToken),
+		))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	provider := awsCfg.Credentials
+	if auth.RoleARN != "" {
+		provider = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(awsCfg), auth.RoleARN))
+	}
+	return provider, awsCfg.Region, nil
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// StreamSampleSize is how much of a streamed body is kept in Response.Body
+const StreamSampleSize = 4096
+
+// StreamFunc consumes a response body as it arrives. resp carries the
+// status and headers; its Body is filled with a sample once fn returns.
+type StreamFunc func(resp *types.Response, body io.Reader) error
+
+// DoStream executes a request like Do, but hands the body to fn as it
+// arrives instead of buffering it. The client timeout only bounds waiting
+// for the response headers; cancel ctx to bound the whole stream.
+func (c *Client) DoStream(ctx context.Context, req *types.Request, creds *types.ResolvedCredentials, fn StreamFunc) (*types.Response, error) {
+	return c.do(ctx, req, creds, fn)
+}
+
+// attemptContext returns the context of one attempt. When streaming, it is
+// cancelled if the headers take longer than the client timeout; headersDone
+// stops that timer and reports whether it had not fired yet. cancel must be
+// called once the body is consumed.
+func (c *Client) attemptContext(ctx context.Context, streaming bool) (actx context.Context, headersDone func() bool, cancel context.CancelFunc) {
+	if !streaming || c.httpClient.Timeout <= 0 {
+		return ctx, func() bool { return true }, func() {}
+	}
+
+	actx, cancel = context.WithCancel(ctx)
+	timer := time.AfterFunc(c.httpClient.Timeout, cancel)
+	return actx, timer.Stop, cancel
+}
+
+// streamResponse passes the body to fn, keeping a sample of it
+func streamResponse(resp *http.Response, attempts []types.Attempt, redirects []types.Redirect, tr *tracer, fn StreamFunc) (*types.Response, error) {
+	defer func() { _ = resp.Body.Close() }()
+
+	out := &types.Response{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Proto:      resp.Proto,
+		Headers:    resp.Header,
+		Attempts:   attempts,
+		Redirects:  redirects,
+		Streamed:   true,
+	}
+
+	wire := &countingReader{r: resp.Body}
+	decoded, encoding, closeDecoder, err := decodeBody(wire, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeDecoder()
+
+	body := &sampler{r: decoded}
+	err = fn(out, body)
+
+	out.Body = body.sample
+	out.Trailers = resp.Trailer
+	out.Size = body.n
+	out.Truncated = body.n > int64(len(body.sample))
+	if encoding != "" {
+		out.Encoding, out.EncodedSize = encoding, wire.n
+	}
+	out.Timing = tr.timing(time.Now())
+	return out, err
+}
+
+// sampler counts bytes read and keeps the first StreamSampleSize of them
+type sampler struct {
+	r      io.Reader
+	n      int64
+	sample []byte
+}
+
+func (s *sampler) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if room := StreamSampleSize - len(s.sample); room > 0 {
+		s.sample = append(s.sample, p[:min(n, room)]...)
+	}
+	s.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestClient_DoStream(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: first\n\n")
+		w.(http.Flusher).Flush()
+
+		<-release
+		_, _ = io.WriteString(w, strings.Repeat("x", StreamSampleSize))
+	}))
+	defer server.Close()
+
+	// The body outlives the timeout, which only bounds the headers
+	c := New(WithTimeout(50 * time.Millisecond))
+	var got strings.Builder
+	resp, err := c.DoStream(context.Background(), &types.Request{Method: "GET", Path: "/"}, &types.ResolvedCredentials{BaseURL: server.URL},
+		func(resp *types.Response, body io.Reader) error {
+			buf := make([]byte, 64)
+			n, err := body.Read(buf)
+			if err != nil {
+				return err
+			}
+			// The first chunk arrives before the server finishes
+			got.Write(buf[:n])
+			time.Sleep(100 * time.Millisecond)
+			close(release)
+
+			rest, err := io.ReadAll(body)
+			got.Write(rest)
+			return err
+		})
+	if err != nil {
+		t.Fatalf("DoStream() error = %v", err)
+	}
+
+	if !strings.HasPrefix(got.String(), "data: first\n\n") {
+		t.Errorf("stream does not start with the first event: %.40q", got.String())
+	}
+	if !resp.Streamed || !resp.Truncated || len(resp.Body) != StreamSampleSize {
+		t.Errorf("Streamed = %v, Truncated = %v, sample = %d bytes; want a truncated %d byte sample", resp.Streamed, resp.Truncated, len(resp.Body), StreamSampleSize)
+	}
+	if resp.Size != int64(len("data: first\n\n")+StreamSampleSize) {
+		t.Errorf("Size = %d, want full body length", resp.Size)
+	}
+}
+
+func TestClient_DoStream_HeaderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	c := New(WithTimeout(50 * time.Millisecond))
+	_, err := c.DoStream(context.Background(), &types.Request{Method: "GET", Path: "/"}, &types.ResolvedCredentials{BaseURL: server.URL},
+		func(*types.Response, io.Reader) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "no response within") {
+		t.Errorf("DoStream() error = %v, want header timeout", err)
+	}
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// tracer records connection events of one attempt with httptrace. When an
+// attempt sends more than one request (auth challenges), the last wins.
+type tracer struct {
+	mu sync.Mutex
+
+	start                     time.Time
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	gotConn, firstByte        time.Time
+}
+
+// newTracer starts timing an attempt
+func newTracer() *tracer {
+	return &tracer{start: time.Now()}
+}
+
+// context returns ctx with the tracer's hooks installed
+func (t *tracer) context(ctx context.Context) context.Context {
+	mark := func(field *time.Time) {
+		t.mu.Lock()
+		*field = time.Now()
+		t.mu.Unlock()
+	}
+
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { mark(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { mark(&t.dnsDone) },
+		ConnectStart: func(_, _ string) {
+			// Happy Eyeballs may dial twice, keep the first start
+			t.mu.Lock()
+			if t.connectStart.IsZero() || !t.connectDone.IsZero() {
+				t.connectStart = time.Now()
+				t.connectDone = time.Time{}
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone:          func(_, _ string, _ error) { mark(&t.connectDone) },
+		TLSHandshakeStart:    func() { mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { mark(&t.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { mark(&t.gotConn) },
+		GotFirstResponseByte: func() { mark(&t.firstByte) },
+	})
+}
+
+// timing returns the breakdown once the body was read at end
+func (t *tracer) timing(end time.Time) *types.Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	between := func(from, to time.Time) time.Duration {
+		if from.IsZero() || to.IsZero() || to.Before(from) {
+			return 0
+		}
+		return to.Sub(from)
+	}
+
+	return &types.Timing{
+		DNS:      between(t.dnsStart, t.dnsDone),
+		Connect:  between(t.connectStart, t.connectDone),
+		TLS:      between(t.tlsStart, t.tlsDone),
+		TTFB:     between(t.gotConn, t.firstByte),
+		Transfer: between(t.firstByte, end),
+		Total:    between(t.start, end),
+	}
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestClient_Do_Timing(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := New()
+	c.httpClient = server.Client()
+
+	resp, err := c.Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, &types.ResolvedCredentials{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	timing := resp.Timing
+	if timing == nil {
+		t.Fatal("Timing should be set")
+	}
+	if timing.Connect <= 0 || timing.TLS <= 0 {
+		t.Errorf("Connect = %v, TLS = %v; want both measured on a new connection", timing.Connect, timing.TLS)
+	}
+	if timing.TTFB < 20*time.Millisecond {
+		t.Errorf("TTFB = %v, want at least the server delay", timing.TTFB)
+	}
+	if timing.Total < timing.TTFB+timing.TLS {
+		t.Errorf("Total = %v, want at least TTFB + TLS", timing.Total)
+	}
+}
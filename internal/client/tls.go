@@ -0,0 +1,108 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// httpClientFor returns the HTTP client to use for a request. Services with
+// a tls block, a proxy or a pinned protocol get a dedicated transport, built
+// once per creds.
+func (c *Client) httpClientFor(creds *types.ResolvedCredentials) (*http.Client, error) {
+	c.derivedMu.Lock()
+	defer c.derivedMu.Unlock()
+	if c.derived != nil && c.derivedFor == creds {
+		return c.derived, nil
+	}
+
+	protocols, err := protocolsFor(c.protocol, creds.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if c.protocol == types.ProtocolHTTP3 {
+		transport, err := h3Transport(creds)
+		if err != nil {
+			return nil, err
+		}
+		hc := *c.httpClient
+		hc.Transport = transport
+		c.derived, c.derivedFor = &hc, creds
+		return &hc, nil
+	}
+	if creds.TLS == nil && creds.Proxy == nil && protocols == nil {
+		return c.httpClient, nil
+	}
+
+	base, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok || base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transport := base.Clone()
+
+	if creds.TLS != nil {
+		tlsConfig, err := buildTLSConfig(creds.TLS)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if creds.Proxy != nil {
+		proxy, err := proxyFunc(creds.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = proxy
+	}
+
+	if protocols != nil {
+		transport.Protocols = protocols
+	}
+
+	hc := *c.httpClient
+	hc.Transport = transport
+	c.derived, c.derivedFor = &hc, creds
+	return &hc, nil
+}
+
+// HTTPClient returns an http.Client with the service's tls and proxy
+// settings, for side requests such as fetching OAuth2 tokens. The
+// service's protocol pin is not applied.
+func HTTPClient(creds *types.ResolvedCredentials, timeout time.Duration) (*http.Client, error) {
+	return New(WithTimeout(timeout)).httpClientFor(creds)
+}
+
+// buildTLSConfig creates a tls.Config with the client certificate and CA
+func buildTLSConfig(t *types.ResolvedTLS) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify, // opt-in, refused in protected envs
+	}
+
+	if t.CertPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(t.CertPEM), []byte(t.KeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if t.CAPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(t.CAPEM)) {
+			return nil, fmt.Errorf("invalid CA: no PEM certificates found")
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
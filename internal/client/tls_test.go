@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// selfSignedPEM generates a self-signed client certificate and key
+func selfSignedPEM(t *testing.T, cn string) (certPEM, keyPEM string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return certPEM, keyPEM
+}
+
+// newMTLSServer starts a TLS server that requires a client certificate
+// signed by clientCA and echoes the client's common name
+func newMTLSServer(t *testing.T, clientCA string) (*httptest.Server, string) {
+	t.Helper()
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM([]byte(clientCA))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	return server, serverCA
+}
+
+func TestClient_Do_MutualTLS(t *testing.T) {
+	certPEM, keyPEM := selfSignedPEM(t, "sreq-client")
+	server, serverCA := newMTLSServer(t, certPEM)
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: server.URL,
+		TLS:     &types.ResolvedTLS{CertPEM: certPEM, KeyPEM: keyPEM, CAPEM: serverCA},
+	}
+
+	resp, err := New().Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, creds)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if string(resp.Body) != "sreq-client" {
+		t.Errorf("server saw client %q, want %q", resp.Body, "sreq-client")
+	}
+}
+
+func TestClient_Do_MutualTLS_Failures(t *testing.T) {
+	certPEM, keyPEM := selfSignedPEM(t, "sreq-client")
+	server, serverCA := newMTLSServer(t, certPEM)
+
+	tests := []struct {
+		name string
+		tls  *types.ResolvedTLS
+	}{
+		{"no client certificate", &types.ResolvedTLS{CAPEM: serverCA}},
+		{"unknown server CA", &types.ResolvedTLS{CertPEM: certPEM, KeyPEM: keyPEM}},
+		{"mismatched key", &types.ResolvedTLS{CertPEM: certPEM, KeyPEM: "not a key", CAPEM: serverCA}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := &types.ResolvedCredentials{BaseURL: server.URL, TLS: tt.tls}
+			if _, err := New().Do(context.Background(), &types.Request{Method: "GET", Path: "/"}, creds); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestClient_Do_InsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req := &types.Request{Method: "GET", Path: "/"}
+
+	// Without the toggle the self-signed certificate is rejected
+	if _, err := New().Do(context.Background(), req, &types.ResolvedCredentials{BaseURL: server.URL}); err == nil {
+		t.Fatal("expected certificate error")
+	}
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: server.URL,
+		TLS:     &types.ResolvedTLS{InsecureSkipVerify: true},
+	}
+	if _, err := New().Do(context.Background(), req, creds); err != nil {
+		t.Errorf("Do() error = %v", err)
+	}
+}
+
+func TestHTTPClient_UsesServiceTLSAndProxy(t *testing.T) {
+	certPEM, keyPEM := selfSignedPEM(t, "sreq-client")
+	server, serverCA := newMTLSServer(t, certPEM)
+
+	creds := &types.ResolvedCredentials{
+		BaseURL: "https://api.example.test",
+		TLS:     &types.ResolvedTLS{CertPEM: certPEM, KeyPEM: keyPEM, CAPEM: serverCA},
+	}
+	hc, err := HTTPClient(creds, 5*time.Second)
+	if err != nil {
+		t.Fatalf("HTTPClient() error = %v", err)
+	}
+	resp, err := hc.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	var proxied bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { proxied = true }))
+	defer proxy.Close()
+	creds = &types.ResolvedCredentials{BaseURL: "http://api.example.test", Proxy: &types.ResolvedProxy{URL: proxy.URL}}
+	if hc, err = HTTPClient(creds, 5*time.Second); err != nil {
+		t.Fatalf("HTTPClient() error = %v", err)
+	}
+	if resp, err = hc.Get("http://login.example.test/oauth2/token"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if !proxied {
+		t.Error("request did not go through the service's proxy")
+	}
+}
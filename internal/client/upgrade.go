@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// WithTokenQuery sends bearer tokens (bearer, oauth2 and jwt auth) as the
+// query parameter name instead of the Authorization header, as browser
+// WebSocket clients must
+func WithTokenQuery(name string) Option {
+	return func(c *Client) {
+		c.tokenQuery = name
+	}
+}
+
+// Upgrade sends req as an HTTP/1.1 upgrade request, such as a WebSocket
+// handshake, with auth applied as for Do. On 101 Switching Protocols it
+// returns the response and the connection, which the caller must close.
+// Any other response is returned read, with a nil connection. The client
+// timeout bounds the handshake only, and it is not retried.
+func (c *Client) Upgrade(ctx context.Context, req *types.Request, creds *types.ResolvedCredentials) (*types.Response, io.ReadWriteCloser, error) {
+	hc, err := c.httpClientFor(creds)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A client timeout would also cut the upgraded connection
+	upgradeHC := *hc
+	upgradeHC.Timeout = 0
+
+	start := time.Now()
+	tr := newTracer()
+	actx, headersDone, cancel := c.attemptContext(tr.context(ctx), true)
+	resp, redirects, err := c.attempt(actx, &upgradeHC, req, creds)
+	if !headersDone() && err != nil {
+		err = fmt.Errorf("no response within %s: %w", c.httpClient.Timeout, err)
+	}
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	attempts := []types.Attempt{{StatusCode: resp.StatusCode, Duration: time.Since(start)}}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer cancel()
+		out, err := readResponse(resp, attempts, redirects, tr)
+		return out, nil, err
+	}
+
+	conn, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		cancel()
+		_ = resp.Body.Close()
+		return nil, nil, fmt.Errorf("upgraded connection is not writable")
+	}
+	out := &types.Response{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Proto:      resp.Proto,
+		Headers:    resp.Header,
+		Attempts:   attempts,
+		Redirects:  redirects,
+		Timing:     tr.timing(time.Now()),
+	}
+	return out, &upgradedConn{ReadWriteCloser: conn, cancel: cancel}, nil
+}
+
+// upgradedConn releases the handshake's context when closed
+type upgradedConn struct {
+	io.ReadWriteCloser
+	cancel context.CancelFunc
+}
+
+func (u *upgradedConn) Close() error {
+	defer u.cancel()
+	return u.ReadWriteCloser.Close()
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func TestClient_Upgrade(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_, _ = io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n")
+		line, _ := rw.ReadString('\n')
+		_, _ = io.WriteString(conn, line)
+	}))
+	defer server.Close()
+
+	req := &types.Request{Method: "GET", Path: "/", Headers: map[string]string{"Connection": "Upgrade", "Upgrade": "echo"}}
+	creds := &types.ResolvedCredentials{BaseURL: server.URL, Auth: &types.ResolvedAuth{Type: types.AuthBearer, Token: "tok"}}
+
+	// The connection outlives the timeout, which only bounds the handshake
+	resp, conn, err := New(WithTimeout(50*time.Millisecond)).Upgrade(context.Background(), req, creds)
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("StatusCode = %d, want 101", resp.StatusCode)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, err := io.WriteString(conn, "ping\n"); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping\n" {
+		t.Errorf("read %q, %v; want the echo", buf, err)
+	}
+
+	// Refused upgrades come back as ordinary responses
+	creds.Auth.Token = "wrong"
+	resp, refused, err := New().Upgrade(context.Background(), req, creds)
+	if err != nil || refused != nil {
+		t.Fatalf("Upgrade() = %v, %v; want a response without a connection", refused, err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized || string(resp.Body) != "unauthorized\n" {
+		t.Errorf("response = %d %q", resp.StatusCode, resp.Body)
+	}
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// buildURL joins a request path onto the base URL. The base URL's path is
+// kept as a prefix with exactly one slash at the join, and query strings
+// from both are combined.
+func buildURL(baseURL, path string) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base url: %w", err)
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return "", fmt.Errorf("invalid base url %q: scheme and host are required", baseURL)
+	}
+
+	relPath, relQuery, _ := strings.Cut(path, "?")
+
+	joined := base.EscapedPath()
+	if relPath != "" {
+		joined = strings.TrimRight(joined, "/") + "/" + strings.TrimLeft(relPath, "/")
+	}
+
+	var queries []string
+	for _, q := range []string{base.RawQuery, relQuery} {
+		if q != "" {
+			queries = append(queries, q)
+		}
+	}
+
+	host := base.Host
+	if base.User != nil {
+		host = base.User.String() + "@" + host
+	}
+	result := base.Scheme + "://" + host + joined
+	if len(queries) > 0 {
+		result += "?" + strings.Join(queries, "&")
+	}
+
+	if _, err := url.Parse(result); err != nil {
+		return "", fmt.Errorf("invalid request url: %w", err)
+	}
+	return result, nil
+}
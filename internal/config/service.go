@@ -0,0 +1,201 @@
+package config
+
+import (
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// ResolveService returns the effective configuration of a service for an
+// environment: the `extends` chain is merged from the root parent down,
+// then the service's `envs[env]` override is applied on top.
+//
+// If env is empty, no environment override is applied.
+func ResolveService(cfg *types.Config, name, env string) (*types.ServiceConfig, error) {
+	if _, exists := cfg.Services[name]; !exists {
+		return nil, sreerrors.ServiceNotFound(name)
+	}
+
+	merged, err := mergeChain(cfg, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if override, ok := merged.Envs[env]; ok && env != "" {
+		merged = applyEnvOverride(merged, override)
+	}
+
+	merged.Name = name
+	merged.Extends = ""
+	merged.Envs = nil
+	return &merged, nil
+}
+
+// mergeChain merges a service with all of its ancestors. chain holds the
+// services already visited, to detect cycles.
+func mergeChain(cfg *types.Config, name string, chain []string) (types.ServiceConfig, error) {
+	for _, visited := range chain {
+		if visited == name {
+			return types.ServiceConfig{}, sreerrors.ServiceExtendsCycle(append(chain, name))
+		}
+	}
+	chain = append(chain, name)
+
+	svc, exists := cfg.Services[name]
+	if !exists {
+		// Only reachable through `extends`, the root is checked by the caller
+		return types.ServiceConfig{}, sreerrors.ServiceExtendsNotFound(chain[len(chain)-2], name)
+	}
+
+	if svc.Extends == "" {
+		return copyService(svc), nil
+	}
+
+	parent, err := mergeChain(cfg, svc.Extends, chain)
+	if err != nil {
+		return types.ServiceConfig{}, err
+	}
+
+	return mergeService(parent, svc), nil
+}
+
+// mergeService overlays child on top of base
+func mergeService(base, child types.ServiceConfig) types.ServiceConfig {
+	result := base
+	result.Name = child.Name
+	if child.ConsulKey != "" {
+		result.ConsulKey = child.ConsulKey
+	}
+	if child.AWSPrefix != "" {
+		result.AWSPrefix = child.AWSPrefix
+	}
+	result.Paths = mergePaths(base.Paths, child.Paths)
+	if child.Auth != nil {
+		result.Auth = child.Auth
+	}
+	if child.TLS != nil {
+		result.TLS = child.TLS
+	}
+	if child.Retry != nil {
+		result.Retry = child.Retry
+	}
+	if child.Proxy != nil {
+		result.Proxy = child.Proxy
+	}
+	if child.Protocol != "" {
+		result.Protocol = child.Protocol
+	}
+	if child.Cookies != nil {
+		result.Cookies = child.Cookies
+	}
+
+	if len(child.Envs) > 0 {
+		envs := make(map[string]types.ServiceEnvConfig, len(base.Envs)+len(child.Envs))
+		for env, override := range base.Envs {
+			envs[env] = override
+		}
+		for env, override := range child.Envs {
+			parent := envs[env]
+			if override.ConsulKey == "" {
+				override.ConsulKey = parent.ConsulKey
+			}
+			if override.AWSPrefix == "" {
+				override.AWSPrefix = parent.AWSPrefix
+			}
+			override.Paths = mergePaths(parent.Paths, override.Paths)
+			if override.Auth == nil {
+				override.Auth = parent.Auth
+			}
+			if override.TLS == nil {
+				override.TLS = parent.TLS
+			}
+			if override.Retry == nil {
+				override.Retry = parent.Retry
+			}
+			if override.Proxy == nil {
+				override.Proxy = parent.Proxy
+			}
+			if override.Protocol == "" {
+				override.Protocol = parent.Protocol
+			}
+			if override.Cookies == nil {
+				override.Cookies = parent.Cookies
+			}
+			envs[env] = override
+		}
+		result.Envs = envs
+	}
+
+	return result
+}
+
+// applyEnvOverride overlays an environment override on a service
+func applyEnvOverride(svc types.ServiceConfig, override types.ServiceEnvConfig) types.ServiceConfig {
+	if override.ConsulKey != "" {
+		svc.ConsulKey = override.ConsulKey
+	}
+	if override.AWSPrefix != "" {
+		svc.AWSPrefix = override.AWSPrefix
+	}
+	svc.Paths = mergePaths(svc.Paths, override.Paths)
+	if override.Auth != nil {
+		svc.Auth = override.Auth
+	}
+	if override.TLS != nil {
+		svc.TLS = override.TLS
+	}
+	if override.Retry != nil {
+		svc.Retry = override.Retry
+	}
+	if override.Proxy != nil {
+		svc.Proxy = override.Proxy
+	}
+	if override.Protocol != "" {
+		svc.Protocol = override.Protocol
+	}
+	if override.Cookies != nil {
+		svc.Cookies = override.Cookies
+	}
+	return svc
+}
+
+// ProxyFor returns the proxy a resolved service uses in env: the
+// service's own, else the environment's, else the global one, or nil
+// when none is configured.
+func ProxyFor(cfg *types.Config, svc *types.ServiceConfig, env string) *types.ProxyConfig {
+	if svc.Proxy != nil {
+		return svc.Proxy
+	}
+	if proxy := cfg.EnvProxies[env]; proxy != nil {
+		return proxy
+	}
+	return cfg.Proxy
+}
+
+// mergePaths returns a new map with override's entries on top of base
+func mergePaths(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range override {
+		result[k] = v
+	}
+	return result
+}
+
+// copyService returns a copy of svc that shares no maps with the original
+func copyService(svc types.ServiceConfig) types.ServiceConfig {
+	svc.Paths = mergePaths(svc.Paths, nil)
+	if svc.Envs != nil {
+		envs := make(map[string]types.ServiceEnvConfig, len(svc.Envs))
+		for env, override := range svc.Envs {
+			override.Paths = mergePaths(override.Paths, nil)
+			envs[env] = override
+		}
+		svc.Envs = envs
+	}
+	return svc
+}
@@ -0,0 +1,328 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+func testServicesConfig() *types.Config {
+	return &types.Config{
+		Services: map[string]types.ServiceConfig{
+			"base-api": {
+				Paths: map[string]string{
+					"base_url": "shared/{env}/url",
+					"username": "shared/user",
+				},
+				Envs: map[string]types.ServiceEnvConfig{
+					"prod": {Paths: map[string]string{"base_url": "prod-shared/url"}},
+				},
+			},
+			"orders": {
+				Extends: "base-api",
+				Paths: map[string]string{
+					"password": "aws:orders/{env}#password",
+				},
+				Envs: map[string]types.ServiceEnvConfig{
+					"prod": {Paths: map[string]string{"password": "aws:orders-prod/vault#password"}},
+				},
+			},
+			"orders-eu": {
+				Extends: "orders",
+				Paths: map[string]string{
+					"username": "eu/user",
+				},
+			},
+			"simple": {
+				ConsulKey: "simple",
+				AWSPrefix: "simple-svc",
+				Envs: map[string]types.ServiceEnvConfig{
+					"prod": {AWSPrefix: "simple-prod"},
+				},
+			},
+		},
+	}
+}
+
+func TestResolveService_EnvOverride(t *testing.T) {
+	cfg := testServicesConfig()
+
+	svc, err := ResolveService(cfg, "simple", "prod")
+	if err != nil {
+		t.Fatalf("ResolveService() error = %v", err)
+	}
+	if svc.ConsulKey != "simple" {
+		t.Errorf("ConsulKey = %q, want %q", svc.ConsulKey, "simple")
+	}
+	if svc.AWSPrefix != "simple-prod" {
+		t.Errorf("AWSPrefix = %q, want %q", svc.AWSPrefix, "simple-prod")
+	}
+
+	svc, _ = ResolveService(cfg, "simple", "dev")
+	if svc.AWSPrefix != "simple-svc" {
+		t.Errorf("AWSPrefix for dev = %q, want %q", svc.AWSPrefix, "simple-svc")
+	}
+}
+
+func TestResolveService_Extends(t *testing.T) {
+	cfg := testServicesConfig()
+
+	tests := []struct {
+		name    string
+		service string
+		env     string
+		want    map[string]string
+	}{
+		{
+			name:    "inherits base paths",
+			service: "orders",
+			env:     "dev",
+			want: map[string]string{
+				"base_url": "shared/{env}/url",
+				"username": "shared/user",
+				"password": "aws:orders/{env}#password",
+			},
+		},
+		{
+			name:    "parent and child env overrides both apply",
+			service: "orders",
+			env:     "prod",
+			want: map[string]string{
+				"base_url": "prod-shared/url",
+				"username": "shared/user",
+				"password": "aws:orders-prod/vault#password",
+			},
+		},
+		{
+			name:    "multi-level inheritance",
+			service: "orders-eu",
+			env:     "prod",
+			want: map[string]string{
+				"base_url": "prod-shared/url",
+				"username": "eu/user",
+				"password": "aws:orders-prod/vault#password",
+			},
+		},
+		{
+			name:    "no env",
+			service: "orders-eu",
+			env:     "",
+			want: map[string]string{
+				"base_url": "shared/{env}/url",
+				"username": "eu/user",
+				"password": "aws:orders/{env}#password",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, err := ResolveService(cfg, tt.service, tt.env)
+			if err != nil {
+				t.Fatalf("ResolveService() error = %v", err)
+			}
+			if svc.Name != tt.service {
+				t.Errorf("Name = %q, want %q", svc.Name, tt.service)
+			}
+			if svc.Extends != "" || svc.Envs != nil {
+				t.Error("effective config should not carry extends/envs")
+			}
+			if len(svc.Paths) != len(tt.want) {
+				t.Errorf("Paths = %v, want %v", svc.Paths, tt.want)
+			}
+			for k, v := range tt.want {
+				if svc.Paths[k] != v {
+					t.Errorf("Paths[%s] = %q, want %q", k, svc.Paths[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveService_Auth(t *testing.T) {
+	bearer := &types.AuthConfig{Type: types.AuthBearer}
+	apiKey := &types.AuthConfig{Type: types.AuthAPIKey, Name: "X-Token"}
+
+	cfg := &types.Config{
+		Services: map[string]types.ServiceConfig{
+			"base": {
+				Auth: bearer,
+				Envs: map[string]types.ServiceEnvConfig{
+					"prod": {Auth: apiKey},
+				},
+			},
+			"child": {Extends: "base"},
+		},
+	}
+
+	tests := []struct {
+		env  string
+		want *types.AuthConfig
+	}{
+		{"dev", bearer},
+		{"prod", apiKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			svc, err := ResolveService(cfg, "child", tt.env)
+			if err != nil {
+				t.Fatalf("ResolveService() error = %v", err)
+			}
+			if svc.Auth != tt.want {
+				t.Errorf("Auth = %+v, want %+v", svc.Auth, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveService_DoesNotMutateConfig(t *testing.T) {
+	cfg := testServicesConfig()
+
+	if _, err := ResolveService(cfg, "orders-eu", "prod"); err != nil {
+		t.Fatalf("ResolveService() error = %v", err)
+	}
+
+	if got := cfg.Services["base-api"].Paths["username"]; got != "shared/user" {
+		t.Errorf("base-api username mutated to %q", got)
+	}
+	if got := len(cfg.Services["orders"].Paths); got != 1 {
+		t.Errorf("orders has %d paths after resolve, want 1", got)
+	}
+}
+
+func TestResolveService_Errors(t *testing.T) {
+	cfg := &types.Config{
+		Services: map[string]types.ServiceConfig{
+			"a":        {Extends: "b"},
+			"b":        {Extends: "c"},
+			"c":        {Extends: "a"},
+			"self":     {Extends: "self"},
+			"orphaned": {Extends: "missing"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		service  string
+		contains string
+	}{
+		{"not found", "nope", "Service 'nope' not found"},
+		{"cycle", "a", "a -> b -> c -> a"},
+		{"self reference", "self", "self -> self"},
+		{"missing parent", "orphaned", "extends unknown service 'missing'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResolveService(cfg, tt.service, "dev")
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("error = %q, should contain %q", err.Error(), tt.contains)
+			}
+		})
+	}
+}
+
+func TestResolveService_Retry(t *testing.T) {
+	base := &types.RetryConfig{MaxAttempts: 3}
+	prod := &types.RetryConfig{MaxAttempts: 1}
+
+	cfg := &types.Config{
+		Services: map[string]types.ServiceConfig{
+			"base":  {Retry: base, Envs: map[string]types.ServiceEnvConfig{"prod": {Retry: prod}}},
+			"child": {Extends: "base"},
+		},
+	}
+
+	for env, want := range map[string]*types.RetryConfig{"dev": base, "prod": prod} {
+		svc, err := ResolveService(cfg, "child", env)
+		if err != nil {
+			t.Fatalf("ResolveService() error = %v", err)
+		}
+		if svc.Retry != want {
+			t.Errorf("%s: Retry = %+v, want %+v", env, svc.Retry, want)
+		}
+	}
+}
+
+func TestResolveService_Protocol(t *testing.T) {
+	cfg := &types.Config{
+		Services: map[string]types.ServiceConfig{
+			"base":  {Protocol: types.ProtocolH2C, Envs: map[string]types.ServiceEnvConfig{"prod": {Protocol: types.ProtocolHTTP2}}},
+			"child": {Extends: "base"},
+		},
+	}
+
+	for env, want := range map[string]string{"dev": types.ProtocolH2C, "prod": types.ProtocolHTTP2} {
+		svc, err := ResolveService(cfg, "child", env)
+		if err != nil {
+			t.Fatalf("ResolveService() error = %v", err)
+		}
+		if svc.Protocol != want {
+			t.Errorf("%s: Protocol = %q, want %q", env, svc.Protocol, want)
+		}
+	}
+}
+
+func TestResolveService_Cookies(t *testing.T) {
+	on, off := true, false
+	cfg := &types.Config{
+		Services: map[string]types.ServiceConfig{
+			"base":      {Cookies: &on},
+			"child":     {Extends: "base"},
+			"stateless": {Extends: "base", Cookies: &off},
+			"per-env":   {Extends: "base", Envs: map[string]types.ServiceEnvConfig{"prod": {Cookies: &off}}},
+		},
+	}
+
+	tests := []struct {
+		service, env string
+		want         bool
+	}{
+		{"child", "dev", true},
+		{"stateless", "dev", false},
+		{"per-env", "dev", true},
+		{"per-env", "prod", false},
+	}
+	for _, tt := range tests {
+		svc, err := ResolveService(cfg, tt.service, tt.env)
+		if err != nil {
+			t.Fatalf("ResolveService() error = %v", err)
+		}
+		if svc.Cookies == nil || *svc.Cookies != tt.want {
+			t.Errorf("%s/%s: Cookies = %v, want %v", tt.service, tt.env, svc.Cookies, tt.want)
+		}
+	}
+}
+
+func TestProxyFor(t *testing.T) {
+	global := &types.ProxyConfig{URL: "http://proxy.corp:3128"}
+	prod := &types.ProxyConfig{URL: "socks5://socks.corp:1080"}
+	own := &types.ProxyConfig{URL: types.ProxyDirect}
+
+	cfg := &types.Config{
+		Proxy:      global,
+		EnvProxies: map[string]*types.ProxyConfig{"prod": prod},
+	}
+
+	tests := []struct {
+		name string
+		svc  *types.ServiceConfig
+		env  string
+		want *types.ProxyConfig
+	}{
+		{"global", &types.ServiceConfig{}, "dev", global},
+		{"environment", &types.ServiceConfig{}, "prod", prod},
+		{"service", &types.ServiceConfig{Proxy: own}, "prod", own},
+	}
+
+	for _, tt := range tests {
+		if got := ProxyFor(cfg, tt.svc, tt.env); got != tt.want {
+			t.Errorf("%s: ProxyFor() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package errors
 import (
 	"fmt"
 	"strings"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
 )
 
 // Error types for better categorization
@@ -97,6 +99,24 @@ func AWSAuthFailed(region string, cause error) *SreqError {
 	}
 }
 
+func OAuthTokenFailed(tokenURL string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrAuth,
+		Message:    fmt.Sprintf("Failed to obtain OAuth2 token from %s", tokenURL),
+		Cause:      cause,
+		Suggestion: "Check that:\n  1. token_url is correct and reachable\n  2. client_id/client_secret resolve to valid credentials\n  3. The requested scopes and audience are allowed for this client\n  Run 'sreq cache clear' to drop a stale refresh token.",
+	}
+}
+
+func SigV4SigningFailed(service string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrAuth,
+		Message:    fmt.Sprintf("Failed to sign request with AWS SigV4 for '%s'", service),
+		Cause:      cause,
+		Suggestion: "Check that:\n  1. AWS credentials are available for the configured profile (or access_key_id/secret_access_key resolve)\n  2. The role in role_arn can be assumed\n  3. A region is set in the auth block, with --region, or in the AWS profile",
+	}
+}
+
 // Provider errors
 func ProviderNotConfigured(provider string) *SreqError {
 	return &SreqError{
@@ -141,6 +161,36 @@ func BaseURLMissing(service, env string) *SreqError {
 	}
 }
 
+func GRPCReflectionFailed(service string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrNetwork,
+		Message:    fmt.Sprintf("Could not load gRPC descriptors from service '%s' by server reflection", service),
+		Cause:      cause,
+		Suggestion: "Enable server reflection on the service, or pass --proto (with --import-path) or --protoset.",
+	}
+}
+
+func InvalidGRPCMessage(method string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrValidation,
+		Message:    fmt.Sprintf("Invalid request message for %s", method),
+		Cause:      cause,
+		Suggestion: fmt.Sprintf("Run 'sreq grpc describe %s' to see the input type, then describe that type for its fields.", method),
+	}
+}
+
+func WebSocketUpgradeRefused(url, status, body string) *SreqError {
+	msg := fmt.Sprintf("WebSocket upgrade refused by %s: %s", url, status)
+	if body != "" {
+		msg += ": " + body
+	}
+	return &SreqError{
+		Type:       ErrNetwork,
+		Message:    msg,
+		Suggestion: "Check the path, and whether the server expects the token in the query string instead (--token-query access_token).",
+	}
+}
+
 // Validation errors
 func InvalidMethod(method string) *SreqError {
 	return &SreqError{
@@ -210,6 +260,22 @@ func ServiceAlreadyExists(name string) *SreqError {
 	}
 }
 
+func ServiceExtendsNotFound(service, parent string) *SreqError {
+	return &SreqError{
+		Type:       ErrConfig,
+		Message:    fmt.Sprintf("Service '%s' extends unknown service '%s'", service, parent),
+		Suggestion: fmt.Sprintf("Define service '%s' or fix the 'extends' field of '%s'", parent, service),
+	}
+}
+
+func ServiceExtendsCycle(chain []string) *SreqError {
+	return &SreqError{
+		Type:       ErrConfig,
+		Message:    fmt.Sprintf("Service inheritance cycle: %s", strings.Join(chain, " -> ")),
+		Suggestion: "Remove one of the 'extends' fields so services do not inherit from each other in a loop",
+	}
+}
+
 func InvalidPathMapping(mapping string) *SreqError {
 	return &SreqError{
 		Type:       ErrValidation,
@@ -234,6 +300,67 @@ func ServiceModeRequired() *SreqError {
 	}
 }
 
+func InvalidAuthConfig(service string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrConfig,
+		Message:    fmt.Sprintf("Invalid auth configuration for service '%s'", service),
+		Cause:      cause,
+		Suggestion: "Check the 'auth' block: type must be one of " + strings.Join(types.AuthTypes, ", ") + ", and templates may only use resolved fields ({username}, {password}, {api_key}, {base_url} or custom path keys)",
+	}
+}
+
+func InvalidTLSConfig(service string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrConfig,
+		Message:    fmt.Sprintf("Invalid TLS configuration for service '%s'", service),
+		Cause:      cause,
+		Suggestion: "Check the 'tls' block: cert and key must be set together and resolve to matching PEM data, and ca must contain PEM certificates.",
+	}
+}
+
+func InvalidRetryConfig(service string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrConfig,
+		Message:    fmt.Sprintf("Invalid retry configuration for service '%s'", service),
+		Cause:      cause,
+		Suggestion: "Check the 'retry' block: backoff and max_backoff are durations like 200ms or 5s, and retry_on lists HTTP status codes.",
+	}
+}
+
+func InvalidProxyConfig(service string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrConfig,
+		Message:    fmt.Sprintf("Invalid proxy configuration for service '%s'", service),
+		Cause:      cause,
+		Suggestion: "Check the 'proxy' block: url must be http://, https://, socks5:// or 'direct', with credentials in username and password.",
+	}
+}
+
+func InvalidProtocol(service string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrConfig,
+		Message:    fmt.Sprintf("Invalid protocol for service '%s'", service),
+		Cause:      cause,
+		Suggestion: "Use http1.1, h2 or h3 (https only), or h2c (http only) in 'protocol' or --protocol.",
+	}
+}
+
+func InsecureTLSNotAllowed(env string) *SreqError {
+	return &SreqError{
+		Type:       ErrValidation,
+		Message:    fmt.Sprintf("Skipping TLS verification is not allowed in protected environment '%s'", env),
+		Suggestion: "Configure the server's CA with 'tls.ca' instead, or adjust 'protected_envs' in ~/.sreq/config.yaml.",
+	}
+}
+
+func BenchNotAllowed(env string) *SreqError {
+	return &SreqError{
+		Type:       ErrValidation,
+		Message:    fmt.Sprintf("Load testing is not allowed in protected environment '%s'", env),
+		Suggestion: "Run 'sreq bench' against a non-protected environment, or adjust 'protected_envs' in ~/.sreq/config.yaml.",
+	}
+}
+
 // Resolver errors
 func PathResolutionFailed(path string, cause error) *SreqError {
 	return &SreqError{
@@ -260,3 +387,30 @@ func JSONParseFailed(cause error) *SreqError {
 		Suggestion: "Ensure the secret value is valid JSON format",
 	}
 }
+
+func InvalidPathTemplate(key string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrValidation,
+		Message:    fmt.Sprintf("Invalid path template for '%s'", key),
+		Cause:      cause,
+		Suggestion: "Provide missing values with --region, --project, --app or a context (-c), and write literal braces as {{ and }}",
+	}
+}
+
+func InvalidTransform(spec string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrValidation,
+		Message:    fmt.Sprintf("Invalid transform pipeline in path '%s'", spec),
+		Cause:      cause,
+		Suggestion: "Use format: path|transform|transform:arg (e.g., aws:svc/creds#token|b64dec|trim)",
+	}
+}
+
+func TransformFailed(source string, cause error) *SreqError {
+	return &SreqError{
+		Type:       ErrValidation,
+		Message:    fmt.Sprintf("Failed to transform value from '%s'", source),
+		Cause:      cause,
+		Suggestion: "Check that the secret value has the format expected by each transform",
+	}
+}
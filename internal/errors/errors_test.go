@@ -4,6 +4,8 @@ import (
 	"errors"
 	"strings"
 	"testing"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
 )
 
 func TestSreqError_Error(t *testing.T) {
@@ -319,6 +321,82 @@ func TestJSONParseFailed(t *testing.T) {
 	}
 }
 
+func TestInvalidPathTemplate(t *testing.T) {
+	cause := errors.New("unresolved placeholders")
+	err := InvalidPathTemplate("base_url", cause)
+	if err.Type != ErrValidation {
+		t.Errorf("Type = %v, want %v", err.Type, ErrValidation)
+	}
+}
+
+func TestInvalidTransform(t *testing.T) {
+	cause := errors.New("unknown transform")
+	err := InvalidTransform("aws:svc#key|rot13", cause)
+	if err.Type != ErrValidation {
+		t.Errorf("Type = %v, want %v", err.Type, ErrValidation)
+	}
+}
+
+func TestTransformFailed(t *testing.T) {
+	cause := errors.New("bad base64")
+	err := TransformFailed("svc/creds", cause)
+	if err.Type != ErrValidation {
+		t.Errorf("Type = %v, want %v", err.Type, ErrValidation)
+	}
+}
+
+func TestInvalidAuthConfig(t *testing.T) {
+	cause := errors.New("unknown auth type 'oauth'")
+	err := InvalidAuthConfig("billing", cause)
+	if err.Type != ErrConfig {
+		t.Errorf("Type = %v, want %v", err.Type, ErrConfig)
+	}
+	if !strings.Contains(err.Error(), "billing") {
+		t.Errorf("Error() = %q, should mention service", err.Error())
+	}
+	for _, authType := range types.AuthTypes {
+		if !strings.Contains(err.Suggestion, authType) {
+			t.Errorf("Suggestion = %q, should list %s", err.Suggestion, authType)
+		}
+	}
+}
+
+func TestOAuthTokenFailed(t *testing.T) {
+	err := OAuthTokenFailed("https://idp/token", errors.New("invalid_client"))
+	if err.Type != ErrAuth {
+		t.Errorf("Type = %v, want %v", err.Type, ErrAuth)
+	}
+	if !strings.Contains(err.Error(), "https://idp/token") {
+		t.Errorf("Error() = %q, should mention token URL", err.Error())
+	}
+}
+
+func TestSigV4SigningFailed(t *testing.T) {
+	err := SigV4SigningFailed("execute-api", errors.New("no region configured"))
+	if err.Type != ErrAuth {
+		t.Errorf("Type = %v, want %v", err.Type, ErrAuth)
+	}
+}
+
+func TestTLSErrors(t *testing.T) {
+	if err := InvalidTLSConfig("partner", errors.New("bad pem")); err.Type != ErrConfig {
+		t.Errorf("InvalidTLSConfig Type = %v, want %v", err.Type, ErrConfig)
+	}
+	if err := InvalidProxyConfig("partner", errors.New("bad url")); err.Type != ErrConfig {
+		t.Errorf("InvalidProxyConfig Type = %v, want %v", err.Type, ErrConfig)
+	}
+	if err := InvalidRetryConfig("partner", errors.New("bad duration")); err.Type != ErrConfig {
+		t.Errorf("InvalidRetryConfig Type = %v, want %v", err.Type, ErrConfig)
+	}
+	err := InsecureTLSNotAllowed("prod")
+	if err.Type != ErrValidation {
+		t.Errorf("InsecureTLSNotAllowed Type = %v, want %v", err.Type, ErrValidation)
+	}
+	if !strings.Contains(err.Error(), "prod") {
+		t.Errorf("Error() = %q, should mention env", err.Error())
+	}
+}
+
 func TestErrorTypes(t *testing.T) {
 	// Ensure all error types are distinct
 	types := []ErrorType{ErrConfig, ErrAuth, ErrProvider, ErrNetwork, ErrNotFound, ErrValidation}
@@ -0,0 +1,180 @@
+// Package graphql builds GraphQL-over-HTTP requests and reads their
+// responses
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Request is the JSON body POSTed for an operation
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is a GraphQL response. Data is null when the operation failed
+// before execution.
+type Response struct {
+	Data       json.RawMessage `json:"data,omitempty"`
+	Errors     []Error         `json:"errors,omitempty"`
+	Extensions json.RawMessage `json:"extensions,omitempty"`
+}
+
+// Error is one entry of a response's errors
+type Error struct {
+	Message    string         `json:"message"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Location is a position in the query document
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// String formats the error as "message (path: a.b.0, line 3:5, code: X)"
+func (e Error) String() string {
+	var details []string
+	if len(e.Path) > 0 {
+		parts := make([]string, len(e.Path))
+		for i, p := range e.Path {
+			parts[i] = fmt.Sprint(p)
+		}
+		details = append(details, "path: "+strings.Join(parts, "."))
+	}
+	for _, l := range e.Locations {
+		details = append(details, fmt.Sprintf("line %d:%d", l.Line, l.Column))
+	}
+	if code, ok := e.Extensions["code"].(string); ok {
+		details = append(details, "code: "+code)
+	}
+	if len(details) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s (%s)", e.Message, strings.Join(details, ", "))
+}
+
+// HasData reports whether the response carries a non-null data field
+func (r *Response) HasData() bool {
+	return len(r.Data) > 0 && !bytes.Equal(bytes.TrimSpace(r.Data), []byte("null"))
+}
+
+// ParseResponse decodes a response body. A body that is not a GraphQL
+// response, such as a proxy's error page, is an error.
+func ParseResponse(body []byte) (*Response, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("response is not JSON: %w", err)
+	}
+	_, hasData := fields["data"]
+	_, hasErrors := fields["errors"]
+	if !hasData && !hasErrors {
+		return nil, fmt.Errorf("response has neither data nor errors")
+	}
+
+	var resp Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid GraphQL response: %w", err)
+	}
+	return &resp, nil
+}
+
+var (
+	// Comments and string literals, which may contain anything
+	ignoredRe = regexp.MustCompile(`"""(?s:.*?)"""|"(?:[^"\\\n]|\\.)*"|#[^\n]*`)
+
+	// A variable definition, "$name: Type"; uses are written "arg: $name"
+	variableRe = regexp.MustCompile(`\$([_A-Za-z][_0-9A-Za-z]*)\s*:\s*((?:\[\s*)*[_A-Za-z][_0-9A-Za-z]*(?:\s*!)?(?:\s*\]\s*!?)*)`)
+)
+
+// VariableTypes returns the declared type of each variable in a query
+// document, such as {"id": "ID!", "tags": "[String!]"}
+func VariableTypes(query string) map[string]string {
+	query = ignoredRe.ReplaceAllString(query, " ")
+	types := map[string]string{}
+	for _, m := range variableRe.FindAllStringSubmatch(query, -1) {
+		types[m[1]] = strings.Join(strings.Fields(m[2]), "")
+	}
+	return types
+}
+
+// ParseVariables builds variables from "name=value" and "name:=json"
+// specs. A plain value is converted to the variable's declared type, so
+// "id=123" is a number for an Int and a string for an ID.
+func ParseVariables(specs []string, types map[string]string) (map[string]any, error) {
+	vars := map[string]any{}
+	for _, spec := range specs {
+		if name, raw, ok := strings.Cut(spec, ":="); ok && !strings.Contains(name, "=") {
+			var v any
+			if err := json.Unmarshal([]byte(raw), &v); err != nil {
+				return nil, fmt.Errorf("variable %s: invalid JSON %s", name, raw)
+			}
+			vars[name] = v
+			continue
+		}
+
+		name, value, ok := strings.Cut(spec, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid variable %q (expected name=value or name:=json)", spec)
+		}
+		v, err := coerce(value, types[name])
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %w", name, err)
+		}
+		vars[name] = v
+	}
+	return vars, nil
+}
+
+// coerce converts a command-line value to a GraphQL type
+func coerce(value, typ string) (any, error) {
+	named := strings.Trim(typ, "[]!")
+	if strings.HasPrefix(typ, "[") && strings.HasPrefix(strings.TrimSpace(value), "[") {
+		var v any
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return nil, fmt.Errorf("invalid list %s", value)
+		}
+		return v, nil
+	}
+
+	switch named {
+	case "Int":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an Int", value)
+		}
+		return n, nil
+	case "Float":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a Float", value)
+		}
+		return f, nil
+	case "Boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a Boolean", value)
+		}
+		return b, nil
+	case "", "String", "ID":
+		return value, nil
+	}
+
+	// Input objects are written as JSON; enums and custom scalars as text
+	if trimmed := strings.TrimSpace(value); strings.HasPrefix(trimmed, "{") {
+		var v any
+		if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+			return nil, fmt.Errorf("invalid %s object %s", named, value)
+		}
+		return v, nil
+	}
+	return value, nil
+}
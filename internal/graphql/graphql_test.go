@@ -0,0 +1,195 @@
+package graphql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVariableTypes(t *testing.T) {
+	query := `
+# $ignored: Int in a comment
+query Order($id: ID!, $limit: Int = 10, $tags: [ String! ] !, $filter: OrderFilter) {
+  order(id: $id, note: "$fake: Boolean") {
+    lines(first: $limit, tags: $tags, filter: $filter) { sku }
+  }
+}`
+
+	want := map[string]string{"id": "ID!", "limit": "Int", "tags": "[String!]!", "filter": "OrderFilter"}
+	if got := VariableTypes(query); !reflect.DeepEqual(got, want) {
+		t.Errorf("VariableTypes() = %v, want %v", got, want)
+	}
+}
+
+func TestParseVariables(t *testing.T) {
+	types := map[string]string{
+		"id":     "ID!",
+		"limit":  "Int",
+		"ratio":  "Float",
+		"active": "Boolean!",
+		"tags":   "[String!]",
+		"filter": "OrderFilter",
+		"status": "Status",
+	}
+
+	got, err := ParseVariables([]string{
+		"id=123",
+		"limit=5",
+		"ratio=0.5",
+		"active=true",
+		`tags=["a","b"]`,
+		`filter={"paid":true}`,
+		"status=SHIPPED",
+		"raw:=[1,2]",
+		"note=a=b",
+	}, types)
+	if err != nil {
+		t.Fatalf("ParseVariables() error = %v", err)
+	}
+
+	want := map[string]any{
+		"id":     "123",
+		"limit":  int64(5),
+		"ratio":  0.5,
+		"active": true,
+		"tags":   []any{"a", "b"},
+		"filter": map[string]any{"paid": true},
+		"status": "SHIPPED",
+		"raw":    []any{float64(1), float64(2)},
+		"note":   "a=b",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseVariables() = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseVariables_Errors(t *testing.T) {
+	types := map[string]string{"limit": "Int!", "active": "Boolean"}
+
+	tests := []struct {
+		spec     string
+		contains string
+	}{
+		{"limit=ten", `"ten" is not an Int`},
+		{"active=yes-please", "is not a Boolean"},
+		{"novalue", "expected name=value"},
+		{"raw:={bad", "invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseVariables([]string{tt.spec}, types)
+		if err == nil || !strings.Contains(err.Error(), tt.contains) {
+			t.Errorf("ParseVariables(%q) error = %v, should contain %q", tt.spec, err, tt.contains)
+		}
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	resp, err := ParseResponse([]byte(`{
+		"data": {"order": null},
+		"errors": [{"message": "not found", "path": ["order", 0], "locations": [{"line": 2, "column": 3}], "extensions": {"code": "NOT_FOUND"}}]
+	}`))
+	if err != nil {
+		t.Fatalf("ParseResponse() error = %v", err)
+	}
+	if !resp.HasData() {
+		t.Error("HasData() = false, want true")
+	}
+	if len(resp.Errors) != 1 {
+		t.Fatalf("Errors = %v", resp.Errors)
+	}
+	if got, want := resp.Errors[0].String(), "not found (path: order.0, line 2:3, code: NOT_FOUND)"; got != want {
+		t.Errorf("Error.String() = %q, want %q", got, want)
+	}
+
+	resp, _ = ParseResponse([]byte(`{"data": null, "errors": [{"message": "bad"}]}`))
+	if resp.HasData() {
+		t.Error("HasData() = true for null data")
+	}
+
+	for _, body := range []string{`<html>Bad Gateway</html>`, `{"message": "Unauthorized"}`} {
+		if _, err := ParseResponse([]byte(body)); err == nil {
+			t.Errorf("ParseResponse(%q) error = nil", body)
+		}
+	}
+}
+
+func TestSchema_SDL(t *testing.T) {
+	data := `{"__schema": {
+		"queryType": {"name": "Query"},
+		"mutationType": null,
+		"subscriptionType": null,
+		"directives": [
+			{"name": "include", "locations": ["FIELD"], "args": []},
+			{"name": "auth", "description": "Requires a role", "isRepeatable": false, "locations": ["FIELD_DEFINITION", "OBJECT"], "args": [{"name": "role", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}}]}
+		],
+		"types": [
+			{"kind": "OBJECT", "name": "Query", "fields": [
+				{"name": "order", "description": "Find an order", "args": [{"name": "id", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}}], "type": {"kind": "OBJECT", "name": "Order"}},
+				{"name": "orders", "args": [{"name": "first", "type": {"kind": "SCALAR", "name": "Int"}, "defaultValue": "10"}], "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "NON_NULL", "ofType": {"kind": "OBJECT", "name": "Order"}}}}}
+			]},
+			{"kind": "OBJECT", "name": "Order", "interfaces": [{"kind": "INTERFACE", "name": "Node"}], "fields": [
+				{"name": "id", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}},
+				{"name": "total", "args": [], "type": {"kind": "SCALAR", "name": "Int"}, "isDeprecated": true, "deprecationReason": "Use totalCents"},
+				{"name": "status", "args": [], "type": {"kind": "ENUM", "name": "Status"}}
+			]},
+			{"kind": "INTERFACE", "name": "Node", "fields": [{"name": "id", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}}]},
+			{"kind": "ENUM", "name": "Status", "enumValues": [{"name": "PAID"}, {"name": "LOST", "isDeprecated": true, "deprecationReason": "No longer supported"}]},
+			{"kind": "UNION", "name": "Result", "possibleTypes": [{"kind": "OBJECT", "name": "Order"}, {"kind": "OBJECT", "name": "Query"}]},
+			{"kind": "INPUT_OBJECT", "name": "OrderFilter", "description": "Filters\nfor orders", "inputFields": [{"name": "paid", "type": {"kind": "SCALAR", "name": "Boolean"}, "defaultValue": "false"}]},
+			{"kind": "SCALAR", "name": "String"},
+			{"kind": "SCALAR", "name": "DateTime"},
+			{"kind": "OBJECT", "name": "__Type", "fields": []}
+		]
+	}}`
+
+	schema, err := ParseSchema([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+
+	want := `"Requires a role"
+directive @auth(role: String!) on FIELD_DEFINITION | OBJECT
+
+type Query {
+  "Find an order"
+  order(id: ID!): Order
+  orders(first: Int = 10): [Order!]!
+}
+
+type Order implements Node {
+  id: ID!
+  total: Int @deprecated(reason: "Use totalCents")
+  status: Status
+}
+
+interface Node {
+  id: ID!
+}
+
+enum Status {
+  PAID
+  LOST @deprecated
+}
+
+union Result = Order | Query
+
+"""
+Filters
+for orders
+"""
+input OrderFilter {
+  paid: Boolean = false
+}
+
+scalar DateTime
+`
+	if got := schema.SDL(); got != want {
+		t.Errorf("SDL() =\n%s\nwant\n%s", got, want)
+	}
+
+	schema.QueryType = &namedRef{Name: "RootQuery"}
+	if got := schema.SDL(); !strings.HasPrefix(got, "schema {\n  query: RootQuery\n}\n\n") {
+		t.Errorf("SDL() with a renamed root should start with a schema block, got\n%s", got)
+	}
+}
@@ -0,0 +1,339 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// IntrospectionQuery fetches the full schema
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives {
+      name
+      description
+      isRepeatable
+      locations
+      args { ...InputValue }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+}`
+
+// Schema is the result of IntrospectionQuery
+type Schema struct {
+	QueryType        *namedRef   `json:"queryType"`
+	MutationType     *namedRef   `json:"mutationType"`
+	SubscriptionType *namedRef   `json:"subscriptionType"`
+	Types            []FullType  `json:"types"`
+	Directives       []Directive `json:"directives"`
+}
+
+type namedRef struct {
+	Name string `json:"name"`
+}
+
+// FullType is one type of the schema
+type FullType struct {
+	Kind          string       `json:"kind"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Fields        []Field      `json:"fields"`
+	InputFields   []InputValue `json:"inputFields"`
+	Interfaces    []TypeRef    `json:"interfaces"`
+	EnumValues    []EnumValue  `json:"enumValues"`
+	PossibleTypes []TypeRef    `json:"possibleTypes"`
+}
+
+// Field is a field of an object or interface type
+type Field struct {
+	Name              string       `json:"name"`
+	Description       string       `json:"description"`
+	Args              []InputValue `json:"args"`
+	Type              TypeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason string       `json:"deprecationReason"`
+}
+
+// InputValue is an argument or input object field
+type InputValue struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Type         TypeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+// EnumValue is a value of an enum type
+type EnumValue struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated"`
+	DeprecationReason string `json:"deprecationReason"`
+}
+
+// Directive is a directive definition
+type Directive struct {
+	Name         string       `json:"name"`
+	Description  string       `json:"description"`
+	IsRepeatable bool         `json:"isRepeatable"`
+	Locations    []string     `json:"locations"`
+	Args         []InputValue `json:"args"`
+}
+
+// TypeRef refers to a type, wrapped in lists and non-null markers
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// String renders the reference in SDL, e.g. "[String!]!"
+func (t TypeRef) String() string {
+	switch {
+	case t.Kind == "NON_NULL" && t.OfType != nil:
+		return t.OfType.String() + "!"
+	case t.Kind == "LIST" && t.OfType != nil:
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// ParseSchema decodes the data of an IntrospectionQuery response
+func ParseSchema(data json.RawMessage) (*Schema, error) {
+	var wrapper struct {
+		Schema *Schema `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("invalid introspection result: %w", err)
+	}
+	if wrapper.Schema == nil {
+		return nil, fmt.Errorf("introspection result has no __schema")
+	}
+	return wrapper.Schema, nil
+}
+
+var builtinScalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+var builtinDirectives = map[string]bool{"skip": true, "include": true, "deprecated": true, "specifiedBy": true, "oneOf": true}
+
+// SDL renders the schema in the GraphQL schema definition language,
+// leaving out built-in scalars, directives and introspection types
+func (s *Schema) SDL() string {
+	var blocks []string
+
+	if def := s.schemaDefinition(); def != "" {
+		blocks = append(blocks, def)
+	}
+	for _, d := range s.Directives {
+		if !builtinDirectives[d.Name] {
+			blocks = append(blocks, printDirective(d))
+		}
+	}
+	for _, t := range s.Types {
+		if strings.HasPrefix(t.Name, "__") || (t.Kind == "SCALAR" && builtinScalars[t.Name]) {
+			continue
+		}
+		blocks = append(blocks, printType(t))
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// schemaDefinition is only needed when the root types are not named
+// Query, Mutation and Subscription
+func (s *Schema) schemaDefinition() string {
+	roots := []struct {
+		op  string
+		ref *namedRef
+	}{
+		{"query", s.QueryType},
+		{"mutation", s.MutationType},
+		{"subscription", s.SubscriptionType},
+	}
+
+	conventional := true
+	var lines []string
+	for _, r := range roots {
+		if r.ref == nil {
+			continue
+		}
+		if r.ref.Name != strings.ToUpper(r.op[:1])+r.op[1:] {
+			conventional = false
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", r.op, r.ref.Name))
+	}
+	if conventional {
+		return ""
+	}
+	return "schema {\n" + strings.Join(lines, "\n") + "\n}"
+}
+
+func printType(t FullType) string {
+	var b strings.Builder
+	b.WriteString(description(t.Description, ""))
+
+	switch t.Kind {
+	case "SCALAR":
+		fmt.Fprintf(&b, "scalar %s", t.Name)
+	case "OBJECT", "INTERFACE":
+		keyword := "type"
+		if t.Kind == "INTERFACE" {
+			keyword = "interface"
+		}
+		fmt.Fprintf(&b, "%s %s%s {\n", keyword, t.Name, implements(t.Interfaces))
+		for _, f := range t.Fields {
+			b.WriteString(description(f.Description, "  "))
+			fmt.Fprintf(&b, "  %s%s: %s%s\n", f.Name, printArgs(f.Args, "  "), f.Type, deprecated(f.IsDeprecated, f.DeprecationReason))
+		}
+		b.WriteString("}")
+	case "UNION":
+		names := make([]string, len(t.PossibleTypes))
+		for i, p := range t.PossibleTypes {
+			names[i] = p.Name
+		}
+		fmt.Fprintf(&b, "union %s = %s", t.Name, strings.Join(names, " | "))
+	case "ENUM":
+		fmt.Fprintf(&b, "enum %s {\n", t.Name)
+		for _, v := range t.EnumValues {
+			b.WriteString(description(v.Description, "  "))
+			fmt.Fprintf(&b, "  %s%s\n", v.Name, deprecated(v.IsDeprecated, v.DeprecationReason))
+		}
+		b.WriteString("}")
+	case "INPUT_OBJECT":
+		fmt.Fprintf(&b, "input %s {\n", t.Name)
+		for _, f := range t.InputFields {
+			b.WriteString(description(f.Description, "  "))
+			fmt.Fprintf(&b, "  %s\n", inputValue(f))
+		}
+		b.WriteString("}")
+	default:
+		fmt.Fprintf(&b, "# %s %s", strings.ToLower(t.Kind), t.Name)
+	}
+	return b.String()
+}
+
+func printDirective(d Directive) string {
+	repeatable := ""
+	if d.IsRepeatable {
+		repeatable = " repeatable"
+	}
+	return fmt.Sprintf("%sdirective @%s%s%s on %s", description(d.Description, ""), d.Name, printArgs(d.Args, ""), repeatable, strings.Join(d.Locations, " | "))
+}
+
+func implements(interfaces []TypeRef) string {
+	if len(interfaces) == 0 {
+		return ""
+	}
+	names := make([]string, len(interfaces))
+	for i, t := range interfaces {
+		names[i] = t.Name
+	}
+	return " implements " + strings.Join(names, " & ")
+}
+
+// printArgs writes arguments on one line, or one per line when any of
+// them has a description
+func printArgs(args []InputValue, indent string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	described := false
+	for _, a := range args {
+		if a.Description != "" {
+			described = true
+		}
+	}
+
+	if !described {
+		parts := make([]string, len(args))
+		for i, a := range args {
+			parts[i] = inputValue(a)
+		}
+		return "(" + strings.Join(parts, ", ") + ")"
+	}
+
+	var b strings.Builder
+	b.WriteString("(\n")
+	for _, a := range args {
+		b.WriteString(description(a.Description, indent+"  "))
+		fmt.Fprintf(&b, "%s  %s\n", indent, inputValue(a))
+	}
+	b.WriteString(indent + ")")
+	return b.String()
+}
+
+func inputValue(v InputValue) string {
+	s := v.Name + ": " + v.Type.String()
+	if v.DefaultValue != nil {
+		s += " = " + *v.DefaultValue
+	}
+	return s
+}
+
+func deprecated(isDeprecated bool, reason string) string {
+	if !isDeprecated {
+		return ""
+	}
+	if reason == "" || reason == "No longer supported" {
+		return " @deprecated"
+	}
+	r, _ := json.Marshal(reason)
+	return fmt.Sprintf(" @deprecated(reason: %s)", r)
+}
+
+// description renders a description as a string or block string
+func description(text, indent string) string {
+	if text == "" {
+		return ""
+	}
+	if !strings.ContainsAny(text, "\n\"\\") {
+		return fmt.Sprintf("%s\"%s\"\n", indent, text)
+	}
+	text = strings.ReplaceAll(text, `"""`, `\"""`)
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = indent + l
+		}
+	}
+	return fmt.Sprintf("%s\"\"\"\n%s\n%s\"\"\"\n", indent, strings.Join(lines, "\n"), indent)
+}
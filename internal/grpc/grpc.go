@@ -0,0 +1,171 @@
+// Package grpc makes gRPC calls over sreq's HTTP client, so they get the
+// same credentials, TLS, proxy and history handling as plain requests.
+// Unary and server-streaming calls are supported.
+package grpc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/client"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// MaxMessageSize bounds a received message, to fail cleanly on a
+// corrupt length prefix
+const MaxMessageSize = 64 << 20
+
+// Conn sends calls to one service
+type Conn struct {
+	client *client.Client
+	creds  *types.ResolvedCredentials
+
+	// Metadata is sent as headers with every call, next to the resolved
+	// credential headers
+	Metadata map[string]string
+
+	// reflection is the reflection service the server answered on
+	reflection string
+}
+
+// NewConn returns a connection to the service at creds.BaseURL. The
+// client should be pinned to h2 or h2c, see client.WithProtocol.
+func NewConn(c *client.Client, creds *types.ResolvedCredentials) *Conn {
+	return &Conn{client: c, creds: creds}
+}
+
+// Invoke calls method ("package.Service/Method") with one request
+// message, handing each response message to fn as it arrives. It returns
+// the HTTP response, with the trailers, and a *Status error when the call
+// did not end with OK.
+func (c *Conn) Invoke(ctx context.Context, method string, msg []byte, fn func(msg []byte) error) (*types.Response, error) {
+	req := &types.Request{
+		Method:  http.MethodPost,
+		Path:    "/" + strings.TrimPrefix(method, "/"),
+		Body:    string(frame(msg)),
+		Headers: map[string]string{},
+	}
+	for key, value := range c.Metadata {
+		req.Headers[key] = value
+	}
+	req.Headers["Content-Type"] = "application/grpc"
+	req.Headers["TE"] = "trailers"
+	req.Headers["Grpc-Accept-Encoding"] = "gzip"
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Headers["Grpc-Timeout"] = timeoutHeader(time.Until(deadline))
+	}
+
+	var callErr error
+	resp, err := c.client.DoStream(ctx, req, c.creds, func(resp *types.Response, body io.Reader) error {
+		if resp.StatusCode != http.StatusOK {
+			return nil
+		}
+		if ct := http.Header(resp.Headers).Get("Content-Type"); !strings.HasPrefix(ct, "application/grpc") {
+			callErr = &Status{Code: Unknown, Message: fmt.Sprintf("unexpected content type %q", ct)}
+			return nil
+		}
+
+		fr := &frameReader{r: bufio.NewReader(body), encoding: http.Header(resp.Headers).Get("Grpc-Encoding")}
+		for {
+			m, err := fr.next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := fn(m); err != nil {
+				callErr = err
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return resp, err
+	}
+	if callErr != nil {
+		return resp, callErr
+	}
+
+	if st := statusFrom(resp.StatusCode, resp.Headers, resp.Trailers); st.Code != OK {
+		return resp, st
+	}
+	return resp, nil
+}
+
+// frame prefixes a message with its uncompressed flag and length
+func frame(msg []byte) []byte {
+	b := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(b[1:], uint32(len(msg)))
+	return append(b, msg...)
+}
+
+// frameReader reads length-prefixed messages
+type frameReader struct {
+	r        io.Reader
+	encoding string // grpc-encoding of compressed messages
+}
+
+// next returns the next message, or io.EOF after the last one
+func (fr *frameReader) next() ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(fr.r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated gRPC message header")
+		}
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > MaxMessageSize {
+		return nil, fmt.Errorf("gRPC message of %d bytes exceeds the %d byte limit", size, MaxMessageSize)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(fr.r, msg); err != nil {
+		return nil, fmt.Errorf("truncated gRPC message: %w", err)
+	}
+
+	switch header[0] {
+	case 0:
+		return msg, nil
+	case 1:
+		if fr.encoding != "gzip" {
+			return nil, fmt.Errorf("compressed gRPC message with unsupported encoding %q", fr.encoding)
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(msg))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip gRPC message: %w", err)
+		}
+		out, err := io.ReadAll(io.LimitReader(zr, MaxMessageSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip gRPC message: %w", err)
+		}
+		if len(out) > MaxMessageSize {
+			return nil, errors.New("decompressed gRPC message exceeds the size limit")
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("invalid gRPC message flag %d", header[0])
+	}
+}
+
+// timeoutHeader formats a grpc-timeout value, at most 8 digits
+func timeoutHeader(d time.Duration) string {
+	if d <= 0 {
+		d = time.Millisecond
+	}
+	ms := d.Milliseconds()
+	if ms < 100000000 {
+		return fmt.Sprintf("%dm", max(ms, 1))
+	}
+	return fmt.Sprintf("%dS", min(int64(d.Seconds()), 99999999))
+}
@@ -0,0 +1,260 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Priyans-hu/sreq/internal/client"
+	"github.com/Priyans-hu/sreq/pkg/types"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// echoDescriptor is a FileDescriptorProto for
+//
+//	package test;
+//	message Msg { string text = 1; }
+//	service Echo { rpc Say(Msg) returns (Msg); rpc Repeat(Msg) returns (stream Msg); }
+func echoDescriptor() []byte {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("echo.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Msg"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("text"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				JsonName: proto.String("text"),
+			}},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Echo"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Say"), InputType: proto.String(".test.Msg"), OutputType: proto.String(".test.Msg")},
+				{Name: proto.String("Repeat"), InputType: proto.String(".test.Msg"), OutputType: proto.String(".test.Msg"), ServerStreaming: proto.Bool(true)},
+			},
+		}},
+		Syntax: proto.String("proto3"),
+	}
+	b, _ := proto.Marshal(file)
+	return b
+}
+
+func writeStatus(w http.ResponseWriter, code Code, msg string) {
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.FormatUint(uint64(code), 10))
+	if msg != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", msg)
+	}
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if len(body) < 5 || r.Header.Get("Content-Type") != "application/grpc" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		msg := body[5:]
+		w.Header().Set("Content-Type", "application/grpc")
+
+		switch r.URL.Path {
+		case "/test.Echo/Say":
+			w.Header().Set("X-Seen-Key", r.Header.Get("X-Api-Key")+","+r.Header.Get("X-Trace"))
+			_, _ = w.Write(frame(msg))
+			writeStatus(w, OK, "")
+		case "/test.Echo/Repeat":
+			for range 3 {
+				_, _ = w.Write(frame(msg))
+				w.(http.Flusher).Flush()
+			}
+			writeStatus(w, OK, "")
+		case "/test.Echo/Missing":
+			// Trailers-only response
+			w.Header().Set("Grpc-Status", "5")
+			w.Header().Set("Grpc-Message", "no such thing%3A 42")
+			w.WriteHeader(http.StatusOK)
+		case "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo":
+			var req rpb.ServerReflectionRequest
+			if err := proto.Unmarshal(msg, &req); err != nil {
+				t.Errorf("decoding reflection request: %v", err)
+			}
+
+			resp := &rpb.ServerReflectionResponse{}
+			switch {
+			case req.GetListServices() != "":
+				resp.MessageResponse = &rpb.ServerReflectionResponse_ListServicesResponse{
+					ListServicesResponse: &rpb.ListServiceResponse{Service: []*rpb.ServiceResponse{{Name: "test.Echo"}}},
+				}
+			case req.GetFileContainingSymbol() == "test.Echo":
+				resp.MessageResponse = &rpb.ServerReflectionResponse_FileDescriptorResponse{
+					FileDescriptorResponse: &rpb.FileDescriptorResponse{FileDescriptorProto: [][]byte{echoDescriptor()}},
+				}
+			default:
+				resp.MessageResponse = &rpb.ServerReflectionResponse_ErrorResponse{
+					ErrorResponse: &rpb.ErrorResponse{ErrorCode: 5, ErrorMessage: "symbol not found"},
+				}
+			}
+			out, err := proto.Marshal(resp)
+			if err != nil {
+				t.Errorf("encoding reflection response: %v", err)
+			}
+			_, _ = w.Write(frame(out))
+			writeStatus(w, OK, "")
+		default:
+			w.Header().Set("Grpc-Status", "12")
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestConn(server *httptest.Server) *Conn {
+	creds := &types.ResolvedCredentials{
+		BaseURL: server.URL,
+		Headers: map[string]string{"X-Api-Key": "secret"},
+	}
+	return NewConn(client.New(client.WithProtocol(types.ProtocolH2C)), creds)
+}
+
+func TestConn_Invoke(t *testing.T) {
+	conn := newTestConn(newTestServer(t))
+	conn.Metadata = map[string]string{"X-Trace": "abc"}
+
+	var got []string
+	resp, err := conn.Invoke(context.Background(), "test.Echo/Say", []byte("hello"), func(msg []byte) error {
+		got = append(got, string(msg))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "hello" {
+		t.Errorf("messages = %q, want [hello]", got)
+	}
+	if seen := http.Header(resp.Headers).Get("X-Seen-Key"); seen != "secret,abc" {
+		t.Errorf("server saw credentials and metadata %q, want %q", seen, "secret,abc")
+	}
+	if resp.Proto != "HTTP/2.0" {
+		t.Errorf("Proto = %q, want HTTP/2.0", resp.Proto)
+	}
+}
+
+func TestConn_Invoke_ServerStreaming(t *testing.T) {
+	conn := newTestConn(newTestServer(t))
+
+	n := 0
+	_, err := conn.Invoke(context.Background(), "/test.Echo/Repeat", []byte("x"), func(msg []byte) error {
+		n++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("received %d messages, want 3", n)
+	}
+}
+
+func TestConn_Invoke_Status(t *testing.T) {
+	conn := newTestConn(newTestServer(t))
+
+	_, err := conn.Invoke(context.Background(), "test.Echo/Missing", nil, func([]byte) error { return nil })
+	var st *Status
+	if !errors.As(err, &st) {
+		t.Fatalf("Invoke() error = %v, want *Status", err)
+	}
+	if st.Code != NotFound || st.Message != "no such thing: 42" {
+		t.Errorf("status = %+v, want NOT_FOUND \"no such thing: 42\"", st)
+	}
+	if err.Error() != "rpc error: NOT_FOUND: no such thing: 42" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestConn_Reflection(t *testing.T) {
+	conn := newTestConn(newTestServer(t))
+	ctx := context.Background()
+
+	services, err := conn.ListServices(ctx)
+	if err != nil {
+		t.Fatalf("ListServices() error = %v", err)
+	}
+	if len(services) != 1 || services[0] != "test.Echo" {
+		t.Errorf("ListServices() = %v", services)
+	}
+	if conn.reflection != "grpc.reflection.v1alpha.ServerReflection" {
+		t.Errorf("reflection = %q, want the v1alpha fallback", conn.reflection)
+	}
+
+	reg, err := conn.Reflect(ctx, "test.Echo/Say")
+	if err != nil {
+		t.Fatalf("Reflect() error = %v", err)
+	}
+	m, err := reg.Method("test.Echo/Repeat")
+	if err != nil {
+		t.Fatalf("Method() error = %v", err)
+	}
+	if m.Output().FullName() != "test.Msg" || !m.IsStreamingServer() {
+		t.Errorf("Method = %+v", m)
+	}
+
+	_, err = conn.Reflect(ctx, "test.Nope")
+	var st *Status
+	if !errors.As(err, &st) || st.Code != NotFound {
+		t.Errorf("Reflect(unknown) error = %v, want NOT_FOUND", err)
+	}
+}
+
+func TestStatusFrom_HTTP(t *testing.T) {
+	tests := []struct {
+		status int
+		want   Code
+	}{
+		{http.StatusUnauthorized, Unauthenticated},
+		{http.StatusNotFound, Unimplemented},
+		{http.StatusServiceUnavailable, Unavailable},
+		{http.StatusTeapot, Unknown},
+		{http.StatusOK, Internal},
+	}
+	for _, tt := range tests {
+		if got := statusFrom(tt.status, http.Header{}, nil); got.Code != tt.want {
+			t.Errorf("statusFrom(%d) = %s, want %s", tt.status, got.Code, tt.want)
+		}
+	}
+}
+
+func TestFrameReader(t *testing.T) {
+	data := append(frame([]byte("a")), frame(nil)...)
+	fr := &frameReader{r: strings.NewReader(string(data))}
+
+	for _, want := range []string{"a", ""} {
+		msg, err := fr.next()
+		if err != nil || string(msg) != want {
+			t.Fatalf("next() = %q, %v, want %q", msg, err, want)
+		}
+	}
+	if _, err := fr.next(); err != io.EOF {
+		t.Errorf("next() at end error = %v, want io.EOF", err)
+	}
+
+	fr = &frameReader{r: strings.NewReader(string(data[:3]))}
+	if _, err := fr.next(); err == nil || err == io.EOF {
+		t.Errorf("next() on truncated header error = %v", err)
+	}
+}
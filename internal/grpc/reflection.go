@@ -0,0 +1,162 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Priyans-hu/sreq/internal/protobuf"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Reflection services, newest first. v1alpha is what most servers still
+// register; both share one message layout.
+var reflectionServices = []string{
+	"grpc.reflection.v1.ServerReflection",
+	"grpc.reflection.v1alpha.ServerReflection",
+}
+
+// ListServices lists the services the server exposes through reflection
+func (c *Conn) ListServices(ctx context.Context) ([]string, error) {
+	resp, err := c.reflect(ctx, &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := resp.GetListServicesResponse()
+	if list == nil {
+		return nil, errors.New("unexpected reflection response to list services")
+	}
+	var names []string
+	for _, s := range list.GetService() {
+		names = append(names, s.GetName())
+	}
+	return names, nil
+}
+
+// Reflect builds a registry of the files defining symbols (services,
+// methods, messages or enums) and everything they import
+func (c *Conn) Reflect(ctx context.Context, symbols ...string) (*protobuf.Registry, error) {
+	files := map[string]*descriptorpb.FileDescriptorProto{}
+	for _, symbol := range symbols {
+		// "pkg.Service/Method" is looked up through its service
+		symbol = strings.TrimPrefix(symbol, "/")
+		if svc, _, ok := strings.Cut(symbol, "/"); ok {
+			symbol = svc
+		}
+		req := &rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+		}
+		if err := c.addFiles(ctx, files, req); err != nil {
+			return nil, err
+		}
+	}
+
+	// Servers usually send the dependencies along; fetch any that are
+	// missing. Those reflection cannot supply, such as the well-known
+	// types on some servers, are left to NewFileSet.
+	tried := map[string]bool{}
+	for {
+		var fetch []string
+		for _, name := range protobuf.MissingDependencies(values(files)) {
+			if !tried[name] {
+				fetch = append(fetch, name)
+			}
+		}
+		if len(fetch) == 0 {
+			break
+		}
+		for _, name := range fetch {
+			tried[name] = true
+			req := &rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+			}
+			var st *Status
+			if err := c.addFiles(ctx, files, req); err != nil && !errors.As(err, &st) {
+				return nil, err
+			}
+		}
+	}
+
+	reg, err := protobuf.NewFileSet(values(files))
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptors from reflection: %w", err)
+	}
+	return reg, nil
+}
+
+func values(files map[string]*descriptorpb.FileDescriptorProto) []*descriptorpb.FileDescriptorProto {
+	list := make([]*descriptorpb.FileDescriptorProto, 0, len(files))
+	for _, f := range files {
+		list = append(list, f)
+	}
+	return list
+}
+
+func (c *Conn) addFiles(ctx context.Context, files map[string]*descriptorpb.FileDescriptorProto, req *rpb.ServerReflectionRequest) error {
+	resp, err := c.reflect(ctx, req)
+	if err != nil {
+		return err
+	}
+	fdr := resp.GetFileDescriptorResponse()
+	if fdr == nil {
+		return errors.New("unexpected reflection response to a file request")
+	}
+	for _, b := range fdr.GetFileDescriptorProto() {
+		f := new(descriptorpb.FileDescriptorProto)
+		if err := proto.Unmarshal(b, f); err != nil {
+			return fmt.Errorf("invalid descriptor from reflection: %w", err)
+		}
+		if _, ok := files[f.GetName()]; !ok {
+			files[f.GetName()] = f
+		}
+	}
+	return nil
+}
+
+// reflect sends one reflection request, falling back to v1alpha when the
+// server lacks v1
+func (c *Conn) reflect(ctx context.Context, req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+	msg, err := proto.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	services := reflectionServices
+	if c.reflection != "" {
+		services = []string{c.reflection}
+	}
+
+	var reply []byte
+	for i, svc := range services {
+		_, err = c.Invoke(ctx, svc+"/ServerReflectionInfo", msg, func(m []byte) error {
+			reply = m
+			return nil
+		})
+		var st *Status
+		if errors.As(err, &st) && st.Code == Unimplemented && i < len(services)-1 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.reflection = svc
+		break
+	}
+	if reply == nil {
+		return nil, errors.New("empty reflection response")
+	}
+
+	resp := new(rpb.ServerReflectionResponse)
+	if err := proto.Unmarshal(reply, resp); err != nil {
+		return nil, fmt.Errorf("invalid reflection response: %w", err)
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, &Status{Code: Code(e.GetErrorCode()), Message: e.GetErrorMessage()}
+	}
+	return resp, nil
+}
@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Code is a gRPC status code
+type Code uint32
+
+// Status codes, see https://grpc.github.io/grpc/core/md_doc_statuscodes.html
+const (
+	OK Code = iota
+	Canceled
+	Unknown
+	InvalidArgument
+	DeadlineExceeded
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	ResourceExhausted
+	FailedPrecondition
+	Aborted
+	OutOfRange
+	Unimplemented
+	Internal
+	Unavailable
+	DataLoss
+	Unauthenticated
+)
+
+var codeNames = []string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED",
+	"NOT_FOUND", "ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED",
+	"FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED",
+	"INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+}
+
+func (c Code) String() string {
+	if int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return "CODE(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// Status is the outcome of a call. A non-OK status is returned as the
+// call's error.
+type Status struct {
+	Code    Code
+	Message string
+}
+
+func (s *Status) Error() string {
+	if s.Message == "" {
+		return "rpc error: " + s.Code.String()
+	}
+	return fmt.Sprintf("rpc error: %s: %s", s.Code, s.Message)
+}
+
+// statusFrom reads grpc-status and grpc-message from the trailers, or
+// from the headers of a trailers-only response. Responses without a
+// status are mapped from their HTTP status, as gRPC clients do.
+func statusFrom(statusCode int, headers, trailers http.Header) *Status {
+	for _, h := range []http.Header{trailers, headers} {
+		value := h.Get("Grpc-Status")
+		if value == "" {
+			continue
+		}
+		code, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return &Status{Code: Unknown, Message: "invalid grpc-status " + value}
+		}
+		return &Status{Code: Code(code), Message: decodeMessage(h.Get("Grpc-Message"))}
+	}
+
+	if statusCode == http.StatusOK {
+		return &Status{Code: Internal, Message: "server sent no grpc-status"}
+	}
+	code := Unknown
+	switch statusCode {
+	case http.StatusBadRequest:
+		code = Internal
+	case http.StatusUnauthorized:
+		code = Unauthenticated
+	case http.StatusForbidden:
+		code = PermissionDenied
+	case http.StatusNotFound:
+		code = Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		code = Unavailable
+	}
+	return &Status{Code: code, Message: fmt.Sprintf("HTTP %d %s", statusCode, http.StatusText(statusCode))}
+}
+
+// decodeMessage undoes the percent-encoding of grpc-message
+func decodeMessage(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	if decoded, err := url.PathUnescape(s); err == nil {
+		return decoded
+	}
+	return s
+}
@@ -8,6 +8,9 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/Priyans-hu/sreq/internal/redact"
+	"github.com/Priyans-hu/sreq/pkg/types"
 )
 
 const (
@@ -16,33 +19,121 @@ const (
 
 	// DefaultMaxEntries is the default maximum number of history entries
 	DefaultMaxEntries = 100
+
+	// MethodGRPC marks entries of gRPC calls, whose Path is
+	// "/package.Service/Method"
+	MethodGRPC = "GRPC"
+
+	// MethodWS marks entries of WebSocket sessions. The request body holds
+	// the messages sent, one per line.
+	MethodWS = "WS"
 )
 
 // Entry represents a single request history entry
 type Entry struct {
-	ID        int       `json:"id"`
-	Timestamp time.Time `json:"timestamp"`
-	Service   string    `json:"service"`
-	Env       string    `json:"env"`
-	Method    string    `json:"method"`
-	Path      string    `json:"path"`
-	BaseURL   string    `json:"base_url,omitempty"`
-	Status    int       `json:"status,omitempty"`
-	Duration  int64     `json:"duration_ms,omitempty"`
-	Request   *Request  `json:"request,omitempty"`
-	Response  *Response `json:"response,omitempty"`
+	ID        int        `json:"id"`
+	Timestamp time.Time  `json:"timestamp"`
+	Service   string     `json:"service"`
+	Env       string     `json:"env"`
+	Method    string     `json:"method"`
+	Path      string     `json:"path"`
+	BaseURL   string     `json:"base_url,omitempty"`
+	Status    int        `json:"status,omitempty"`
+	Duration  int64      `json:"duration_ms,omitempty"`
+	Request   *Request   `json:"request,omitempty"`
+	Response  *Response  `json:"response,omitempty"`
+	Attempts  []Attempt  `json:"attempts,omitempty"`
+	Redirects []Redirect `json:"redirects,omitempty"`
+	Timing    *Timing    `json:"timing,omitempty"`
+}
+
+// Redirect records one followed redirect
+type Redirect struct {
+	Status   int      `json:"status"`
+	Method   string   `json:"method"`
+	URL      string   `json:"url"`
+	Stripped []string `json:"stripped,omitempty"` // credential headers dropped
+}
+
+// Timing is a request's latency breakdown in milliseconds
+type Timing struct {
+	CacheLookup float64 `json:"cache_ms,omitempty"`
+	Resolve     float64 `json:"resolve_ms,omitempty"`
+	DNS         float64 `json:"dns_ms,omitempty"`
+	Connect     float64 `json:"connect_ms,omitempty"`
+	TLS         float64 `json:"tls_ms,omitempty"`
+	TTFB        float64 `json:"ttfb_ms,omitempty"`
+	Transfer    float64 `json:"transfer_ms,omitempty"`
+	Request     float64 `json:"request_ms,omitempty"`
+}
+
+// TimingPhase is one named part of a Timing
+type TimingPhase struct {
+	Name string
+	Ms   float64
+}
+
+// SetRequest fills the HTTP phases from the client's measurements
+func (t *Timing) SetRequest(rt *types.Timing) {
+	t.DNS = Milliseconds(rt.DNS)
+	t.Connect = Milliseconds(rt.Connect)
+	t.TLS = Milliseconds(rt.TLS)
+	t.TTFB = Milliseconds(rt.TTFB)
+	t.Transfer = Milliseconds(rt.Transfer)
+	t.Request = Milliseconds(rt.Total)
+}
+
+// Phases lists the breakdown in the order the phases happen
+func (t Timing) Phases() []TimingPhase {
+	return []TimingPhase{
+		{"Cache lookup", t.CacheLookup},
+		{"Resolve", t.Resolve},
+		{"DNS", t.DNS},
+		{"Connect", t.Connect},
+		{"TLS", t.TLS},
+		{"TTFB", t.TTFB},
+		{"Transfer", t.Transfer},
+		{"HTTP total", t.Request},
+	}
+}
+
+// Milliseconds converts d to milliseconds, rounded to microseconds
+func Milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Attempt records one try of a retried request
+type Attempt struct {
+	Status   int    `json:"status,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Duration int64  `json:"duration_ms"`
+	Wait     int64  `json:"wait_ms,omitempty"`
 }
 
 // Request contains request details (with sensitive data redacted)
 type Request struct {
 	Headers map[string]string `json:"headers,omitempty"`
 	Body    string            `json:"body,omitempty"`
+	Form    []string          `json:"form,omitempty"` // multipart fields, files as name=@path
+
+	// Compress is the coding the body was sent in, e.g. gzip
+	Compress string `json:"compress,omitempty"`
 }
 
 // Response contains response details
 type Response struct {
 	Status    string `json:"status,omitempty"`
+	Protocol  string `json:"protocol,omitempty"` // negotiated, e.g. HTTP/2.0
 	SizeBytes int    `json:"size_bytes,omitempty"`
+
+	// Encoding lists the Content-Encoding codings the body was decoded
+	// from; EncodedBytes is its size before decoding
+	Encoding     string `json:"encoding,omitempty"`
+	EncodedBytes int    `json:"encoded_bytes,omitempty"`
+
+	// Sample is the start of a streamed body
+	Sample    string `json:"sample,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
 }
 
 // History manages request history
@@ -52,15 +143,6 @@ type History struct {
 	path       string
 }
 
-// sensitiveHeaders are headers that should be redacted
-var sensitiveHeaders = []string{
-	"authorization",
-	"x-api-key",
-	"x-auth-token",
-	"cookie",
-	"set-cookie",
-}
-
 // New creates a new history manager
 func New(configDir string) (*History, error) {
 	path := filepath.Join(configDir, DefaultHistoryFile)
@@ -123,10 +205,15 @@ func (h *History) Add(entry Entry) {
 		entry.Timestamp = time.Now()
 	}
 
-	// Redact sensitive headers
+	// Redact sensitive headers and query parameters. Callers should also
+	// mask resolved secrets with a seeded redact.Redactor first.
 	if entry.Request != nil && entry.Request.Headers != nil {
 		entry.Request.Headers = redactHeaders(entry.Request.Headers)
 	}
+	entry.Path = redact.New().URL(entry.Path)
+	for i := range entry.Redirects {
+		entry.Redirects[i].URL = redact.New().URL(entry.Redirects[i].URL)
+	}
 
 	// Prepend to keep most recent first
 	h.Entries = append([]Entry{entry}, h.Entries...)
@@ -212,23 +299,7 @@ func (h *History) Count() int {
 
 // redactHeaders redacts sensitive header values
 func redactHeaders(headers map[string]string) map[string]string {
-	result := make(map[string]string)
-	for k, v := range headers {
-		lowerKey := strings.ToLower(k)
-		redact := false
-		for _, sensitive := range sensitiveHeaders {
-			if lowerKey == sensitive {
-				redact = true
-				break
-			}
-		}
-		if redact {
-			result[k] = "***REDACTED***"
-		} else {
-			result[k] = v
-		}
-	}
-	return result
+	return redact.New().Headers(headers)
 }
 
 // ToCurl converts an entry to a curl command
@@ -250,24 +321,42 @@ func (e *Entry) ToCurl() string {
 		}
 		sort.Strings(keys)
 
+		// Entries are redacted when added; this also covers older files
+		headers := redactHeaders(e.Request.Headers)
 		for _, k := range keys {
-			v := e.Request.Headers[k]
+			v := headers[k]
 			parts = append(parts, "-H", fmt.Sprintf("'%s: %s'", k, v))
 		}
 	}
 
 	// Body
-	if e.Request != nil && e.Request.Body != "" {
+	var pipe string
+	if e.Request != nil && e.Request.Body != "" && e.Request.Compress != "" {
+		// curl cannot compress a request body, so the shell does it
+		enc := e.Request.Compress
+		if file, ok := strings.CutPrefix(e.Request.Body, "@"); ok {
+			pipe = fmt.Sprintf("%s -c '%s' | ", enc, strings.ReplaceAll(file, "'", "'\\''"))
+		} else {
+			body := strings.ReplaceAll(e.Request.Body, "'", "'\\''")
+			pipe = fmt.Sprintf("printf '%%s' '%s' | %s -c | ", body, enc)
+		}
+		parts = append(parts, "-H", fmt.Sprintf("'Content-Encoding: %s'", enc), "--data-binary", "@-")
+	} else if e.Request != nil && e.Request.Body != "" {
 		// Escape single quotes in body
 		body := strings.ReplaceAll(e.Request.Body, "'", "'\\''")
 		parts = append(parts, "-d", fmt.Sprintf("'%s'", body))
 	}
+	if e.Request != nil {
+		for _, field := range e.Request.Form {
+			parts = append(parts, "-F", fmt.Sprintf("'%s'", strings.ReplaceAll(field, "'", "'\\''")))
+		}
+	}
 
 	// URL
-	url := e.BaseURL + e.Path
+	url := redact.New().URL(e.BaseURL + e.Path)
 	parts = append(parts, fmt.Sprintf("'%s'", url))
 
-	return strings.Join(parts, " ")
+	return pipe + strings.Join(parts, " ")
 }
 
 // ToHTTPie converts an entry to an HTTPie command
@@ -276,7 +365,7 @@ func (e *Entry) ToHTTPie() string {
 	parts = append(parts, "http")
 
 	// Method and URL
-	url := e.BaseURL + e.Path
+	url := redact.New().URL(e.BaseURL + e.Path)
 	parts = append(parts, e.Method, url)
 
 	// Headers
@@ -287,8 +376,9 @@ func (e *Entry) ToHTTPie() string {
 		}
 		sort.Strings(keys)
 
+		headers := redactHeaders(e.Request.Headers)
 		for _, k := range keys {
-			v := e.Request.Headers[k]
+			v := headers[k]
 			parts = append(parts, fmt.Sprintf("%s:'%s'", k, v))
 		}
 	}
@@ -310,7 +400,7 @@ func (e *Entry) FormatDuration() string {
 // StatusColor returns ANSI color code for the status
 func (e *Entry) StatusColor() string {
 	switch {
-	case e.Status >= 200 && e.Status < 300:
+	case e.Status >= 200 && e.Status < 300, e.Status == 101: // 101: WebSocket upgrade
 		return "\033[32m" // Green
 	case e.Status >= 300 && e.Status < 400:
 		return "\033[33m" // Yellow
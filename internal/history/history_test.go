@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
 )
 
 func TestHistory_AddAndGet(t *testing.T) {
@@ -257,6 +259,26 @@ func TestEntry_ToCurl(t *testing.T) {
 			},
 			contains: []string{"-H", "Content-Type: application/json"},
 		},
+		{
+			name: "compressed body",
+			entry: Entry{
+				Method:  "POST",
+				Path:    "/events",
+				BaseURL: "https://api.example.com",
+				Request: &Request{Body: `{"a":1}`, Compress: "gzip"},
+			},
+			contains: []string{`printf '%s' '{"a":1}' | gzip -c | curl`, "'Content-Encoding: gzip'", "--data-binary @-"},
+		},
+		{
+			name: "compressed file",
+			entry: Entry{
+				Method:  "POST",
+				Path:    "/events",
+				BaseURL: "https://api.example.com",
+				Request: &Request{Body: "@events.json", Compress: "zstd"},
+			},
+			contains: []string{"zstd -c 'events.json' | curl", "'Content-Encoding: zstd'", "--data-binary @-"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -277,6 +299,8 @@ func TestRedactHeaders(t *testing.T) {
 		"Authorization": "Bearer secret-token",
 		"X-Api-Key":     "my-api-key",
 		"Accept":        "application/json",
+		"Cookie":        "session=abc123",
+		"Set-Cookie":    "session=abc123; Path=/; HttpOnly",
 	}
 
 	redacted := redactHeaders(headers)
@@ -296,6 +320,11 @@ func TestRedactHeaders(t *testing.T) {
 	if redacted["X-Api-Key"] != "***REDACTED***" {
 		t.Errorf("X-Api-Key should be redacted, got %q", redacted["X-Api-Key"])
 	}
+	for _, name := range []string{"Cookie", "Set-Cookie"} {
+		if redacted[name] != "***REDACTED***" {
+			t.Errorf("%s should be redacted, got %q", name, redacted[name])
+		}
+	}
 }
 
 func contains(s, substr string) bool {
@@ -310,3 +339,34 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestEntry_Exports_RedactQuery(t *testing.T) {
+	entry := &Entry{
+		Method:  "GET",
+		BaseURL: "https://api.example.com",
+		Path:    "/users?api_key=abc123&page=2",
+	}
+
+	for name, out := range map[string]string{"curl": entry.ToCurl(), "httpie": entry.ToHTTPie()} {
+		if contains(out, "abc123") {
+			t.Errorf("%s export leaks query secret: %s", name, out)
+		}
+		if !contains(out, "page=2") {
+			t.Errorf("%s export dropped query param: %s", name, out)
+		}
+	}
+}
+
+func TestTiming_SetRequest(t *testing.T) {
+	var timing Timing
+	timing.SetRequest(&types.Timing{DNS: 1500 * time.Microsecond, TTFB: 20 * time.Millisecond, Total: 25 * time.Millisecond})
+
+	if timing.DNS != 1.5 || timing.TTFB != 20 || timing.Request != 25 {
+		t.Errorf("SetRequest() = %+v", timing)
+	}
+
+	phases := timing.Phases()
+	if phases[0].Name != "Cache lookup" || phases[len(phases)-1].Ms != 25 {
+		t.Errorf("Phases() = %+v, want cache lookup first and total last", phases)
+	}
+}
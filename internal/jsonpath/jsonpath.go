@@ -0,0 +1,173 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidJSON is returned when the input document is not valid JSON
+var ErrInvalidJSON = errors.New("invalid JSON")
+
+// ErrNotFound is returned when the path does not exist in the document
+var ErrNotFound = errors.New("path not found in JSON")
+
+// Extract returns the value at path within the JSON document.
+//
+// Supported path syntaxes:
+//   - Dotted keys:   "db.primary.password"
+//   - Array indices: "hosts.0", "hosts[0]", "servers[1].name"
+//   - JSON Pointer:  "/hosts/0", "/a~1b" (RFC 6901, must start with "/")
+//
+// String values are returned unquoted. Any other value (number, bool,
+// null, object, array) is returned as compact JSON.
+func Extract(jsonStr, path string) (string, error) {
+	doc, err := decode(jsonStr)
+	if err != nil {
+		return "", err
+	}
+
+	var value interface{}
+	if strings.HasPrefix(path, "/") || path == "" {
+		value, err = lookup(doc, parsePointer(path), path)
+	} else {
+		value, err = lookupDotted(doc, path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return format(value)
+}
+
+// decode parses a JSON document, preserving number literals
+func decode(jsonStr string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(jsonStr))
+	dec.UseNumber()
+
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	// Reject trailing data such as `{"a":1} garbage`
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: unexpected data after top-level value", ErrInvalidJSON)
+	}
+
+	return doc, nil
+}
+
+// lookupDotted resolves a dotted path. A top-level key that literally
+// contains dots (e.g. {"db.password": "x"}) takes precedence over
+// nested traversal.
+func lookupDotted(doc interface{}, path string) (interface{}, error) {
+	if obj, ok := doc.(map[string]interface{}); ok {
+		if v, exists := obj[path]; exists {
+			return v, nil
+		}
+	}
+
+	segments, err := parseDotted(path)
+	if err != nil {
+		return nil, err
+	}
+	return lookup(doc, segments, path)
+}
+
+// lookup walks the document following the given segments
+func lookup(doc interface{}, segments []string, path string) (interface{}, error) {
+	current := doc
+	for i, seg := range segments {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			v, exists := node[seg]
+			if !exists {
+				return nil, fmt.Errorf("%w: key '%s' (in '%s')", ErrNotFound, seg, path)
+			}
+			current = v
+
+		case []interface{}:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, fmt.Errorf("%w: index '%s' out of range (in '%s')", ErrNotFound, seg, path)
+			}
+			current = node[idx]
+
+		default:
+			traversed := strings.Join(segments[:i], ".")
+			if traversed == "" {
+				traversed = "<root>"
+			}
+			return nil, fmt.Errorf("%w: '%s' is not an object or array (in '%s')", ErrNotFound, traversed, path)
+		}
+	}
+	return current, nil
+}
+
+// parsePointer splits a JSON Pointer into unescaped reference tokens
+func parsePointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+
+	parts := strings.Split(pointer[1:], "/")
+	for i, p := range parts {
+		p = strings.ReplaceAll(p, "~1", "/")
+		parts[i] = strings.ReplaceAll(p, "~0", "~")
+	}
+	return parts
+}
+
+// parseDotted splits a dotted path with optional [n] index suffixes
+func parseDotted(path string) ([]string, error) {
+	var segments []string
+
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			return nil, fmt.Errorf("%w: empty segment in '%s'", ErrNotFound, path)
+		}
+
+		// Split "name[0][1]" into "name", "0", "1"
+		name := part
+		if idx := strings.Index(part, "["); idx != -1 {
+			name = part[:idx]
+			rest := part[idx:]
+			if name != "" {
+				segments = append(segments, name)
+			}
+			for rest != "" {
+				end := strings.Index(rest, "]")
+				if rest[0] != '[' || end == -1 {
+					return nil, fmt.Errorf("%w: malformed index in '%s'", ErrNotFound, path)
+				}
+				segments = append(segments, rest[1:end])
+				rest = rest[end+1:]
+			}
+			continue
+		}
+
+		segments = append(segments, name)
+	}
+
+	return segments, nil
+}
+
+// format renders a decoded value as a string
+func format(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return "", fmt.Errorf("failed to encode value: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
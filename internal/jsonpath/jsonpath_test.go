@@ -0,0 +1,255 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "simple string value",
+			json:     `{"password": "secret123"}`,
+			path:     "password",
+			expected: "secret123",
+		},
+		{
+			name:     "multiple keys",
+			json:     `{"username": "admin", "password": "secret123"}`,
+			path:     "password",
+			expected: "secret123",
+		},
+		{
+			name:     "numeric value",
+			json:     `{"port": 5432, "host": "localhost"}`,
+			path:     "port",
+			expected: "5432",
+		},
+		{
+			name:     "negative number",
+			json:     `{"offset": -10}`,
+			path:     "offset",
+			expected: "-10",
+		},
+		{
+			name:     "float number keeps literal",
+			json:     `{"rate": 0.50}`,
+			path:     "rate",
+			expected: "0.50",
+		},
+		{
+			name:     "boolean value",
+			json:     `{"enabled": true, "debug": false}`,
+			path:     "debug",
+			expected: "false",
+		},
+		{
+			name:     "null value",
+			json:     `{"value": null}`,
+			path:     "value",
+			expected: "null",
+		},
+		{
+			name:     "empty string value",
+			json:     `{"empty": ""}`,
+			path:     "empty",
+			expected: "",
+		},
+		{
+			name:     "extra whitespace",
+			json:     `{  "key"  :  "value"  }`,
+			path:     "key",
+			expected: "value",
+		},
+		{
+			name:     "special characters",
+			json:     `{"password": "P@$$w0rd!#%^&*()"}`,
+			path:     "password",
+			expected: "P@$$w0rd!#%^&*()",
+		},
+		{
+			name:     "escaped quotes",
+			json:     `{"msg": "say \"hello\""}`,
+			path:     "msg",
+			expected: `say "hello"`,
+		},
+		{
+			name:     "unicode escape",
+			json:     `{"msg": "café"}`,
+			path:     "msg",
+			expected: "café",
+		},
+		{
+			name:     "key appearing in a value first",
+			json:     `{"note": "\"password\": wrong", "password": "right"}`,
+			path:     "password",
+			expected: "right",
+		},
+		{
+			name:     "key appearing in a nested object first",
+			json:     `{"old": {"password": "wrong"}, "password": "right"}`,
+			path:     "password",
+			expected: "right",
+		},
+		{
+			name:     "nested dotted path",
+			json:     `{"db": {"primary": {"password": "p1"}, "replica": {"password": "p2"}}}`,
+			path:     "db.replica.password",
+			expected: "p2",
+		},
+		{
+			name:     "literal dotted key wins",
+			json:     `{"db.password": "flat", "db": {"password": "nested"}}`,
+			path:     "db.password",
+			expected: "flat",
+		},
+		{
+			name:     "array index with dot",
+			json:     `{"hosts": ["a.internal", "b.internal"]}`,
+			path:     "hosts.1",
+			expected: "b.internal",
+		},
+		{
+			name:     "array index with brackets",
+			json:     `{"servers": [{"name": "one"}, {"name": "two"}]}`,
+			path:     "servers[1].name",
+			expected: "two",
+		},
+		{
+			name:     "top-level array",
+			json:     `[["x", "y"], ["z"]]`,
+			path:     "[0][1]",
+			expected: "y",
+		},
+		{
+			name:     "json pointer",
+			json:     `{"hosts": ["a", "b"]}`,
+			path:     "/hosts/0",
+			expected: "a",
+		},
+		{
+			name:     "json pointer escapes",
+			json:     `{"a/b": {"c~d": "v"}}`,
+			path:     "/a~1b/c~0d",
+			expected: "v",
+		},
+		{
+			name:     "object returned as compact json",
+			json:     "{\"db\": {\"host\": \"h\",\n \"port\": 5432}}",
+			path:     "db",
+			expected: `{"host":"h","port":5432}`,
+		},
+		{
+			name:     "array returned as compact json",
+			json:     `{"tags": [1, "two", true]}`,
+			path:     "tags",
+			expected: `[1,"two",true]`,
+		},
+		{
+			name:     "html characters not escaped",
+			json:     `{"q": {"expr": "a<b&&c>d"}}`,
+			path:     "q",
+			expected: `{"expr":"a<b&&c>d"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Extract(tt.json, tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("got %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtract_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		path    string
+		wantErr error
+	}{
+		{"key not found", `{"username": "admin"}`, "password", ErrNotFound},
+		{"empty object", `{}`, "password", ErrNotFound},
+		{"nested key not found", `{"db": {"user": "x"}}`, "db.password", ErrNotFound},
+		{"index out of range", `{"hosts": ["a"]}`, "hosts.3", ErrNotFound},
+		{"non-numeric index", `{"hosts": ["a"]}`, "hosts.first", ErrNotFound},
+		{"traverse into string", `{"db": "x"}`, "db.password", ErrNotFound},
+		{"empty segment", `{"a": {"b": 1}}`, "a..b", ErrNotFound},
+		{"malformed index", `{"a": [1]}`, "a[0", ErrNotFound},
+		{"unterminated string", `{"key": "value`, "key", ErrInvalidJSON},
+		{"no colon after key", `{"key" "value"}`, "key", ErrInvalidJSON},
+		{"not json", `plain-text-secret`, "key", ErrInvalidJSON},
+		{"trailing data", `{"key": "v"} extra`, "key", ErrInvalidJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Extract(tt.json, tt.path)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtract_EmptyPathReturnsDocument(t *testing.T) {
+	result, err := Extract(`{ "a" : 1 }`, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != `{"a":1}` {
+		t.Errorf("got %q, want %q", result, `{"a":1}`)
+	}
+}
+
+func FuzzExtract(f *testing.F) {
+	f.Add(`{"password": "secret"}`, "password")
+	f.Add(`{"db": {"hosts": ["a", "b"]}}`, "db.hosts[1]")
+	f.Add(`{"a/b": [1, {"c": null}]}`, "/a~1b/1/c")
+	f.Add(`{"msg": "say \"hi\""}`, "msg")
+	f.Add(`[1, 2, 3]`, "[2]")
+	f.Add(`not json`, "x")
+
+	f.Fuzz(func(t *testing.T, doc, path string) {
+		result, err := Extract(doc, path)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrInvalidJSON) {
+				t.Fatalf("unexpected error kind: %v", err)
+			}
+			return
+		}
+
+		if !json.Valid([]byte(doc)) {
+			t.Fatalf("Extract succeeded on invalid JSON %q", doc)
+		}
+
+		// Non-string results are compact JSON and must round-trip unchanged
+		var probe interface{}
+		if json.Unmarshal([]byte(result), &probe) == nil {
+			if _, isString := probe.(string); isString {
+				return
+			}
+			again, err := Extract(result, "")
+			if err != nil {
+				t.Fatalf("re-extracting %q failed: %v", result, err)
+			}
+			if again != result {
+				t.Fatalf("result not compact: %q vs %q", result, again)
+			}
+		}
+	})
+}
@@ -0,0 +1,149 @@
+// Package jwt mints compact JWS tokens signed with RS256, ES256 or HS256.
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// Supported signing algorithms
+const (
+	RS256 = "RS256"
+	ES256 = "ES256"
+	HS256 = "HS256"
+)
+
+// Signer signs tokens with a fixed algorithm and key
+type Signer struct {
+	alg    string
+	kid    string
+	rsa    *rsa.PrivateKey
+	ecdsa  *ecdsa.PrivateKey
+	secret []byte
+}
+
+// NewSigner creates a signer. key is a PEM private key (PKCS#1, SEC 1 or
+// PKCS#8) for RS256/ES256, or the shared secret for HS256. kid, if set,
+// is added to the token header.
+func NewSigner(alg, key, kid string) (*Signer, error) {
+	s := &Signer{alg: alg, kid: kid}
+
+	switch alg {
+	case HS256:
+		if key == "" {
+			return nil, fmt.Errorf("HS256 requires a secret")
+		}
+		s.secret = []byte(key)
+
+	case RS256, ES256:
+		parsed, err := parsePrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		switch k := parsed.(type) {
+		case *rsa.PrivateKey:
+			if alg != RS256 {
+				return nil, fmt.Errorf("%s requires an EC P-256 key, got RSA", alg)
+			}
+			s.rsa = k
+		case *ecdsa.PrivateKey:
+			if alg != ES256 {
+				return nil, fmt.Errorf("%s requires an RSA key, got EC", alg)
+			}
+			if k.Curve != elliptic.P256() {
+				return nil, fmt.Errorf("ES256 requires a P-256 key, got %s", k.Curve.Params().Name)
+			}
+			s.ecdsa = k
+		default:
+			return nil, fmt.Errorf("unsupported private key type %T", parsed)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported algorithm '%s' (supported: %s, %s, %s)", alg, RS256, ES256, HS256)
+	}
+
+	return s, nil
+}
+
+// Sign returns a compact JWT for the claims
+func (s *Signer) Sign(claims map[string]any) (string, error) {
+	header := map[string]string{"alg": s.alg, "typ": "JWT"}
+	if s.kid != "" {
+		header["kid"] = s.kid
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("invalid claims: %w", err)
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(headerJSON) + "." + enc.EncodeToString(claimsJSON)
+
+	sig, err := s.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
+
+// sign computes the signature over the signing input
+func (s *Signer) sign(input []byte) ([]byte, error) {
+	switch s.alg {
+	case HS256:
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+
+	case RS256:
+		digest := sha256.Sum256(input)
+		return rsa.SignPKCS1v15(rand.Reader, s.rsa, crypto.SHA256, digest[:])
+
+	case ES256:
+		digest := sha256.Sum256(input)
+		r, sv, err := ecdsa.Sign(rand.Reader, s.ecdsa, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		// JWS uses fixed-width r||s rather than ASN.1
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		sv.FillBytes(sig[32:])
+		return sig, nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm '%s'", s.alg)
+}
+
+// parsePrivateKey decodes a PEM private key in PKCS#1, SEC 1 or PKCS#8 form
+func parsePrivateKey(key string) (any, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(key)))
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+
+	if k, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unsupported private key format in PEM block '%s'", block.Type)
+}
@@ -0,0 +1,156 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func rsaKeyPEM(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func ecKeyPEM(t *testing.T, curve elliptic.Curve) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+// splitToken decodes a token's header and claims and returns the signing
+// input and raw signature
+func splitToken(t *testing.T, token string) (header, claims map[string]any, input string, sig []byte) {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token has %d segments, want 3", len(parts))
+	}
+	dec := base64.RawURLEncoding
+	for i, dst := range []*map[string]any{&header, &claims} {
+		raw, err := dec.DecodeString(parts[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(raw, dst); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sig, err := dec.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return header, claims, parts[0] + "." + parts[1], sig
+}
+
+func TestSigner_Sign(t *testing.T) {
+	rsaKey, rsaPEM := rsaKeyPEM(t)
+	ecKey, ecPEM := ecKeyPEM(t, elliptic.P256())
+
+	tests := []struct {
+		alg    string
+		key    string
+		verify func(input string, sig []byte) bool
+	}{
+		{
+			alg: RS256,
+			key: rsaPEM,
+			verify: func(input string, sig []byte) bool {
+				digest := sha256.Sum256([]byte(input))
+				return rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest[:], sig) == nil
+			},
+		},
+		{
+			alg: ES256,
+			key: ecPEM,
+			verify: func(input string, sig []byte) bool {
+				digest := sha256.Sum256([]byte(input))
+				r := new(big.Int).SetBytes(sig[:32])
+				s := new(big.Int).SetBytes(sig[32:])
+				return len(sig) == 64 && ecdsa.Verify(&ecKey.PublicKey, digest[:], r, s)
+			},
+		},
+		{
+			alg: HS256,
+			key: "shared-secret",
+			verify: func(input string, sig []byte) bool {
+				mac := hmac.New(sha256.New, []byte("shared-secret"))
+				mac.Write([]byte(input))
+				return hmac.Equal(mac.Sum(nil), sig)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			signer, err := NewSigner(tt.alg, tt.key, "key-1")
+			if err != nil {
+				t.Fatalf("NewSigner() error = %v", err)
+			}
+
+			token, err := signer.Sign(map[string]any{"iss": "sreq", "n": 1})
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			header, claims, input, sig := splitToken(t, token)
+			if header["alg"] != tt.alg || header["kid"] != "key-1" || header["typ"] != "JWT" {
+				t.Errorf("header = %v", header)
+			}
+			if claims["iss"] != "sreq" {
+				t.Errorf("claims = %v", claims)
+			}
+			if !tt.verify(input, sig) {
+				t.Error("signature does not verify")
+			}
+		})
+	}
+}
+
+func TestNewSigner_Errors(t *testing.T) {
+	_, rsaPEM := rsaKeyPEM(t)
+	_, ecPEM := ecKeyPEM(t, elliptic.P256())
+	_, p384PEM := ecKeyPEM(t, elliptic.P384())
+
+	tests := []struct {
+		name string
+		alg  string
+		key  string
+	}{
+		{"unknown algorithm", "PS256", rsaPEM},
+		{"HS256 without secret", HS256, ""},
+		{"not PEM", RS256, "not a key"},
+		{"RS256 with EC key", RS256, ecPEM},
+		{"ES256 with RSA key", ES256, rsaPEM},
+		{"ES256 with P-384 key", ES256, p384PEM},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSigner(tt.alg, tt.key, ""); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Package oauth obtains and refreshes OAuth2 access tokens for services
+// using the client-credentials and refresh-token grants.
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// ExpiryLeeway is how long before expiry a token is considered stale,
+// so it is not rejected in flight
+const ExpiryLeeway = 30 * time.Second
+
+// Store persists tokens between runs (implemented by the credential cache).
+// key is CacheKey of the auth config the token was issued for.
+type Store interface {
+	GetToken(service, env, key string) (*types.OAuthToken, error)
+	SetToken(service, env, key string, token *types.OAuthToken) error
+}
+
+// CacheKey identifies the token endpoint, client and requested access of
+// an oauth2 config, so tokens are not reused after any of them changes
+func CacheKey(auth *types.ResolvedAuth) string {
+	parts := []string{auth.TokenURL, auth.GrantType, auth.ClientID, strings.Join(auth.Scopes, " "), auth.Audience}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Source hands out access tokens for one service/env, fetching and
+// refreshing them as needed
+type Source struct {
+	auth       *types.ResolvedAuth
+	service    string
+	env        string
+	store      Store
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	token *types.OAuthToken
+}
+
+// NewSource creates a token source. store and httpClient may be nil.
+func NewSource(auth *types.ResolvedAuth, service, env string, store Store, httpClient *http.Client) *Source {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Source{
+		auth:       auth,
+		service:    service,
+		env:        env,
+		store:      store,
+		httpClient: httpClient,
+	}
+}
+
+// Token returns a valid access token, using the cache when possible
+func (s *Source) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil && s.store != nil {
+		// A broken cache entry is treated as a miss
+		s.token, _ = s.store.GetToken(s.service, s.env, CacheKey(s.auth))
+	}
+
+	if s.token.Valid(ExpiryLeeway) {
+		return s.token.AccessToken, nil
+	}
+
+	token, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token
+	if s.store != nil {
+		// Failing to cache only costs a fetch next time
+		_ = s.store.SetToken(s.service, s.env, CacheKey(s.auth), token)
+	}
+	return token.AccessToken, nil
+}
+
+// Invalidate drops the current access token (e.g. after a 401), keeping
+// its refresh token so the next Token call can refresh it
+func (s *Source) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil {
+		s.token = &types.OAuthToken{RefreshToken: s.token.RefreshToken}
+	}
+}
+
+// fetch obtains a new token, preferring a refresh token when one is known
+func (s *Source) fetch(ctx context.Context) (*types.OAuthToken, error) {
+	refreshToken := s.auth.RefreshToken
+	if s.token != nil && s.token.RefreshToken != "" {
+		refreshToken = s.token.RefreshToken
+	}
+
+	if refreshToken != "" {
+		token, err := s.request(ctx, url.Values{
+			"grant_type":    {types.GrantRefreshToken},
+			"refresh_token": {refreshToken},
+		})
+		if err == nil {
+			if token.RefreshToken == "" {
+				token.RefreshToken = refreshToken
+			}
+			return token, nil
+		}
+		// A revoked refresh token can still be replaced via client credentials
+		if s.auth.GrantType != types.GrantClientCredentials {
+			return nil, err
+		}
+	}
+
+	return s.request(ctx, url.Values{"grant_type": {types.GrantClientCredentials}})
+}
+
+// tokenResponse is the token endpoint's JSON response (RFC 6749 §5)
+type tokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	TokenType        string      `json:"token_type"`
+	ExpiresIn        json.Number `json:"expires_in"`
+	RefreshToken     string      `json:"refresh_token"`
+	Error            string      `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
+
+// request posts a token request and parses the response
+func (s *Source) request(ctx context.Context, form url.Values) (*types.OAuthToken, error) {
+	if len(s.auth.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.auth.Scopes, " "))
+	}
+	if s.auth.Audience != "" {
+		form.Set("audience", s.auth.Audience)
+	}
+	if s.auth.AuthStyle != "header" {
+		form.Set("client_id", s.auth.ClientID)
+		if s.auth.ClientSecret != "" {
+			form.Set("client_secret", s.auth.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.auth.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, sreerrors.OAuthTokenFailed(s.auth.TokenURL, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.auth.AuthStyle == "header" {
+		req.SetBasicAuth(url.QueryEscape(s.auth.ClientID), url.QueryEscape(s.auth.ClientSecret))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, sreerrors.OAuthTokenFailed(s.auth.TokenURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, sreerrors.OAuthTokenFailed(s.auth.TokenURL, err)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, sreerrors.OAuthTokenFailed(s.auth.TokenURL,
+			fmt.Errorf("HTTP %d: invalid token response: %w", resp.StatusCode, err))
+	}
+
+	if resp.StatusCode != http.StatusOK || tr.Error != "" || tr.AccessToken == "" {
+		cause := fmt.Errorf("HTTP %d", resp.StatusCode)
+		switch {
+		case tr.Error != "" && tr.ErrorDescription != "":
+			cause = fmt.Errorf("HTTP %d: %s: %s", resp.StatusCode, tr.Error, tr.ErrorDescription)
+		case tr.Error != "":
+			cause = fmt.Errorf("HTTP %d: %s", resp.StatusCode, tr.Error)
+		case tr.AccessToken == "":
+			cause = fmt.Errorf("HTTP %d: response has no access_token", resp.StatusCode)
+		}
+		return nil, sreerrors.OAuthTokenFailed(s.auth.TokenURL, cause)
+	}
+
+	token := &types.OAuthToken{
+		AccessToken:  tr.AccessToken,
+		TokenType:    tr.TokenType,
+		RefreshToken: tr.RefreshToken,
+	}
+	if secs, err := tr.ExpiresIn.Int64(); err == nil && secs > 0 {
+		token.Expiry = time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,275 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// memStore is an in-memory Store
+type memStore struct {
+	mu     sync.Mutex
+	tokens map[string]*types.OAuthToken
+}
+
+func (m *memStore) GetToken(service, env, key string) (*types.OAuthToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tokens[service+"/"+env+"/"+key], nil
+}
+
+func (m *memStore) SetToken(service, env, key string, token *types.OAuthToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tokens == nil {
+		m.tokens = make(map[string]*types.OAuthToken)
+	}
+	m.tokens[service+"/"+env+"/"+key] = token
+	return nil
+}
+
+func testAuth(tokenURL string) *types.ResolvedAuth {
+	return &types.ResolvedAuth{
+		Type:         types.AuthOAuth2,
+		TokenURL:     tokenURL,
+		GrantType:    types.GrantClientCredentials,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"a", "b"},
+		Audience:     "orders-api",
+		AuthStyle:    "body",
+	}
+}
+
+func TestSource_ClientCredentials(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{
+			"grant_type":    "client_credentials",
+			"client_id":     "client",
+			"client_secret": "secret",
+			"scope":         "a b",
+			"audience":      "orders-api",
+		}
+		for k, v := range want {
+			if got := r.PostForm.Get(k); got != v && calls == 1 {
+				t.Errorf("%s = %q, want %q", k, got, v)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"tok-1","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	store := &memStore{}
+	src := NewSource(testAuth(server.URL), "orders", "dev", store, nil)
+
+	for i := 0; i < 2; i++ {
+		tok, err := src.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+		if tok != "tok-1" {
+			t.Errorf("Token() = %q, want %q", tok, "tok-1")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("token endpoint called %d times, want 1", calls)
+	}
+
+	cached, _ := store.GetToken("orders", "dev", CacheKey(testAuth(server.URL)))
+	if cached == nil || time.Until(cached.Expiry) < 59*time.Minute {
+		t.Errorf("cached token = %+v, want expiry in ~1h", cached)
+	}
+
+	// A new source (next run) reuses the stored token
+	if _, err := NewSource(testAuth(server.URL), "orders", "dev", store, nil).Token(context.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("token endpoint called %d times after restart, want 1", calls)
+	}
+
+	// Changed scopes need a new token
+	auth := testAuth(server.URL)
+	auth.Scopes = []string{"a", "b", "admin"}
+	if _, err := NewSource(auth, "orders", "dev", store, nil).Token(context.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("token endpoint called %d times after a scope change, want 2", calls)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	base := testAuth("https://login.example.com/token")
+	key := CacheKey(base)
+	if CacheKey(testAuth("https://login.example.com/token")) != key {
+		t.Error("CacheKey() differs for the same config")
+	}
+
+	for name, change := range map[string]func(*types.ResolvedAuth){
+		"token_url": func(a *types.ResolvedAuth) { a.TokenURL = "https://login2.example.com/token" },
+		"client_id": func(a *types.ResolvedAuth) { a.ClientID = "other" },
+		"scopes":    func(a *types.ResolvedAuth) { a.Scopes = []string{"a"} },
+		"audience":  func(a *types.ResolvedAuth) { a.Audience = "billing-api" },
+	} {
+		a := testAuth("https://login.example.com/token")
+		change(a)
+		if CacheKey(a) == key {
+			t.Errorf("CacheKey() unchanged after changing %s", name)
+		}
+	}
+
+	// The secret is not part of the key
+	a := testAuth("https://login.example.com/token")
+	a.ClientSecret = "rotated"
+	if CacheKey(a) != key {
+		t.Error("CacheKey() changed with the client secret")
+	}
+}
+
+func TestSource_RefreshesExpiredToken(t *testing.T) {
+	var grants []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		grants = append(grants, r.PostForm.Get("grant_type"))
+		if r.PostForm.Get("grant_type") == "refresh_token" && r.PostForm.Get("refresh_token") != "r-1" {
+			t.Errorf("refresh_token = %q, want %q", r.PostForm.Get("refresh_token"), "r-1")
+		}
+		_, _ = w.Write([]byte(`{"access_token":"fresh","expires_in":"600"}`))
+	}))
+	defer server.Close()
+
+	store := &memStore{}
+	_ = store.SetToken("orders", "dev", CacheKey(testAuth(server.URL)), &types.OAuthToken{
+		AccessToken:  "stale",
+		RefreshToken: "r-1",
+		Expiry:       time.Now().Add(10 * time.Second), // inside the leeway
+	})
+
+	src := NewSource(testAuth(server.URL), "orders", "dev", store, nil)
+	tok, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if tok != "fresh" {
+		t.Errorf("Token() = %q, want %q", tok, "fresh")
+	}
+	if len(grants) != 1 || grants[0] != "refresh_token" {
+		t.Errorf("grants = %v, want [refresh_token]", grants)
+	}
+
+	// The refresh token is kept when the server doesn't rotate it
+	cached, _ := store.GetToken("orders", "dev", CacheKey(testAuth(server.URL)))
+	if cached.RefreshToken != "r-1" {
+		t.Errorf("RefreshToken = %q, want %q", cached.RefreshToken, "r-1")
+	}
+}
+
+func TestSource_RevokedRefreshFallsBackToClientCredentials(t *testing.T) {
+	var grants []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		grant := r.PostForm.Get("grant_type")
+		grants = append(grants, grant)
+		if grant == "refresh_token" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"new"}`))
+	}))
+	defer server.Close()
+
+	store := &memStore{}
+	_ = store.SetToken("orders", "dev", CacheKey(testAuth(server.URL)), &types.OAuthToken{RefreshToken: "revoked"})
+
+	tok, err := NewSource(testAuth(server.URL), "orders", "dev", store, nil).Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if tok != "new" {
+		t.Errorf("Token() = %q, want %q", tok, "new")
+	}
+	if len(grants) != 2 {
+		t.Errorf("grants = %v, want refresh then client_credentials", grants)
+	}
+}
+
+func TestSource_HeaderAuthStyle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if u, p, ok := r.BasicAuth(); !ok || u != "client" || p != "secret" {
+			t.Errorf("BasicAuth() = %q, %q, %v", u, p, ok)
+		}
+		if r.PostForm.Get("client_secret") != "" {
+			t.Error("client_secret should not be sent in the body")
+		}
+		_, _ = w.Write([]byte(`{"access_token":"tok"}`))
+	}))
+	defer server.Close()
+
+	auth := testAuth(server.URL)
+	auth.AuthStyle = "header"
+	if _, err := NewSource(auth, "orders", "dev", nil, nil).Token(context.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+}
+
+func TestSource_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"oauth error", http.StatusUnauthorized, `{"error":"invalid_client","error_description":"bad secret"}`},
+		{"missing access token", http.StatusOK, `{"token_type":"Bearer"}`},
+		{"not json", http.StatusBadGateway, `<html>bad gateway</html>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewSource(testAuth(server.URL), "orders", "dev", nil, nil).Token(context.Background())
+			var sreqErr *sreerrors.SreqError
+			if !errors.As(err, &sreqErr) || sreqErr.Type != sreerrors.ErrAuth {
+				t.Errorf("Token() error = %v, want auth error", err)
+			}
+		})
+	}
+}
+
+func TestSource_Invalidate(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"access_token":"tok","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	src := NewSource(testAuth(server.URL), "orders", "dev", nil, nil)
+	_, _ = src.Token(context.Background())
+	src.Invalidate()
+	_, _ = src.Token(context.Background())
+
+	if calls != 2 {
+		t.Errorf("token endpoint called %d times, want 2", calls)
+	}
+}
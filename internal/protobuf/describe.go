@@ -0,0 +1,124 @@
+package protobuf
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Describe renders a service, method, message or enum in .proto syntax.
+// Type names are fully qualified.
+func (r *Registry) Describe(symbol string) (string, error) {
+	symbol = strings.TrimPrefix(symbol, ".")
+	var b strings.Builder
+
+	d, err := r.files.FindDescriptorByName(protoreflect.FullName(symbol))
+	if err != nil {
+		if _, _, ok := SplitMethod(symbol); ok {
+			if m, err := r.Method(symbol); err == nil {
+				return methodSignature(m) + "\n", nil
+			}
+		}
+		return "", fmt.Errorf("symbol %s not found", symbol)
+	}
+
+	switch d := d.(type) {
+	case protoreflect.ServiceDescriptor:
+		fmt.Fprintf(&b, "service %s {\n", d.FullName())
+		for i := 0; i < d.Methods().Len(); i++ {
+			fmt.Fprintf(&b, "  %s\n", methodSignature(d.Methods().Get(i)))
+		}
+		b.WriteString("}\n")
+	case protoreflect.MethodDescriptor:
+		b.WriteString(methodSignature(d) + "\n")
+	case protoreflect.MessageDescriptor:
+		describeMessage(&b, d, "")
+	case protoreflect.EnumDescriptor:
+		describeEnum(&b, d, "")
+	default:
+		return "", fmt.Errorf("%s is not a service, method, message or enum", symbol)
+	}
+	return b.String(), nil
+}
+
+func methodSignature(m protoreflect.MethodDescriptor) string {
+	in, out := "."+string(m.Input().FullName()), "."+string(m.Output().FullName())
+	if m.IsStreamingClient() {
+		in = "stream " + in
+	}
+	if m.IsStreamingServer() {
+		out = "stream " + out
+	}
+	return fmt.Sprintf("rpc %s(%s) returns (%s);", m.Name(), in, out)
+}
+
+func describeMessage(b *strings.Builder, m protoreflect.MessageDescriptor, indent string) {
+	fmt.Fprintf(b, "%smessage %s {\n", indent, m.FullName())
+	inner := indent + "  "
+
+	printed := map[protoreflect.FullName]bool{}
+	fields := m.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		if o := f.ContainingOneof(); o != nil && !o.IsSynthetic() {
+			if printed[o.FullName()] {
+				continue
+			}
+			printed[o.FullName()] = true
+			fmt.Fprintf(b, "%soneof %s {\n", inner, o.Name())
+			for j := 0; j < o.Fields().Len(); j++ {
+				fmt.Fprintf(b, "%s  %s\n", inner, fieldDecl(o.Fields().Get(j)))
+			}
+			fmt.Fprintf(b, "%s}\n", inner)
+			continue
+		}
+		fmt.Fprintf(b, "%s%s\n", inner, fieldDecl(f))
+	}
+
+	for i := 0; i < m.Enums().Len(); i++ {
+		describeEnum(b, m.Enums().Get(i), inner)
+	}
+	for i := 0; i < m.Messages().Len(); i++ {
+		if n := m.Messages().Get(i); !n.IsMapEntry() {
+			describeMessage(b, n, inner)
+		}
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+func fieldDecl(f protoreflect.FieldDescriptor) string {
+	if f.IsMap() {
+		return fmt.Sprintf("map<%s, %s> %s = %d;", fieldType(f.MapKey()), fieldType(f.MapValue()), f.Name(), f.Number())
+	}
+
+	label := ""
+	switch {
+	case f.IsList():
+		label = "repeated "
+	case f.HasOptionalKeyword():
+		label = "optional "
+	case f.Cardinality() == protoreflect.Required:
+		label = "required "
+	}
+	return fmt.Sprintf("%s%s %s = %d;", label, fieldType(f), f.Name(), f.Number())
+}
+
+func fieldType(f protoreflect.FieldDescriptor) string {
+	switch f.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return "." + string(f.Message().FullName())
+	case protoreflect.EnumKind:
+		return "." + string(f.Enum().FullName())
+	}
+	return f.Kind().String()
+}
+
+func describeEnum(b *strings.Builder, e protoreflect.EnumDescriptor, indent string) {
+	fmt.Fprintf(b, "%senum %s {\n", indent, e.FullName())
+	for i := 0; i < e.Values().Len(); i++ {
+		v := e.Values().Get(i)
+		fmt.Fprintf(b, "%s  %s = %d;\n", indent, v.Name(), v.Number())
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
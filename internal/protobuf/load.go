@@ -0,0 +1,111 @@
+package protobuf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// LoadFiles compiles .proto files and their imports into a registry.
+// Imports are looked up in importPaths, then among the well-known types
+// and the google.api annotations.
+// Without import paths, the current directory is used.
+func LoadFiles(names []string, importPaths []string) (*Registry, error) {
+	if len(importPaths) == 0 {
+		importPaths = []string{"."}
+	}
+
+	// Files named on the command line are known by their path relative
+	// to the import path containing them, as with protoc
+	rel := make([]string, len(names))
+	for i, name := range names {
+		rel[i] = importName(name, importPaths)
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.CompositeResolver{
+			&protocompile.SourceResolver{ImportPaths: importPaths},
+			protocompile.ResolverFunc(builtinFile),
+		},
+	}
+	compiled, err := compiler.Compile(context.Background(), rel...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := new(protoregistry.Files)
+	for _, f := range compiled {
+		if err := register(files, f); err != nil {
+			return nil, err
+		}
+	}
+	return newRegistry(files), nil
+}
+
+// builtinFile resolves imports linked into the binary
+func builtinFile(path string) (protocompile.SearchResult, error) {
+	f, err := protoregistry.GlobalFiles.FindFileByPath(path)
+	if err != nil {
+		return protocompile.SearchResult{}, err
+	}
+	return protocompile.SearchResult{Desc: f}, nil
+}
+
+// register adds f and, first, everything it imports
+func register(files *protoregistry.Files, f protoreflect.FileDescriptor) error {
+	if _, err := files.FindFileByPath(f.Path()); err == nil {
+		return nil
+	}
+	imports := f.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		if err := register(files, imports.Get(i).FileDescriptor); err != nil {
+			return err
+		}
+	}
+	return files.RegisterFile(f)
+}
+
+// importName returns path relative to the import path containing it
+func importName(path string, importPaths []string) string {
+	name := filepath.ToSlash(filepath.Clean(path))
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return name
+	}
+	for _, dir := range importPaths {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(absDir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return name
+}
+
+// LoadFileSet reads a FileDescriptorSet, as written by
+// protoc --include_imports --descriptor_set_out, into a registry
+func LoadFileSet(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%s: invalid descriptor set: %w", path, err)
+	}
+	reg, err := NewFileSet(set.File)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return reg, nil
+}
@@ -0,0 +1,378 @@
+package protobuf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const testProto = `
+syntax = "proto3";
+
+package shop.v1;
+
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+import "google/protobuf/struct.proto";
+
+// Orders service
+service OrderService {
+  rpc GetOrder(GetOrderRequest) returns (Order);
+  rpc WatchOrders(GetOrderRequest) returns (stream Order);
+}
+
+message GetOrderRequest {
+  string id = 1;
+}
+
+message Order {
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+    PAID = 1;
+    SHIPPED = 2;
+  }
+
+  message Line {
+    string sku = 1;
+    int32 quantity = 2;
+  }
+
+  string id = 1;
+  Status status = 2;
+  repeated Line lines = 3;
+  int64 total_cents = 4;
+  repeated int32 tags = 5;
+  map<string, int64> counts = 6;
+  bytes blob = 7;
+  double ratio = 8;
+  bool gift = 9;
+  sint32 delta = 10;
+  google.protobuf.Timestamp created_at = 11;
+  google.protobuf.StringValue note = 12;
+  google.protobuf.Struct attrs = 13;
+  oneof payment {
+    string card = 14;
+    string voucher = 15;
+  }
+}
+`
+
+func loadTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "shop.proto"), []byte(testProto), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reg, err := LoadFiles([]string{filepath.Join(dir, "shop.proto")}, []string{dir})
+	if err != nil {
+		t.Fatalf("LoadFiles() error = %v", err)
+	}
+	return reg
+}
+
+func TestLoadFiles(t *testing.T) {
+	reg := loadTestRegistry(t)
+
+	if got := reg.Services(); len(got) != 1 || got[0] != "shop.v1.OrderService" {
+		t.Fatalf("Services() = %v", got)
+	}
+
+	m, err := reg.Method("shop.v1.OrderService/WatchOrders")
+	if err != nil {
+		t.Fatalf("Method() error = %v", err)
+	}
+	if m.Input().FullName() != "shop.v1.GetOrderRequest" || m.Output().FullName() != "shop.v1.Order" || !m.IsStreamingServer() {
+		t.Errorf("Method = %v", m)
+	}
+	if _, err := reg.Method("shop.v1.OrderService.GetOrder"); err != nil {
+		t.Errorf("Method(dotted) error = %v", err)
+	}
+	if _, err := reg.Method("shop.v1.OrderService/Nope"); err == nil {
+		t.Error("Method(unknown) error = nil")
+	}
+}
+
+func TestLoadFiles_Annotations(t *testing.T) {
+	dir := t.TempDir()
+	src := `syntax = "proto3";
+package api.v1;
+import "google/api/annotations.proto";
+import "google/api/field_behavior.proto";
+message Req { string id = 1 [(google.api.field_behavior) = REQUIRED]; }
+service Items {
+  rpc Get(Req) returns (Req) { option (google.api.http) = { get: "/v1/items/{id}" }; }
+}`
+	path := filepath.Join(dir, "items.proto")
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reg, err := LoadFiles([]string{path}, []string{dir})
+	if err != nil {
+		t.Fatalf("LoadFiles() error = %v", err)
+	}
+	if _, err := reg.Method("api.v1.Items/Get"); err != nil {
+		t.Errorf("Method() error = %v", err)
+	}
+}
+
+func TestLoadFiles_Errors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, src string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name     string
+		src      string
+		contains string
+	}{
+		{"unknown type", `syntax = "proto3"; message A { Missing m = 1; }`, "Missing"},
+		{"missing import", `syntax = "proto3"; import "nope.proto";`, "nope.proto"},
+		{"syntax error", `syntax = "proto3"; message A { string = 1; }`, "bad.proto"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := write("bad.proto", tt.src)
+			_, err := LoadFiles([]string{path}, []string{dir})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("error = %q, should contain %q", err.Error(), tt.contains)
+			}
+		})
+	}
+}
+
+func orderType(t *testing.T, reg *Registry) protoreflect.MessageDescriptor {
+	t.Helper()
+	m, err := reg.Method("shop.v1.OrderService/GetOrder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m.Output()
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	reg := loadTestRegistry(t)
+	order := orderType(t, reg)
+
+	in := `{
+		"id": "o-1",
+		"status": "SHIPPED",
+		"lines": [{"sku": "A", "quantity": 2}, {"sku": "B", "quantity": -1}],
+		"total_cents": 9007199254740993,
+		"tags": [1, 2, 300],
+		"counts": {"a": "1", "b": 2},
+		"blob": "aGVsbG8=",
+		"ratio": 0.25,
+		"gift": true,
+		"delta": -5,
+		"createdAt": "2026-01-02T03:04:05.5Z",
+		"note": "leave at door",
+		"attrs": {"color": "red", "size": [1, null, true]},
+		"voucher": "SPRING"
+	}`
+
+	data, err := reg.FromJSON(order, []byte(in))
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+	out, err := reg.ToJSON(order, data)
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+
+	want := `{"id":"o-1","status":"SHIPPED","lines":[{"sku":"A","quantity":2},{"sku":"B","quantity":-1}],` +
+		`"totalCents":"9007199254740993","tags":[1,2,300],"counts":{"a":"1","b":"2"},"blob":"aGVsbG8=",` +
+		`"ratio":0.25,"gift":true,"delta":-5,"createdAt":"2026-01-02T03:04:05.500Z","note":"leave at door",` +
+		`"attrs":{"color":"red","size":[1,null,true]},"voucher":"SPRING"}`
+	if string(out) != want {
+		t.Errorf("ToJSON() =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestFromJSON_Errors(t *testing.T) {
+	reg := loadTestRegistry(t)
+	order := orderType(t, reg)
+
+	tests := []struct {
+		name     string
+		in       string
+		contains string
+	}{
+		{"unknown field", `{"nope": 1}`, "nope"},
+		{"unknown enum", `{"status": "LOST"}`, "LOST"},
+		{"int32 overflow", `{"lines": [{"quantity": 3000000000}]}`, "quantity"},
+		{"wrong kind", `{"id": 5}`, "id"},
+		{"bad timestamp", `{"createdAt": "yesterday"}`, "Timestamp"},
+		{"not an object", `[1]`, "["},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := reg.FromJSON(order, []byte(tt.in))
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("error = %q, should contain %q", err.Error(), tt.contains)
+			}
+		})
+	}
+}
+
+func TestToJSON_Invalid(t *testing.T) {
+	reg := loadTestRegistry(t)
+	if _, err := reg.ToJSON(orderType(t, reg), []byte{0xff, 0xff}); err == nil {
+		t.Error("ToJSON(garbage) error = nil")
+	}
+}
+
+// writeFileSet writes files, as protoc --descriptor_set_out does
+func writeFileSet(t *testing.T, files ...protoreflect.FileDescriptor) string {
+	t.Helper()
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, f := range files {
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(f))
+	}
+	b, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "set.pb")
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileSet(t *testing.T) {
+	// The protoc-generated reflection descriptor, round-tripped through a
+	// descriptor set, decodes what the generated code encodes
+	reg, err := LoadFileSet(writeFileSet(t, rpb.File_grpc_reflection_v1_reflection_proto))
+	if err != nil {
+		t.Fatalf("LoadFileSet() error = %v", err)
+	}
+	m, err := reg.Method("grpc.reflection.v1.ServerReflection/ServerReflectionInfo")
+	if err != nil {
+		t.Fatalf("Method() error = %v", err)
+	}
+
+	data, err := proto.Marshal(&rpb.ServerReflectionResponse{
+		ValidHost: "h",
+		MessageResponse: &rpb.ServerReflectionResponse_ListServicesResponse{
+			ListServicesResponse: &rpb.ListServiceResponse{Service: []*rpb.ServiceResponse{{Name: "shop.v1.OrderService"}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := reg.ToJSON(m.Output(), data)
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	if want := `{"validHost":"h","listServicesResponse":{"service":[{"name":"shop.v1.OrderService"}]}}`; string(out) != want {
+		t.Errorf("ToJSON() = %s, want %s", out, want)
+	}
+
+	in, err := reg.FromJSON(m.Input(), []byte(`{"fileContainingSymbol": "shop.v1.Order"}`))
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+	var req rpb.ServerReflectionRequest
+	if err := proto.Unmarshal(in, &req); err != nil || req.GetFileContainingSymbol() != "shop.v1.Order" {
+		t.Errorf("decoded request = %v, %v", &req, err)
+	}
+}
+
+func TestNewFileSet_WellKnownImports(t *testing.T) {
+	// A set without the well-known types it imports, as some servers send
+	reg := loadTestRegistry(t)
+	d, err := reg.files.FindFileByPath("shop.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := NewFileSet([]*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(d)})
+	if err != nil {
+		t.Fatalf("NewFileSet() error = %v", err)
+	}
+	order := orderType(t, set)
+	data, err := set.FromJSON(order, []byte(`{"createdAt": "2026-01-02T03:04:05Z"}`))
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+	if out, _ := set.ToJSON(order, data); string(out) != `{"createdAt":"2026-01-02T03:04:05Z"}` {
+		t.Errorf("ToJSON() = %s", out)
+	}
+
+	// google/api/annotations.proto brings in its own imports
+	api := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("api.proto"),
+		Package:    proto.String("api"),
+		Dependency: []string{"google/api/annotations.proto"},
+		Syntax:     proto.String("proto3"),
+	}
+	if _, err := NewFileSet([]*descriptorpb.FileDescriptorProto{api}); err != nil {
+		t.Errorf("NewFileSet(annotations) error = %v", err)
+	}
+
+	bad := &descriptorpb.FileDescriptorProto{Name: proto.String("a.proto"), Dependency: []string{"missing.proto"}}
+	if _, err := NewFileSet([]*descriptorpb.FileDescriptorProto{bad}); err == nil || !strings.Contains(err.Error(), "missing.proto") {
+		t.Errorf("NewFileSet(missing import) error = %v", err)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	reg := loadTestRegistry(t)
+
+	tests := []struct {
+		symbol   string
+		contains []string
+	}{
+		{"shop.v1.OrderService", []string{
+			"service shop.v1.OrderService {",
+			"rpc GetOrder(.shop.v1.GetOrderRequest) returns (.shop.v1.Order);",
+			"rpc WatchOrders(.shop.v1.GetOrderRequest) returns (stream .shop.v1.Order);",
+		}},
+		{"shop.v1.OrderService/GetOrder", []string{"rpc GetOrder("}},
+		{"shop.v1.OrderService.WatchOrders", []string{"rpc WatchOrders("}},
+		{"shop.v1.Order", []string{
+			"repeated .shop.v1.Order.Line lines = 3;",
+			"map<string, int64> counts = 6;",
+			"oneof payment {\n    string card = 14;\n    string voucher = 15;\n  }",
+			"enum shop.v1.Order.Status {",
+			".google.protobuf.Timestamp created_at = 11;",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			got, err := reg.Describe(tt.symbol)
+			if err != nil {
+				t.Fatalf("Describe() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Describe() =\n%s\nshould contain %q", got, want)
+				}
+			}
+		})
+	}
+
+	if _, err := reg.Describe("shop.v1.Nope"); err == nil {
+		t.Error("expected error for unknown symbol")
+	}
+}
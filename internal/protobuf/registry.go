@@ -0,0 +1,169 @@
+// Package protobuf loads message types from .proto files, descriptor sets
+// or server reflection, and converts messages to and from JSON with the
+// proto3 JSON mapping. It wraps google.golang.org/protobuf.
+package protobuf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// Well-known types and the google.api HTTP annotations, for imports
+	// that a descriptor set or reflection leaves out
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	_ "google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Registry holds a set of files and the types they define
+type Registry struct {
+	files *protoregistry.Files
+	types *dynamicpb.Types
+}
+
+func newRegistry(files *protoregistry.Files) *Registry {
+	return &Registry{files: files, types: dynamicpb.NewTypes(files)}
+}
+
+// NewFileSet builds a registry from FileDescriptorProtos. Imports missing
+// from files are taken from the well-known types and google.api annotations.
+func NewFileSet(files []*descriptorpb.FileDescriptorProto) (*Registry, error) {
+	set := &descriptorpb.FileDescriptorSet{File: files}
+	for missing := MissingDependencies(set.File); len(missing) > 0; missing = MissingDependencies(set.File) {
+		for _, name := range missing {
+			builtin, err := protoregistry.GlobalFiles.FindFileByPath(name)
+			if err != nil {
+				return nil, fmt.Errorf("import %q not found", name)
+			}
+			set.File = append(set.File, protodesc.ToFileDescriptorProto(builtin))
+		}
+	}
+
+	reg, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, err
+	}
+	return newRegistry(reg), nil
+}
+
+// MissingDependencies lists the imports of files that are not among them
+func MissingDependencies(files []*descriptorpb.FileDescriptorProto) []string {
+	have := map[string]bool{}
+	for _, f := range files {
+		have[f.GetName()] = true
+	}
+	seen := map[string]bool{}
+	var missing []string
+	for _, f := range files {
+		for _, dep := range f.GetDependency() {
+			if !have[dep] && !seen[dep] {
+				seen[dep] = true
+				missing = append(missing, dep)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Service returns a service by full name
+func (r *Registry) Service(name string) (protoreflect.ServiceDescriptor, error) {
+	name = strings.TrimPrefix(name, ".")
+	d, err := r.files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found", name)
+	}
+	svc, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", name)
+	}
+	return svc, nil
+}
+
+// Services lists the full names of all services, sorted
+func (r *Registry) Services() []string {
+	var names []string
+	r.files.RangeFiles(func(f protoreflect.FileDescriptor) bool {
+		for i := 0; i < f.Services().Len(); i++ {
+			names = append(names, string(f.Services().Get(i).FullName()))
+		}
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+// Method finds a method by "pkg.Service/Method" or "pkg.Service.Method"
+func (r *Registry) Method(name string) (protoreflect.MethodDescriptor, error) {
+	name = strings.TrimPrefix(name, "/")
+	svcName, methodName, ok := SplitMethod(name)
+	if !ok {
+		return nil, fmt.Errorf("invalid method %q (want package.Service/Method)", name)
+	}
+	svc, err := r.Service(svcName)
+	if err != nil {
+		return nil, err
+	}
+	m := svc.Methods().ByName(protoreflect.Name(methodName))
+	if m == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", methodName, svcName)
+	}
+	return m, nil
+}
+
+// SplitMethod splits "pkg.Service/Method" or "pkg.Service.Method" into the
+// service and method names
+func SplitMethod(name string) (service, method string, ok bool) {
+	name = strings.TrimPrefix(name, "/")
+	i := strings.LastIndex(name, "/")
+	if i < 0 {
+		i = strings.LastIndex(name, ".")
+	}
+	if i <= 0 || i == len(name)-1 {
+		return "", "", false
+	}
+	return name[:i], name[i+1:], true
+}
+
+// FromJSON encodes a JSON object as a message of type md
+func (r *Registry) FromJSON(md protoreflect.MessageDescriptor, data []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(md)
+	if err := (protojson.UnmarshalOptions{Resolver: r.types}).Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(msg)
+}
+
+// ToJSON converts an encoded message of type md to compact JSON
+func (r *Registry) ToJSON(md protoreflect.MessageDescriptor, data []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(md)
+	if err := (proto.UnmarshalOptions{Resolver: r.types}).Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("invalid %s message: %w", md.FullName(), err)
+	}
+	out, err := (protojson.MarshalOptions{Resolver: r.types}).Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	// protojson varies its whitespace from build to build
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -6,7 +6,9 @@ import (
 	"os"
 	"strings"
 
+	"github.com/Priyans-hu/sreq/internal/jsonpath"
 	"github.com/Priyans-hu/sreq/internal/providers"
+	"github.com/Priyans-hu/sreq/internal/template"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
@@ -69,7 +71,7 @@ func (p *Provider) Name() string {
 }
 
 // Get retrieves a secret from AWS Secrets Manager
-// The key format is: secret-name or secret-name#json-key
+// The key format is: secret-name or secret-name#json-path
 func (p *Provider) Get(ctx context.Context, key string) (string, error) {
 	// Parse the key for JSON key extraction (secret-name#jsonkey)
 	secretName := key
@@ -102,7 +104,7 @@ func (p *Provider) Get(ctx context.Context, key string) (string, error) {
 	}
 
 	// Extract JSON key
-	extracted, err := extractJSONKey(secretValue, jsonKey)
+	extracted, err := jsonpath.Extract(secretValue, jsonKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to extract key '%s' from secret '%s': %w", jsonKey, secretName, err)
 	}
@@ -137,55 +139,11 @@ func (p *Provider) Health(ctx context.Context) error {
 	return nil
 }
 
-// extractJSONKey extracts a value from a JSON string
-// Handles simple cases like {"password": "secret", "username": "admin"}
-func extractJSONKey(jsonStr, key string) (string, error) {
-	// Look for "key": "value" or "key":"value"
-	searchKey := fmt.Sprintf(`"%s"`, key)
-	idx := strings.Index(jsonStr, searchKey)
-	if idx == -1 {
-		return "", fmt.Errorf("key '%s' not found in JSON", key)
-	}
-
-	// Find the colon after the key
-	rest := jsonStr[idx+len(searchKey):]
-	colonIdx := strings.Index(rest, ":")
-	if colonIdx == -1 {
-		return "", fmt.Errorf("invalid JSON format")
-	}
-
-	// Find the value
-	rest = strings.TrimSpace(rest[colonIdx+1:])
-
-	if len(rest) == 0 {
-		return "", fmt.Errorf("empty value for key '%s'", key)
-	}
-
-	// Handle string value
-	if rest[0] == '"' {
-		endIdx := strings.Index(rest[1:], `"`)
-		if endIdx == -1 {
-			return "", fmt.Errorf("unterminated string value")
-		}
-		return rest[1 : endIdx+1], nil
-	}
-
-	// Handle non-string value (number, bool, null)
-	endIdx := strings.IndexAny(rest, ",}")
-	if endIdx == -1 {
-		endIdx = len(rest)
-	}
-	return strings.TrimSpace(rest[:endIdx]), nil
-}
-
 // ResolvePath replaces placeholders in a path template
-// Placeholders: {service}, {env}, {region}, {project}, {app}
-func ResolvePath(template string, vars map[string]string) string {
-	result := template
-	for key, value := range vars {
-		result = strings.ReplaceAll(result, "{"+key+"}", value)
-	}
-	return result
+// Placeholders: {service}, {env}, {region}, {project}, {app}, with optional
+// modifiers such as {service|upper}. Missing values are an error.
+func ResolvePath(tmpl string, vars map[string]string) (string, error) {
+	return template.Render(tmpl, vars, template.Options{})
 }
 
 // Ensure Provider implements the interface
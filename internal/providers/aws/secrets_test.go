@@ -4,123 +4,13 @@ import (
 	"testing"
 )
 
-func TestExtractJSONKey(t *testing.T) {
+func TestResolvePath(t *testing.T) {
 	tests := []struct {
 		name      string
-		json      string
-		key       string
+		template  string
+		vars      map[string]string
 		expected  string
 		expectErr bool
-	}{
-		{
-			name:     "simple string value",
-			json:     `{"password": "secret123"}`,
-			key:      "password",
-			expected: "secret123",
-		},
-		{
-			name:     "multiple keys - get first",
-			json:     `{"username": "admin", "password": "secret123"}`,
-			key:      "username",
-			expected: "admin",
-		},
-		{
-			name:     "multiple keys - get second",
-			json:     `{"username": "admin", "password": "secret123"}`,
-			key:      "password",
-			expected: "secret123",
-		},
-		{
-			name:     "numeric value",
-			json:     `{"port": 5432}`,
-			key:      "port",
-			expected: "5432",
-		},
-		{
-			name:     "boolean true",
-			json:     `{"enabled": true}`,
-			key:      "enabled",
-			expected: "true",
-		},
-		{
-			name:     "boolean false",
-			json:     `{"debug": false}`,
-			key:      "debug",
-			expected: "false",
-		},
-		{
-			name:     "no spaces",
-			json:     `{"key":"value"}`,
-			key:      "key",
-			expected: "value",
-		},
-		{
-			name:     "extra whitespace",
-			json:     `{  "key"  :  "value"  }`,
-			key:      "key",
-			expected: "value",
-		},
-		{
-			name:      "key not found",
-			json:      `{"other": "value"}`,
-			key:       "password",
-			expectErr: true,
-		},
-		{
-			name:      "empty object",
-			json:      `{}`,
-			key:       "key",
-			expectErr: true,
-		},
-		{
-			name:     "complex password",
-			json:     `{"password": "P@$$w0rd!#%^&*()"}`,
-			key:      "password",
-			expected: "P@$$w0rd!#%^&*()",
-		},
-		{
-			name:     "url value",
-			json:     `{"url": "https://api.example.com/v1"}`,
-			key:      "url",
-			expected: "https://api.example.com/v1",
-		},
-		{
-			name:     "value with colon",
-			json:     `{"connection": "host:port"}`,
-			key:      "connection",
-			expected: "host:port",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := extractJSONKey(tt.json, tt.key)
-
-			if tt.expectErr {
-				if err == nil {
-					t.Errorf("expected error, got nil")
-				}
-				return
-			}
-
-			if err != nil {
-				t.Errorf("unexpected error: %v", err)
-				return
-			}
-
-			if result != tt.expected {
-				t.Errorf("got %q, want %q", result, tt.expected)
-			}
-		})
-	}
-}
-
-func TestResolvePath(t *testing.T) {
-	tests := []struct {
-		name     string
-		template string
-		vars     map[string]string
-		expected string
 	}{
 		{
 			name:     "service and env",
@@ -163,13 +53,22 @@ func TestResolvePath(t *testing.T) {
 			vars: map[string]string{
 				"service": "api",
 			},
-			expected: "api/{env}/secret",
+			expectErr: true,
 		},
 		{
-			name:     "empty vars",
-			template: "{service}/config",
-			vars:     map[string]string{},
-			expected: "{service}/config",
+			name:      "empty vars",
+			template:  "{service}/config",
+			vars:      map[string]string{},
+			expectErr: true,
+		},
+		{
+			name:     "json key suffix untouched",
+			template: "{service}/{env}/credentials#db.password",
+			vars: map[string]string{
+				"service": "api",
+				"env":     "dev",
+			},
+			expected: "api/dev/credentials#db.password",
 		},
 		{
 			name:     "nil vars",
@@ -190,94 +89,16 @@ func TestResolvePath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ResolvePath(tt.template, tt.vars)
-			if result != tt.expected {
-				t.Errorf("got %q, want %q", result, tt.expected)
-			}
-		})
-	}
-}
-
-func TestExtractJSONKey_EdgeCases(t *testing.T) {
-	tests := []struct {
-		name      string
-		json      string
-		key       string
-		expected  string
-		expectErr bool
-	}{
-		{
-			name:      "unterminated string",
-			json:      `{"key": "value`,
-			key:       "key",
-			expectErr: true,
-		},
-		{
-			name:      "no colon after key",
-			json:      `{"key" "value"}`,
-			key:       "key",
-			expectErr: true,
-		},
-		{
-			name:     "null value",
-			json:     `{"value": null}`,
-			key:      "value",
-			expected: "null",
-		},
-		{
-			name:     "negative number",
-			json:     `{"offset": -10}`,
-			key:      "offset",
-			expected: "-10",
-		},
-		{
-			name:     "float number",
-			json:     `{"rate": 0.5}`,
-			key:      "rate",
-			expected: "0.5",
-		},
-		{
-			name:     "value at end without comma",
-			json:     `{"last": "item"}`,
-			key:      "last",
-			expected: "item",
-		},
-		{
-			name:     "value in middle with comma",
-			json:     `{"first": "one", "second": "two"}`,
-			key:      "first",
-			expected: "one",
-		},
-		{
-			name:     "string with escaped quotes",
-			json:     `{"msg": "say \"hello\""}`,
-			key:      "msg",
-			expected: "say \\",
-		},
-		{
-			name:     "empty string value",
-			json:     `{"empty": ""}`,
-			key:      "empty",
-			expected: "",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := extractJSONKey(tt.json, tt.key)
-
+			result, err := ResolvePath(tt.template, tt.vars)
 			if tt.expectErr {
 				if err == nil {
-					t.Error("expected error, got nil")
+					t.Errorf("expected error, got %q", result)
 				}
 				return
 			}
-
 			if err != nil {
-				t.Errorf("unexpected error: %v", err)
-				return
+				t.Fatalf("unexpected error: %v", err)
 			}
-
 			if result != tt.expected {
 				t.Errorf("got %q, want %q", result, tt.expected)
 			}
@@ -9,6 +9,7 @@ import (
 
 	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
 	"github.com/Priyans-hu/sreq/internal/providers"
+	"github.com/Priyans-hu/sreq/internal/template"
 	"github.com/hashicorp/consul/api"
 )
 
@@ -168,8 +169,11 @@ func (p *Provider) GetMultiple(ctx context.Context, keys []string) (map[string]s
 
 // GetWithTemplate retrieves a value using a path template
 // Supports placeholders: {service}, {env}, {region}, {project}
-func (p *Provider) GetWithTemplate(ctx context.Context, template string, vars map[string]string) (string, error) {
-	key := ResolvePath(template, vars)
+func (p *Provider) GetWithTemplate(ctx context.Context, tmpl string, vars map[string]string) (string, error) {
+	key, err := ResolvePath(tmpl, vars)
+	if err != nil {
+		return "", err
+	}
 	return p.Get(ctx, key)
 }
 
@@ -266,18 +270,15 @@ func (p *Provider) queryOptions(ctx context.Context) *api.QueryOptions {
 }
 
 // ResolvePath replaces placeholders in a path template
-// Placeholders: {service}, {env}, {region}, {project}
-func ResolvePath(template string, vars map[string]string) string {
-	result := template
-	for key, value := range vars {
-		result = strings.ReplaceAll(result, "{"+key+"}", value)
-	}
-	return result
+// Placeholders: {service}, {env}, {region}, {project}, with optional
+// modifiers such as {service|upper}. Missing values are an error.
+func ResolvePath(tmpl string, vars map[string]string) (string, error) {
+	return template.Render(tmpl, vars, template.Options{})
 }
 
 // ResolvePathSimple is a convenience function for basic service/env resolution
-func ResolvePathSimple(template, service, env string) string {
-	return ResolvePath(template, map[string]string{
+func ResolvePathSimple(tmpl, service, env string) (string, error) {
+	return ResolvePath(tmpl, map[string]string{
 		"service": service,
 		"env":     env,
 	})
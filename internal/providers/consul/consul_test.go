@@ -10,10 +10,11 @@ import (
 
 func TestResolvePath(t *testing.T) {
 	tests := []struct {
-		name     string
-		template string
-		vars     map[string]string
-		expected string
+		name      string
+		template  string
+		vars      map[string]string
+		expected  string
+		expectErr bool
 	}{
 		{
 			name:     "single placeholder",
@@ -56,13 +57,19 @@ func TestResolvePath(t *testing.T) {
 				"project": "myapp",
 				"service": "auth",
 			},
-			expected: "myapp/{env}/auth/config",
+			expectErr: true,
 		},
 		{
-			name:     "empty vars",
-			template: "{service}/config",
-			vars:     map[string]string{},
-			expected: "{service}/config",
+			name:      "empty vars",
+			template:  "{service}/config",
+			vars:      map[string]string{},
+			expectErr: true,
+		},
+		{
+			name:     "modifier",
+			template: "{service|upper}/config",
+			vars:     map[string]string{"service": "auth"},
+			expected: "AUTH/config",
 		},
 		{
 			name:     "repeated placeholder",
@@ -77,7 +84,16 @@ func TestResolvePath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ResolvePath(tt.template, tt.vars)
+			result, err := ResolvePath(tt.template, tt.vars)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
 			if result != tt.expected {
 				t.Errorf("got %q, want %q", result, tt.expected)
 			}
@@ -247,7 +263,10 @@ func TestResolvePathSimple(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ResolvePathSimple(tt.template, tt.service, tt.env)
+			result, err := ResolvePathSimple(tt.template, tt.service, tt.env)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
 			if result != tt.expected {
 				t.Errorf("got %q, want %q", result, tt.expected)
 			}
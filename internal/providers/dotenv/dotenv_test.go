@@ -397,10 +397,11 @@ func TestProvider_Reload(t *testing.T) {
 
 func TestResolvePath(t *testing.T) {
 	tests := []struct {
-		name     string
-		template string
-		vars     map[string]string
-		expected string
+		name      string
+		template  string
+		vars      map[string]string
+		expected  string
+		expectErr bool
 	}{
 		{
 			name:     "single placeholder uppercase",
@@ -434,7 +435,16 @@ func TestResolvePath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ResolvePath(tt.template, tt.vars)
+			result, err := ResolvePath(tt.template, tt.vars)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
 			if result != tt.expected {
 				t.Errorf("ResolvePath() = %q, want %q", result, tt.expected)
 			}
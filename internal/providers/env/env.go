@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/Priyans-hu/sreq/internal/providers"
+	"github.com/Priyans-hu/sreq/internal/template"
 )
 
 // Provider implements the providers.Provider interface for environment variables
@@ -73,8 +74,11 @@ func (p *Provider) GetMultiple(ctx context.Context, keys []string) (map[string]s
 
 // GetWithTemplate retrieves a value using a path template
 // Supports placeholders: {service}, {env}, {region}, {project}
-func (p *Provider) GetWithTemplate(ctx context.Context, template string, vars map[string]string) (string, error) {
-	key := ResolvePath(template, vars)
+func (p *Provider) GetWithTemplate(ctx context.Context, tmpl string, vars map[string]string) (string, error) {
+	key, err := ResolvePath(tmpl, vars)
+	if err != nil {
+		return "", err
+	}
 	return p.Get(ctx, key)
 }
 
@@ -86,19 +90,13 @@ func (p *Provider) Health(ctx context.Context) error {
 
 // ResolvePath replaces placeholders in a path template
 // Placeholders: {service}, {env}, {region}, {project} (case-insensitive)
-// Also converts to uppercase and replaces hyphens/dots with underscores
-func ResolvePath(template string, vars map[string]string) string {
-	result := template
-	for key, value := range vars {
-		// Convert value to env-friendly format (uppercase, underscores)
-		envValue := strings.ToUpper(value)
-		envValue = strings.ReplaceAll(envValue, "-", "_")
-		envValue = strings.ReplaceAll(envValue, ".", "_")
-		// Replace both lowercase and uppercase placeholders
-		result = strings.ReplaceAll(result, "{"+key+"}", envValue)
-		result = strings.ReplaceAll(result, "{"+strings.ToUpper(key)+"}", envValue)
-	}
-	return result
+// Values are converted to uppercase with hyphens/dots replaced by underscores,
+// unless the placeholder sets its own modifiers (e.g. {service|lower})
+func ResolvePath(tmpl string, vars map[string]string) (string, error) {
+	return template.Render(tmpl, vars, template.Options{
+		DefaultModifiers: []string{"env"},
+		IgnoreCase:       true,
+	})
 }
 
 // Ensure Provider implements the interface
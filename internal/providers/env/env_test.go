@@ -138,10 +138,11 @@ func TestProvider_Health(t *testing.T) {
 
 func TestResolvePath(t *testing.T) {
 	tests := []struct {
-		name     string
-		template string
-		vars     map[string]string
-		expected string
+		name      string
+		template  string
+		vars      map[string]string
+		expected  string
+		expectErr bool
 	}{
 		{
 			name:     "single placeholder",
@@ -178,16 +179,31 @@ func TestResolvePath(t *testing.T) {
 			expected: "STATIC_API_KEY",
 		},
 		{
-			name:     "empty vars",
-			template: "{SERVICE}_KEY",
-			vars:     map[string]string{},
-			expected: "{SERVICE}_KEY",
+			name:      "empty vars",
+			template:  "{SERVICE}_KEY",
+			vars:      map[string]string{},
+			expectErr: true,
+		},
+		{
+			name:     "explicit modifier",
+			template: "{service|lower}_KEY",
+			vars:     map[string]string{"service": "Auth-Svc"},
+			expected: "auth-svc_KEY",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ResolvePath(tt.template, tt.vars)
+			result, err := ResolvePath(tt.template, tt.vars)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
 			if result != tt.expected {
 				t.Errorf("ResolvePath() = %q, want %q", result, tt.expected)
 			}
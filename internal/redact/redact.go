@@ -0,0 +1,244 @@
+// Package redact masks secrets in anything sreq prints or stores: verbose
+// output, errors, history entries, exports and TUI views.
+package redact
+
+import (
+	"encoding/base64"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// Mask replaces redacted values
+const Mask = "***REDACTED***"
+
+// minSecretLength avoids masking short values like "1" or "dev" everywhere
+const minSecretLength = 4
+
+// sensitiveHeaders are always redacted, whatever their value
+var sensitiveHeaders = map[string]bool{
+	"authorization":        true,
+	"proxy-authorization":  true,
+	"x-api-key":            true,
+	"x-auth-token":         true,
+	"x-amz-security-token": true,
+	"cookie":               true,
+	"set-cookie":           true,
+}
+
+// sensitiveNames are substrings marking a header, query parameter or custom
+// credential key as secret
+var sensitiveNames = []string{"token", "secret", "password", "passwd", "signature", "api_key", "apikey", "api-key", "private_key", "credential"}
+
+// IsSensitiveHeader reports whether a header's value should never be shown
+func IsSensitiveHeader(name string) bool {
+	lower := strings.ToLower(name)
+	return sensitiveHeaders[lower] || isSensitiveName(lower)
+}
+
+// isSensitiveName reports whether a key name suggests a secret value
+func isSensitiveName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, s := range sensitiveNames {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redactor masks known secret values. The zero value and nil only mask
+// sensitive header and query parameter names.
+type Redactor struct {
+	mu      sync.RWMutex
+	secrets []string // longest first, so overlapping secrets mask fully
+	reveal  bool
+}
+
+// New creates a redactor seeded with secret values
+func New(secrets ...string) *Redactor {
+	r := &Redactor{}
+	r.Add(secrets...)
+	return r
+}
+
+// ForCredentials creates a redactor seeded with every secret value resolved
+// for a request
+func ForCredentials(creds *types.ResolvedCredentials) *Redactor {
+	r := &Redactor{}
+	if creds == nil {
+		return r
+	}
+
+	r.Add(creds.Password, creds.APIKey)
+	if creds.Username != "" && creds.Password != "" {
+		r.Add(base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password)))
+	}
+	for _, v := range creds.Headers {
+		r.Add(v)
+	}
+	for k, v := range creds.Custom {
+		if isSensitiveName(k) {
+			r.Add(v)
+		}
+	}
+
+	if a := creds.Auth; a != nil {
+		r.Add(a.Token, a.Value, a.ClientSecret, a.RefreshToken, a.SecretAccessKey, a.SessionToken, a.Secret, a.PrivateKey)
+		if a.Type == types.AuthBasic || a.Type == types.AuthDigest {
+			r.Add(a.Password, base64.StdEncoding.EncodeToString([]byte(a.Username+":"+a.Password)))
+		}
+	}
+	if creds.TLS != nil {
+		r.Add(creds.TLS.KeyPEM)
+	}
+	if creds.Proxy != nil {
+		r.Add(creds.Proxy.Password)
+	}
+
+	return r
+}
+
+// SetReveal disables masking of secret values, for local debugging.
+// Sensitive names stay masked in history regardless, see Headers.
+func (r *Redactor) SetReveal(reveal bool) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reveal = reveal
+}
+
+// Revealing reports whether masking is disabled
+func (r *Redactor) Revealing() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.reveal
+}
+
+// Add registers more secret values, such as tokens obtained at request time
+func (r *Redactor) Add(secrets ...string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range secrets {
+		s = strings.TrimSpace(s)
+		if len(s) < minSecretLength {
+			continue
+		}
+		r.secrets = append(r.secrets, s)
+		// Multi-line secrets (PEM keys) are also masked line by line
+		if strings.Contains(s, "\n") {
+			for _, line := range strings.Split(s, "\n") {
+				if line = strings.TrimSpace(line); len(line) >= minSecretLength && !strings.HasPrefix(line, "-----") {
+					r.secrets = append(r.secrets, line)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+}
+
+// Secrets returns the registered secret values
+func (r *Redactor) Secrets() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.secrets...)
+}
+
+// String masks every known secret value in s
+func (r *Redactor) String(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.reveal {
+		return s
+	}
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+	return s
+}
+
+// Header masks a header value: sensitive header names are always masked,
+// other values have known secrets removed
+func (r *Redactor) Header(name, value string) string {
+	if IsSensitiveHeader(name) && !r.Revealing() {
+		return Mask
+	}
+	return r.String(value)
+}
+
+// Headers returns a copy of headers with values masked
+func (r *Redactor) Headers(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	result := make(map[string]string, len(headers))
+	for k, v := range headers {
+		result[k] = r.Header(k, v)
+	}
+	return result
+}
+
+// URL masks sensitive query parameters and known secrets in a URL or path
+func (r *Redactor) URL(raw string) string {
+	if r.Revealing() {
+		return raw
+	}
+
+	base, query, ok := strings.Cut(raw, "?")
+	if ok {
+		if values, err := url.ParseQuery(query); err == nil {
+			changed := false
+			for name := range values {
+				if isSensitiveName(name) || name == "key" {
+					values[name] = []string{Mask}
+					changed = true
+				}
+			}
+			if changed {
+				raw = base + "?" + values.Encode()
+			}
+		}
+	}
+	return r.String(raw)
+}
+
+// Error returns err with secrets masked from its message. The original
+// error stays reachable through errors.Is and errors.As.
+func (r *Redactor) Error(err error) error {
+	if err == nil || r == nil {
+		return err
+	}
+	msg := r.String(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{err: err, msg: msg}
+}
+
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
@@ -0,0 +1,318 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/jwt"
+	"github.com/Priyans-hu/sreq/internal/template"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// Default templates and names for auth schemes
+const (
+	defaultUsernameTemplate = "{username}"
+	defaultPasswordTemplate = "{password}"
+	defaultAPIKeyTemplate   = "{api_key}"
+	defaultAPIKeyHeader     = "X-API-Key"
+	defaultAPIKeyParam      = "api_key"
+
+	defaultClientIDTemplate     = "{client_id}"
+	defaultClientSecretTemplate = "{client_secret}"
+	defaultRefreshTokenTemplate = "{refresh_token}"
+
+	defaultHMACAlgorithm       = "sha256"
+	defaultHMACEncoding        = "hex"
+	defaultHMACCanonical       = "{method}\n{path}\n{timestamp}\n{body}"
+	defaultHMACSignatureHeader = "X-Signature"
+	defaultHMACSignatureFormat = "{signature}"
+	defaultHMACTimestampHeader = "X-Timestamp"
+	defaultHMACTimestampFormat = "unix"
+
+	defaultJWTPrivateKeyTemplate = "{private_key}"
+	defaultJWTExpiresIn          = 5 * time.Minute
+)
+
+// resolveAuth renders an auth block's templates against resolved credentials
+// and the request's template variables ({service}, {env}, {region}, ...)
+func resolveAuth(cfg *types.AuthConfig, creds *types.ResolvedCredentials, templateVars map[string]string) (*types.ResolvedAuth, error) {
+	vars := authVars(creds, templateVars)
+	auth := &types.ResolvedAuth{Type: cfg.Type}
+
+	render := func(field, tmpl, fallback string) (string, error) {
+		if tmpl == "" {
+			tmpl = fallback
+		}
+		value, err := template.Render(tmpl, vars, template.Options{})
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", field, err)
+		}
+		return value, nil
+	}
+
+	var err error
+	switch cfg.Type {
+	case types.AuthNone:
+		// Explicitly send no credentials
+
+	case types.AuthBasic, types.AuthDigest:
+		if auth.Username, err = render("username", cfg.Username, defaultUsernameTemplate); err != nil {
+			return nil, err
+		}
+		if auth.Password, err = render("password", cfg.Password, defaultPasswordTemplate); err != nil {
+			return nil, err
+		}
+
+	case types.AuthBearer:
+		if auth.Token, err = render("token", cfg.Token, defaultAPIKeyTemplate); err != nil {
+			return nil, err
+		}
+
+	case types.AuthAPIKey:
+		auth.In = cfg.In
+		if auth.In == "" {
+			auth.In = "header"
+		}
+
+		auth.Name = cfg.Name
+		switch auth.In {
+		case "header":
+			if auth.Name == "" {
+				auth.Name = defaultAPIKeyHeader
+			}
+		case "query":
+			if auth.Name == "" {
+				auth.Name = defaultAPIKeyParam
+			}
+		default:
+			return nil, fmt.Errorf("api_key 'in' must be 'header' or 'query', got '%s'", cfg.In)
+		}
+
+		if auth.Value, err = render("value", cfg.Value, defaultAPIKeyTemplate); err != nil {
+			return nil, err
+		}
+
+	case types.AuthOAuth2:
+		if auth.TokenURL, err = render("token_url", cfg.TokenURL, ""); err != nil {
+			return nil, err
+		}
+		if auth.TokenURL == "" {
+			return nil, fmt.Errorf("oauth2 requires token_url")
+		}
+
+		auth.GrantType = cfg.GrantType
+		if auth.GrantType == "" {
+			auth.GrantType = types.GrantClientCredentials
+		}
+
+		switch auth.GrantType {
+		case types.GrantClientCredentials:
+		case types.GrantRefreshToken:
+			if auth.RefreshToken, err = render("refresh_token", cfg.RefreshToken, defaultRefreshTokenTemplate); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("oauth2 grant_type must be '%s' or '%s', got '%s'",
+				types.GrantClientCredentials, types.GrantRefreshToken, cfg.GrantType)
+		}
+
+		if auth.ClientID, err = render("client_id", cfg.ClientID, defaultClientIDTemplate); err != nil {
+			return nil, err
+		}
+		// Public clients may use a refresh token without a secret
+		secret := cfg.ClientSecret
+		if secret == "" && (auth.GrantType == types.GrantClientCredentials || vars["client_secret"] != "") {
+			secret = defaultClientSecretTemplate
+		}
+		if auth.ClientSecret, err = render("client_secret", secret, ""); err != nil {
+			return nil, err
+		}
+
+		auth.AuthStyle = cfg.AuthStyle
+		switch auth.AuthStyle {
+		case "":
+			auth.AuthStyle = "body"
+		case "body", "header":
+		default:
+			return nil, fmt.Errorf("oauth2 auth_style must be 'body' or 'header', got '%s'", cfg.AuthStyle)
+		}
+
+		if auth.Audience, err = render("audience", cfg.Audience, ""); err != nil {
+			return nil, err
+		}
+		auth.Scopes = cfg.Scopes
+
+	case types.AuthSigV4:
+		if cfg.SigningService == "" {
+			return nil, fmt.Errorf("sigv4 requires service (e.g. execute-api, es)")
+		}
+		auth.SigningService = cfg.SigningService
+		auth.Profile = cfg.Profile
+
+		// Region falls back to the AWS profile's region at signing time
+		region := cfg.Region
+		if region == "" && vars["region"] != "" {
+			region = "{region}"
+		}
+		if auth.Region, err = render("region", region, ""); err != nil {
+			return nil, err
+		}
+		if auth.RoleARN, err = render("role_arn", cfg.RoleARN, ""); err != nil {
+			return nil, err
+		}
+
+		if cfg.AccessKeyID != "" {
+			if auth.AccessKeyID, err = render("access_key_id", cfg.AccessKeyID, ""); err != nil {
+				return nil, err
+			}
+			if auth.SecretAccessKey, err = render("secret_access_key", cfg.SecretAccessKey, ""); err != nil {
+				return nil, err
+			}
+			if auth.SecretAccessKey == "" {
+				return nil, fmt.Errorf("sigv4 access_key_id requires secret_access_key")
+			}
+			if auth.SessionToken, err = render("session_token", cfg.SessionToken, ""); err != nil {
+				return nil, err
+			}
+		}
+
+	case types.AuthHMAC:
+		if auth.Secret, err = render("secret", cfg.Secret, defaultAPIKeyTemplate); err != nil {
+			return nil, err
+		}
+		if auth.KeyID, err = render("key_id", cfg.KeyID, ""); err != nil {
+			return nil, err
+		}
+
+		auth.Algorithm = valueOr(strings.ToLower(cfg.Algorithm), defaultHMACAlgorithm)
+		if err := oneOf("algorithm", auth.Algorithm, "sha1", "sha256", "sha512"); err != nil {
+			return nil, err
+		}
+		auth.Encoding = valueOr(cfg.Encoding, defaultHMACEncoding)
+		if err := oneOf("encoding", auth.Encoding, "hex", "base64", "base64url"); err != nil {
+			return nil, err
+		}
+		auth.TimestampFormat = valueOr(cfg.TimestampFormat, defaultHMACTimestampFormat)
+		if err := oneOf("timestamp_format", auth.TimestampFormat, "unix", "unix_ms", "rfc3339"); err != nil {
+			return nil, err
+		}
+
+		auth.Canonical = valueOr(cfg.Canonical, defaultHMACCanonical)
+		auth.SignatureFormat = valueOr(cfg.SignatureFormat, defaultHMACSignatureFormat)
+		for field, tmpl := range map[string]string{"canonical": auth.Canonical, "signature_format": auth.SignatureFormat} {
+			if err := checkRequestTemplate(field, tmpl); err != nil {
+				return nil, err
+			}
+		}
+
+		auth.SignatureHeader = valueOr(cfg.SignatureHeader, defaultHMACSignatureHeader)
+		auth.TimestampHeader = valueOr(cfg.TimestampHeader, defaultHMACTimestampHeader)
+		auth.NonceHeader = cfg.NonceHeader
+
+	case types.AuthJWT:
+		auth.Algorithm = valueOr(strings.ToUpper(cfg.Algorithm), jwt.RS256)
+		if auth.KeyID, err = render("key_id", cfg.KeyID, ""); err != nil {
+			return nil, err
+		}
+
+		// Parse the key now so a bad key fails before any request
+		var key string
+		if auth.Algorithm == jwt.HS256 {
+			if auth.Secret, err = render("secret", cfg.Secret, defaultAPIKeyTemplate); err != nil {
+				return nil, err
+			}
+			key = auth.Secret
+		} else {
+			if auth.PrivateKey, err = render("private_key", cfg.PrivateKey, defaultJWTPrivateKeyTemplate); err != nil {
+				return nil, err
+			}
+			key = auth.PrivateKey
+		}
+		if _, err := jwt.NewSigner(auth.Algorithm, key, auth.KeyID); err != nil {
+			return nil, fmt.Errorf("jwt: %w", err)
+		}
+
+		auth.ExpiresIn = defaultJWTExpiresIn
+		if cfg.ExpiresIn != "" {
+			if auth.ExpiresIn, err = time.ParseDuration(cfg.ExpiresIn); err != nil || auth.ExpiresIn <= 0 {
+				return nil, fmt.Errorf("jwt expires_in must be a positive duration like 10m, got '%s'", cfg.ExpiresIn)
+			}
+		}
+
+		auth.Claims = make(map[string]string, len(cfg.Claims))
+		for name, tmpl := range cfg.Claims {
+			if name == "iat" || name == "exp" {
+				return nil, fmt.Errorf("jwt claim '%s' is set automatically (use expires_in)", name)
+			}
+			if auth.Claims[name], err = render("claims."+name, tmpl, ""); err != nil {
+				return nil, err
+			}
+		}
+
+	case "":
+		return nil, fmt.Errorf("auth type is required")
+
+	default:
+		return nil, fmt.Errorf("unknown auth type '%s'", cfg.Type)
+	}
+
+	return auth, nil
+}
+
+// authVars exposes resolved credential fields to auth templates.
+// Only non-empty fields are set, so a template referencing a field that
+// did not resolve is reported as unresolved instead of sending "".
+func authVars(creds *types.ResolvedCredentials, templateVars map[string]string) map[string]string {
+	vars := make(map[string]string, len(templateVars)+len(creds.Custom)+4)
+	for k, v := range templateVars {
+		vars[k] = v
+	}
+	for k, v := range creds.Custom {
+		vars[k] = v
+	}
+
+	fields := map[string]string{
+		"base_url": creds.BaseURL,
+		"username": creds.Username,
+		"password": creds.Password,
+		"api_key":  creds.APIKey,
+	}
+	for k, v := range fields {
+		if v != "" {
+			vars[k] = v
+		}
+	}
+	return vars
+}
+
+// checkRequestTemplate verifies that a per-request template only uses
+// placeholders the client provides
+func checkRequestTemplate(field, tmpl string) error {
+	vars := make(map[string]string, len(types.HMACRequestVars))
+	for _, name := range types.HMACRequestVars {
+		vars[name] = ""
+	}
+	if _, err := template.Render(tmpl, vars, template.Options{}); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	return nil
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// oneOf checks that value is one of the allowed options
+func oneOf(field, value string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must be one of %s, got '%s'", field, strings.Join(allowed, ", "), value)
+}
@@ -0,0 +1,76 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// proxySchemes are the proxy protocols the client's transport can speak
+var proxySchemes = map[string]bool{
+	"http":    true,
+	"https":   true,
+	"socks5":  true,
+	"socks5h": true,
+}
+
+// resolveProxy validates a proxy url and fetches its credentials
+func (r *Resolver) resolveProxy(ctx context.Context, service string, cfg *types.ProxyConfig, vars map[string]string) (*types.ResolvedProxy, error) {
+	if cfg.URL == types.ProxyDirect {
+		return &types.ResolvedProxy{URL: types.ProxyDirect}, nil
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil || cfg.URL == "" {
+		return nil, sreerrors.InvalidProxyConfig(service, fmt.Errorf("invalid url %q", cfg.URL))
+	}
+	if !proxySchemes[u.Scheme] || u.Host == "" {
+		return nil, sreerrors.InvalidProxyConfig(service, fmt.Errorf("unsupported proxy url %q", u.Redacted()))
+	}
+	if u.User != nil {
+		return nil, sreerrors.InvalidProxyConfig(service, fmt.Errorf("set proxy credentials with username and password, not in the url"))
+	}
+	if (cfg.Username == "") != (cfg.Password == "") {
+		return nil, sreerrors.InvalidProxyConfig(service, fmt.Errorf("username and password must be set together"))
+	}
+
+	resolved := &types.ResolvedProxy{
+		URL:     cfg.URL,
+		NoProxy: cfg.NoProxy,
+	}
+	if cfg.Username == "" {
+		return resolved, nil
+	}
+
+	specs := map[string]string{
+		"proxy.username": cfg.Username,
+		"proxy.password": cfg.Password,
+	}
+
+	// Validate both specs before making any provider call
+	plans := make(map[string]*pathPlan, len(specs))
+	for key, spec := range specs {
+		plan, err := r.planPath(key, spec, vars)
+		if err != nil {
+			return nil, err
+		}
+		plans[key] = plan
+	}
+
+	for key, plan := range plans {
+		value, err := r.resolvePath(ctx, plan)
+		if err != nil {
+			return nil, sreerrors.PathResolutionFailed(key, err)
+		}
+		if key == "proxy.username" {
+			resolved.Username = value
+		} else {
+			resolved.Password = value
+		}
+	}
+
+	return resolved, nil
+}
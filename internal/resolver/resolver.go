@@ -2,15 +2,20 @@ package resolver
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"maps"
 	"strings"
 
+	"github.com/Priyans-hu/sreq/internal/config"
 	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/internal/jsonpath"
 	"github.com/Priyans-hu/sreq/internal/providers"
 	"github.com/Priyans-hu/sreq/internal/providers/aws"
 	"github.com/Priyans-hu/sreq/internal/providers/consul"
 	"github.com/Priyans-hu/sreq/internal/providers/dotenv"
 	"github.com/Priyans-hu/sreq/internal/providers/env"
+	"github.com/Priyans-hu/sreq/internal/template"
+	"github.com/Priyans-hu/sreq/internal/transform"
 	"github.com/Priyans-hu/sreq/pkg/types"
 )
 
@@ -104,10 +109,10 @@ type ResolveOptions struct {
 
 // Resolve resolves credentials for a service with given options
 func (r *Resolver) Resolve(ctx context.Context, opts ResolveOptions) (*types.ResolvedCredentials, error) {
-	// Find service config
-	svcCfg, exists := r.config.Services[opts.Service]
-	if !exists {
-		return nil, sreerrors.ServiceNotFound(opts.Service)
+	// Find service config, with inheritance and env overrides applied
+	svcCfg, err := config.ResolveService(r.config, opts.Service, opts.Env)
+	if err != nil {
+		return nil, err
 	}
 
 	creds := &types.ResolvedCredentials{
@@ -135,15 +140,52 @@ func (r *Resolver) Resolve(ctx context.Context, opts ResolveOptions) (*types.Res
 
 	if svcCfg.IsAdvancedMode() {
 		// Advanced mode: use explicit path mappings
-		return r.resolveAdvanced(ctx, &svcCfg, vars, creds)
+		creds, err = r.resolveAdvanced(ctx, svcCfg, vars, creds)
+	} else {
+		// Simple mode: use path templates from provider config
+		creds, err = r.resolveSimple(ctx, svcCfg, vars, creds)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Render the auth scheme against the resolved fields
+	if svcCfg.Auth != nil {
+		creds.Auth, err = resolveAuth(svcCfg.Auth, creds, vars)
+		if err != nil {
+			return nil, sreerrors.InvalidAuthConfig(opts.Service, err)
+		}
+	}
+
+	// Fetch client certificates and CA for mutual TLS
+	if svcCfg.TLS != nil {
+		creds.TLS, err = r.resolveTLS(ctx, opts.Service, opts.Env, svcCfg.TLS, vars)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Simple mode: use path templates from provider config
-	return r.resolveSimple(ctx, &svcCfg, vars, creds)
+	// Resolve the proxy and its credentials
+	if proxyCfg := config.ProxyFor(r.config, svcCfg, opts.Env); proxyCfg != nil {
+		creds.Proxy, err = r.resolveProxy(ctx, opts.Service, proxyCfg, vars)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return creds, nil
 }
 
 // resolveSimple resolves credentials using simple mode (consul_key, aws_prefix)
 func (r *Resolver) resolveSimple(ctx context.Context, svc *types.ServiceConfig, vars map[string]string, creds *types.ResolvedCredentials) (*types.ResolvedCredentials, error) {
+	// {service} becomes the provider's prefix for path templates only;
+	// auth, TLS and proxy specs still render the service name
+	vars = maps.Clone(vars)
+
+	// Render every template before contacting any provider, so a missing
+	// placeholder is reported instead of being sent as a literal "{region}"
+	var consulPaths, awsPaths map[string]string
+
 	// Get Consul provider
 	consulProvider, hasConsul := r.providers["consul"]
 
@@ -154,30 +196,11 @@ func (r *Resolver) resolveSimple(ctx context.Context, svc *types.ServiceConfig,
 		// Add consul_key to vars for template resolution
 		vars["service"] = svc.ConsulKey
 
-		for key, template := range consulCfg.Paths {
-			// Replace placeholders
-			path := consul.ResolvePath(template, vars)
-
-			value, err := consulProvider.Get(ctx, path)
-			if err != nil {
-				// Log warning but continue - not all keys may exist
-				continue
-			}
-
-			// Map to credential fields
-			switch key {
-			case "base_url":
-				creds.BaseURL = value
-			case "username":
-				creds.Username = value
-			case "password":
-				creds.Password = value
-			case "api_key":
-				creds.APIKey = value
-			default:
-				creds.Custom[key] = value
-			}
+		paths, err := renderPaths(consulCfg.Paths, vars, consul.ResolvePath)
+		if err != nil {
+			return nil, err
 		}
+		consulPaths = paths
 	}
 
 	// Get AWS provider
@@ -193,43 +216,91 @@ func (r *Resolver) resolveSimple(ctx context.Context, svc *types.ServiceConfig,
 		// Add aws_prefix to vars for template resolution
 		vars["service"] = svc.AWSPrefix
 
-		for key, template := range awsCfg.Paths {
-			// Replace placeholders
-			path := aws.ResolvePath(template, vars)
+		paths, err := renderPaths(awsCfg.Paths, vars, aws.ResolvePath)
+		if err != nil {
+			return nil, err
+		}
+		awsPaths = paths
+	}
 
-			value, err := awsProvider.Get(ctx, path)
-			if err != nil {
-				// Log warning but continue - not all keys may exist
-				continue
-			}
+	for key, path := range consulPaths {
+		value, err := consulProvider.Get(ctx, path)
+		if err != nil {
+			// Log warning but continue - not all keys may exist
+			continue
+		}
 
-			// Map to credential fields (AWS typically provides password/api_key)
-			switch key {
-			case "base_url":
-				if creds.BaseURL == "" {
-					creds.BaseURL = value
-				}
-			case "username":
-				if creds.Username == "" {
-					creds.Username = value
-				}
-			case "password":
-				creds.Password = value
-			case "api_key":
-				creds.APIKey = value
-			default:
-				creds.Custom[key] = value
+		// Map to credential fields
+		switch key {
+		case "base_url":
+			creds.BaseURL = value
+		case "username":
+			creds.Username = value
+		case "password":
+			creds.Password = value
+		case "api_key":
+			creds.APIKey = value
+		default:
+			creds.Custom[key] = value
+		}
+	}
+
+	for key, path := range awsPaths {
+		value, err := awsProvider.Get(ctx, path)
+		if err != nil {
+			// Log warning but continue - not all keys may exist
+			continue
+		}
+
+		// Map to credential fields (AWS typically provides password/api_key)
+		switch key {
+		case "base_url":
+			if creds.BaseURL == "" {
+				creds.BaseURL = value
+			}
+		case "username":
+			if creds.Username == "" {
+				creds.Username = value
 			}
+		case "password":
+			creds.Password = value
+		case "api_key":
+			creds.APIKey = value
+		default:
+			creds.Custom[key] = value
 		}
 	}
 
 	return creds, nil
 }
 
+// renderPaths renders each path template with the given provider's resolver
+func renderPaths(templates map[string]string, vars map[string]string, render func(string, map[string]string) (string, error)) (map[string]string, error) {
+	paths := make(map[string]string, len(templates))
+	for key, tmpl := range templates {
+		path, err := render(tmpl, vars)
+		if err != nil {
+			return nil, sreerrors.InvalidPathTemplate(key, err)
+		}
+		paths[key] = path
+	}
+	return paths, nil
+}
+
 // resolveAdvanced resolves credentials using advanced mode (explicit paths)
 func (r *Resolver) resolveAdvanced(ctx context.Context, svc *types.ServiceConfig, vars map[string]string, creds *types.ResolvedCredentials) (*types.ResolvedCredentials, error) {
+	// Validate every path before making any provider call
+	plans := make(map[string]*pathPlan, len(svc.Paths))
 	for key, pathSpec := range svc.Paths {
-		value, err := r.resolvePath(ctx, pathSpec, vars)
+		plan, err := r.planPath(key, pathSpec, vars)
+		if err != nil {
+			return nil, err
+		}
+		plans[key] = plan
+	}
+
+	for key, plan := range plans {
+		value, err := r.resolvePath(ctx, plan)
 		if err != nil {
 			return nil, sreerrors.PathResolutionFailed(key, err)
 		}
@@ -252,13 +323,31 @@ func (r *Resolver) resolveAdvanced(ctx context.Context, svc *types.ServiceConfig
 	return creds, nil
 }
 
-// resolvePath resolves a single path specification
-// Format: [provider:]path[#jsonkey]
-func (r *Resolver) resolvePath(ctx context.Context, pathSpec string, vars map[string]string) (string, error) {
+// pathPlan is a validated path specification, ready to be fetched
+type pathPlan struct {
+	provider providers.Provider
+	path     string // Path with placeholders rendered
+	jsonKey  string
+	steps    []transform.Step
+}
+
+// planPath parses and validates a path specification without contacting
+// the provider: placeholders, transforms and the provider name are checked
+// Format: [provider:]path[#jsonkey][|transform[:arg]...]
+func (r *Resolver) planPath(key, pathSpec string, vars map[string]string) (*pathPlan, error) {
 	parsed := parsePath(pathSpec)
 
+	// Validate transforms up front so typos fail before any provider call
+	steps, err := transform.Parse(parsed.Transforms)
+	if err != nil {
+		return nil, sreerrors.InvalidTransform(pathSpec, err)
+	}
+
 	// Replace placeholders in path
-	path := consul.ResolvePath(parsed.Path, vars)
+	path, err := template.Render(parsed.Path, vars, template.Options{})
+	if err != nil {
+		return nil, sreerrors.InvalidPathTemplate(key, err)
+	}
 
 	// Get provider (default to consul)
 	providerName := parsed.Provider
@@ -268,20 +357,40 @@ func (r *Resolver) resolvePath(ctx context.Context, pathSpec string, vars map[st
 
 	provider, exists := r.providers[providerName]
 	if !exists {
-		return "", sreerrors.ProviderNotConfigured(providerName)
+		return nil, sreerrors.PathResolutionFailed(key, sreerrors.ProviderNotConfigured(providerName))
 	}
 
+	return &pathPlan{
+		provider: provider,
+		path:     path,
+		jsonKey:  parsed.JSONKey,
+		steps:    steps,
+	}, nil
+}
+
+// resolvePath fetches a planned path and applies JSON extraction and transforms
+func (r *Resolver) resolvePath(ctx context.Context, plan *pathPlan) (string, error) {
 	// Get value
-	value, err := provider.Get(ctx, path)
+	value, err := plan.provider.Get(ctx, plan.path)
 	if err != nil {
 		return "", err
 	}
 
 	// Extract JSON key if specified
-	if parsed.JSONKey != "" {
-		value, err = extractJSONKey(value, parsed.JSONKey)
+	if plan.jsonKey != "" {
+		value, err = jsonpath.Extract(value, plan.jsonKey)
+		if err != nil {
+			if errors.Is(err, jsonpath.ErrInvalidJSON) {
+				return "", sreerrors.JSONParseFailed(err)
+			}
+			return "", sreerrors.JSONKeyNotFound(plan.jsonKey, plan.path)
+		}
+	}
+
+	if len(plan.steps) > 0 {
+		value, err = transform.Apply(value, plan.steps)
 		if err != nil {
-			return "", sreerrors.JSONKeyNotFound(parsed.JSONKey, path)
+			return "", sreerrors.TransformFailed(plan.path, err)
 		}
 	}
 
@@ -290,20 +399,29 @@ func (r *Resolver) resolvePath(ctx context.Context, pathSpec string, vars map[st
 
 // PathSpec represents a parsed path specification
 type PathSpec struct {
-	Provider string // consul, aws, vault, env
-	Path     string // The actual path
-	JSONKey  string // Optional JSON key (after #)
+	Provider   string   // consul, aws, vault, env
+	Path       string   // The actual path
+	JSONKey    string   // Optional JSON path (after #): dotted keys, [n] indices or a /json/pointer
+	Transforms []string // Optional transforms (after |), applied in order
 }
 
 // parsePath parses a path specification
-// Format: [provider:]path[#jsonkey]
+// Format: [provider:]path[#jsonkey][|transform[:arg]...]
 // Examples:
 //   - "billing_service/invoice_url" -> consul:billing_service/invoice_url
 //   - "consul:services/auth/url" -> consul:services/auth/url
 //   - "aws:secrets/prod/db#password" -> aws:secrets/prod/db, key=password
+//   - "aws:secrets/prod/db#primary.hosts[0]" -> nested JSON path
+//   - "aws:svc/creds#token|b64dec|trim" -> key=token, transforms=[b64dec trim]
 func parsePath(spec string) PathSpec {
 	result := PathSpec{}
 
+	// Split off transform pipeline
+	if idx := pipelineIndex(spec); idx != -1 {
+		result.Transforms = strings.Split(spec[idx+1:], "|")
+		spec = spec[:idx]
+	}
+
 	// Check for JSON key
 	if idx := strings.LastIndex(spec, "#"); idx != -1 {
 		result.JSONKey = spec[idx+1:]
@@ -323,47 +441,30 @@ func parsePath(spec string) PathSpec {
 	return result
 }
 
-// extractJSONKey extracts a value from a JSON string
-func extractJSONKey(jsonStr, key string) (string, error) {
-	// Simple JSON extraction - for complex cases, use encoding/json
-	// This handles simple cases like {"password": "secret"}
-
-	// Look for "key": "value" or "key":"value"
-	searchKey := fmt.Sprintf(`"%s"`, key)
-	idx := strings.Index(jsonStr, searchKey)
-	if idx == -1 {
-		return "", fmt.Errorf("key '%s' not found in JSON", key)
-	}
-
-	// Find the colon after the key
-	rest := jsonStr[idx+len(searchKey):]
-	colonIdx := strings.Index(rest, ":")
-	if colonIdx == -1 {
-		return "", fmt.Errorf("invalid JSON format")
-	}
-
-	// Find the value
-	rest = strings.TrimSpace(rest[colonIdx+1:])
-
-	if len(rest) == 0 {
-		return "", fmt.Errorf("empty value for key '%s'", key)
-	}
-
-	// Handle string value
-	if rest[0] == '"' {
-		endIdx := strings.Index(rest[1:], `"`)
-		if endIdx == -1 {
-			return "", fmt.Errorf("unterminated string value")
+// pipelineIndex returns the index of the first '|' outside a {placeholder},
+// or -1. Pipes inside braces are placeholder modifiers ({service|upper}).
+func pipelineIndex(spec string) int {
+	depth := 0
+	for i := 0; i < len(spec); i++ {
+		switch spec[i] {
+		case '{':
+			if i+1 < len(spec) && spec[i+1] == '{' {
+				i++ // escaped literal brace
+				continue
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+		case '|':
+			if depth == 0 {
+				return i
+			}
 		}
-		return rest[1 : endIdx+1], nil
-	}
-
-	// Handle non-string value (number, bool, null)
-	endIdx := strings.IndexAny(rest, ",}")
-	if endIdx == -1 {
-		endIdx = len(rest)
 	}
-	return strings.TrimSpace(rest[:endIdx]), nil
+	return -1
 }
 
 // GetProvider returns a provider by name
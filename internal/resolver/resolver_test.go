@@ -3,7 +3,10 @@ package resolver
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Priyans-hu/sreq/internal/providers"
 	"github.com/Priyans-hu/sreq/pkg/types"
@@ -346,6 +349,35 @@ func TestParsePath(t *testing.T) {
 				JSONKey:  "",
 			},
 		},
+		{
+			name:  "path with transforms",
+			input: "aws:svc/creds#token|b64dec|trim",
+			expected: PathSpec{
+				Provider:   "aws",
+				Path:       "svc/creds",
+				JSONKey:    "token",
+				Transforms: []string{"b64dec", "trim"},
+			},
+		},
+		{
+			name:  "transform argument containing hash",
+			input: "consul:svc/host|suffix:#frag",
+			expected: PathSpec{
+				Provider:   "consul",
+				Path:       "svc/host",
+				Transforms: []string{"suffix:#frag"},
+			},
+		},
+		{
+			name:  "placeholder modifier is not a transform",
+			input: "aws:{service|upper}/{env}#key|trim",
+			expected: PathSpec{
+				Provider:   "aws",
+				Path:       "{service|upper}/{env}",
+				JSONKey:    "key",
+				Transforms: []string{"trim"},
+			},
+		},
 		{
 			name:  "path with nested json key",
 			input: "aws:myapp/prod/credentials#db.password",
@@ -370,99 +402,546 @@ func TestParsePath(t *testing.T) {
 			if result.JSONKey != tt.expected.JSONKey {
 				t.Errorf("JSONKey: got %q, want %q", result.JSONKey, tt.expected.JSONKey)
 			}
+			if strings.Join(result.Transforms, "|") != strings.Join(tt.expected.Transforms, "|") {
+				t.Errorf("Transforms: got %q, want %q", result.Transforms, tt.expected.Transforms)
+			}
 		})
 	}
 }
 
-func TestExtractJSONKey(t *testing.T) {
+func TestResolver_Resolve_WithNestedJSONPath(t *testing.T) {
+	cfg := &types.Config{
+		Providers: map[string]types.ProviderConfig{},
+		Services: map[string]types.ServiceConfig{
+			"db-service": {
+				Paths: map[string]string{
+					"password": "mock:secrets/db#db.primary.password",
+					"base_url": "mock:secrets/db#/hosts/1",
+					"pool":     "mock:secrets/db#pool",
+				},
+			},
+		},
+	}
+
+	r, _ := New(cfg)
+	r.providers["mock"] = &mockProvider{
+		name: "mock",
+		values: map[string]string{
+			"secrets/db": `{
+				"note": "\"password\": decoy",
+				"db": {"primary": {"password": "p@ss\"word"}},
+				"hosts": ["https://a.internal", "https://b.internal"],
+				"pool": {"max": 10, "idle": 2}
+			}`,
+		},
+	}
+
+	creds, err := r.Resolve(context.Background(), ResolveOptions{
+		Service: "db-service",
+		Env:     "dev",
+	})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if creds.Password != `p@ss"word` {
+		t.Errorf("Password = %q, want %q", creds.Password, `p@ss"word`)
+	}
+	if creds.BaseURL != "https://b.internal" {
+		t.Errorf("BaseURL = %q, want %q", creds.BaseURL, "https://b.internal")
+	}
+	if creds.Custom["pool"] != `{"idle":2,"max":10}` {
+		t.Errorf("Custom[pool] = %q, want %q", creds.Custom["pool"], `{"idle":2,"max":10}`)
+	}
+}
+
+func TestResolver_Resolve_JSONPathErrors(t *testing.T) {
 	tests := []struct {
-		name      string
-		json      string
-		key       string
-		expected  string
-		expectErr bool
+		name     string
+		path     string
+		value    string
+		contains string
 	}{
+		{"missing key", "mock:secrets/db#password", `{"username": "x"}`, "JSON key 'password' not found"},
+		{"invalid json", "mock:secrets/db#password", `not-json`, "Failed to parse JSON value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &types.Config{
+				Providers: map[string]types.ProviderConfig{},
+				Services: map[string]types.ServiceConfig{
+					"db-service": {Paths: map[string]string{"password": tt.path}},
+				},
+			}
+
+			r, _ := New(cfg)
+			r.providers["mock"] = &mockProvider{
+				name:   "mock",
+				values: map[string]string{"secrets/db": tt.value},
+			}
+
+			_, err := r.Resolve(context.Background(), ResolveOptions{Service: "db-service", Env: "dev"})
+			if err == nil {
+				t.Fatal("Resolve() should return error")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("error = %q, should contain %q", err.Error(), tt.contains)
+			}
+		})
+	}
+}
+
+func TestResolver_Resolve_WithTransforms(t *testing.T) {
+	cfg := &types.Config{
+		Providers: map[string]types.ProviderConfig{},
+		Services: map[string]types.ServiceConfig{
+			"api": {
+				Paths: map[string]string{
+					"base_url": "mock:api/host|prefix:https://|port:8443",
+					"api_key":  "mock:api/creds#token|b64dec|trim",
+				},
+			},
+		},
+	}
+
+	r, _ := New(cfg)
+	r.providers["mock"] = &mockProvider{
+		name: "mock",
+		values: map[string]string{
+			"api/host":  "api.internal",
+			"api/creds": `{"token": "IGtleS0xMjMK"}`,
+		},
+	}
+
+	creds, err := r.Resolve(context.Background(), ResolveOptions{Service: "api", Env: "dev"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if creds.BaseURL != "https://api.internal:8443" {
+		t.Errorf("BaseURL = %q, want %q", creds.BaseURL, "https://api.internal:8443")
+	}
+	if creds.APIKey != "key-123" {
+		t.Errorf("APIKey = %q, want %q", creds.APIKey, "key-123")
+	}
+}
+
+func TestResolver_Resolve_UnknownTransformFailsBeforeProviderCall(t *testing.T) {
+	cfg := &types.Config{
+		Providers: map[string]types.ProviderConfig{},
+		Services: map[string]types.ServiceConfig{
+			"api": {
+				Paths: map[string]string{"api_key": "nonexistent:api/key|rot13"},
+			},
+		},
+	}
+
+	r, _ := New(cfg)
+	_, err := r.Resolve(context.Background(), ResolveOptions{Service: "api", Env: "dev"})
+	if err == nil {
+		t.Fatal("Resolve() should return error for unknown transform")
+	}
+	if !strings.Contains(err.Error(), "unknown transform 'rot13'") {
+		t.Errorf("error = %q, should report the unknown transform", err.Error())
+	}
+}
+
+// countingProvider records how many times Get was called
+type countingProvider struct {
+	mockProvider
+	calls int
+}
+
+func (c *countingProvider) Get(ctx context.Context, key string) (string, error) {
+	c.calls++
+	return c.mockProvider.Get(ctx, key)
+}
+
+func TestResolver_Resolve_UnresolvedPlaceholder_AdvancedMode(t *testing.T) {
+	cfg := &types.Config{
+		Providers: map[string]types.ProviderConfig{},
+		Services: map[string]types.ServiceConfig{
+			"api": {
+				Paths: map[string]string{
+					"base_url": "mock:api/{env}/url",
+					"password": "mock:api/{region}/{project}/password",
+				},
+			},
+		},
+	}
+
+	r, _ := New(cfg)
+	mock := &countingProvider{mockProvider: mockProvider{name: "mock", values: map[string]string{
+		"api/dev/url": "https://api.dev",
+	}}}
+	r.providers["mock"] = mock
+
+	_, err := r.Resolve(context.Background(), ResolveOptions{Service: "api", Env: "dev"})
+	if err == nil {
+		t.Fatal("Resolve() should fail on unresolved placeholders")
+	}
+	if !strings.Contains(err.Error(), "{region}, {project}") {
+		t.Errorf("error = %q, should list missing placeholders", err.Error())
+	}
+	if mock.calls != 0 {
+		t.Errorf("provider called %d times, want 0", mock.calls)
+	}
+}
+
+func TestResolver_Resolve_PlaceholderModifiers(t *testing.T) {
+	cfg := &types.Config{
+		Providers: map[string]types.ProviderConfig{},
+		Services: map[string]types.ServiceConfig{
+			"billing-api": {
+				Paths: map[string]string{
+					"base_url": "mock:{service|snake}/{env|upper}/{{url}}",
+				},
+			},
+		},
+	}
+
+	r, _ := New(cfg)
+	r.providers["mock"] = &mockProvider{name: "mock", values: map[string]string{
+		"billing_api/PROD/{url}": "https://billing.prod",
+	}}
+
+	creds, err := r.Resolve(context.Background(), ResolveOptions{Service: "billing-api", Env: "prod"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if creds.BaseURL != "https://billing.prod" {
+		t.Errorf("BaseURL = %q, want %q", creds.BaseURL, "https://billing.prod")
+	}
+}
+
+func TestResolver_Resolve_UnresolvedPlaceholder_SimpleMode(t *testing.T) {
+	cfg := &types.Config{
+		Providers: map[string]types.ProviderConfig{
+			"consul": {
+				Paths: map[string]string{
+					"base_url": "{service}/{region}/url",
+				},
+			},
+		},
+		Services: map[string]types.ServiceConfig{
+			"auth": {ConsulKey: "auth"},
+		},
+	}
+
+	r := &Resolver{config: cfg, providers: map[string]providers.Provider{}}
+	mock := &countingProvider{mockProvider: mockProvider{name: "consul", values: map[string]string{}}}
+	r.providers["consul"] = mock
+
+	_, err := r.Resolve(context.Background(), ResolveOptions{Service: "auth", Env: "dev"})
+	if err == nil {
+		t.Fatal("Resolve() should fail on unresolved placeholders")
+	}
+	if !strings.Contains(err.Error(), "{region}") {
+		t.Errorf("error = %q, should mention {region}", err.Error())
+	}
+	if mock.calls != 0 {
+		t.Errorf("provider called %d times, want 0", mock.calls)
+	}
+}
+
+func TestResolver_Resolve_ExtendsAndEnvOverrides(t *testing.T) {
+	cfg := &types.Config{
+		Providers: map[string]types.ProviderConfig{},
+		Services: map[string]types.ServiceConfig{
+			"base": {
+				Paths: map[string]string{
+					"base_url": "mock:shared/{env}/url",
+					"password": "mock:shared/password",
+				},
+			},
+			"orders": {
+				Extends: "base",
+				Envs: map[string]types.ServiceEnvConfig{
+					"prod": {Paths: map[string]string{"password": "mock:orders/prod/password"}},
+				},
+			},
+		},
+	}
+
+	r, _ := New(cfg)
+	r.providers["mock"] = &mockProvider{name: "mock", values: map[string]string{
+		"shared/dev/url":       "https://dev.internal",
+		"shared/prod/url":      "https://prod.internal",
+		"shared/password":      "shared-secret",
+		"orders/prod/password": "prod-secret",
+	}}
+
+	tests := []struct {
+		env      string
+		baseURL  string
+		password string
+	}{
+		{"dev", "https://dev.internal", "shared-secret"},
+		{"prod", "https://prod.internal", "prod-secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			creds, err := r.Resolve(context.Background(), ResolveOptions{Service: "orders", Env: tt.env})
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if creds.BaseURL != tt.baseURL {
+				t.Errorf("BaseURL = %q, want %q", creds.BaseURL, tt.baseURL)
+			}
+			if creds.Password != tt.password {
+				t.Errorf("Password = %q, want %q", creds.Password, tt.password)
+			}
+		})
+	}
+}
+
+func TestResolver_Resolve_Auth(t *testing.T) {
+	paths := map[string]string{
+		"base_url": "mock:svc/url",
+		"api_key":  "mock:svc/key",
+		"tenant":   "mock:svc/tenant",
+	}
+	values := map[string]string{
+		"svc/url":    "https://api.internal",
+		"svc/key":    "k-123",
+		"svc/tenant": "acme",
+	}
+
+	tests := []struct {
+		name    string
+		auth    *types.AuthConfig
+		want    *types.ResolvedAuth
+		wantErr bool
+	}{
+		{
+			name: "no auth block",
+			auth: nil,
+			want: nil,
+		},
+		{
+			name: "api key header defaults",
+			auth: &types.AuthConfig{Type: types.AuthAPIKey},
+			want: &types.ResolvedAuth{Type: types.AuthAPIKey, In: "header", Name: "X-API-Key", Value: "k-123"},
+		},
+		{
+			name: "api key query with template",
+			auth: &types.AuthConfig{Type: types.AuthAPIKey, In: "query", Value: "{tenant}:{api_key}"},
+			want: &types.ResolvedAuth{Type: types.AuthAPIKey, In: "query", Name: "api_key", Value: "acme:k-123"},
+		},
+		{
+			name: "bearer defaults to api key",
+			auth: &types.AuthConfig{Type: types.AuthBearer},
+			want: &types.ResolvedAuth{Type: types.AuthBearer, Token: "k-123"},
+		},
+		{
+			name: "basic with templates",
+			auth: &types.AuthConfig{Type: types.AuthBasic, Username: "{tenant}", Password: "{api_key}"},
+			want: &types.ResolvedAuth{Type: types.AuthBasic, Username: "acme", Password: "k-123"},
+		},
+		{
+			name:    "basic without resolved username",
+			auth:    &types.AuthConfig{Type: types.AuthBasic},
+			wantErr: true,
+		},
+		{
+			name: "oauth2 client credentials",
+			auth: &types.AuthConfig{
+				Type:         types.AuthOAuth2,
+				TokenURL:     "https://idp/{tenant}/token",
+				ClientID:     "{tenant}",
+				ClientSecret: "{api_key}",
+				Scopes:       []string{"orders.read"},
+			},
+			want: &types.ResolvedAuth{
+				Type:         types.AuthOAuth2,
+				TokenURL:     "https://idp/acme/token",
+				GrantType:    types.GrantClientCredentials,
+				ClientID:     "acme",
+				ClientSecret: "k-123",
+				Scopes:       []string{"orders.read"},
+				AuthStyle:    "body",
+			},
+		},
+		{
+			name:    "oauth2 without token url",
+			auth:    &types.AuthConfig{Type: types.AuthOAuth2, ClientID: "x", ClientSecret: "y"},
+			wantErr: true,
+		},
+		{
+			name:    "oauth2 unknown grant",
+			auth:    &types.AuthConfig{Type: types.AuthOAuth2, TokenURL: "https://idp", GrantType: "password"},
+			wantErr: true,
+		},
+		{
+			name: "sigv4 static keys",
+			auth: &types.AuthConfig{
+				Type:            types.AuthSigV4,
+				SigningService:  "execute-api",
+				Region:          "eu-west-1",
+				AccessKeyID:     "{tenant}",
+				SecretAccessKey: "{api_key}",
+			},
+			want: &types.ResolvedAuth{
+				Type:            types.AuthSigV4,
+				SigningService:  "execute-api",
+				Region:          "eu-west-1",
+				AccessKeyID:     "acme",
+				SecretAccessKey: "k-123",
+			},
+		},
+		{
+			name: "sigv4 profile with role",
+			auth: &types.AuthConfig{
+				Type:           types.AuthSigV4,
+				SigningService: "es",
+				Profile:        "search",
+				RoleARN:        "arn:aws:iam::123:role/{env}-search",
+			},
+			want: &types.ResolvedAuth{
+				Type:           types.AuthSigV4,
+				SigningService: "es",
+				Profile:        "search",
+				RoleARN:        "arn:aws:iam::123:role/dev-search",
+			},
+		},
 		{
-			name:     "simple string value",
-			json:     `{"password": "secret123"}`,
-			key:      "password",
-			expected: "secret123",
+			name:    "sigv4 without service",
+			auth:    &types.AuthConfig{Type: types.AuthSigV4, Region: "us-east-1"},
+			wantErr: true,
 		},
 		{
-			name:     "multiple keys",
-			json:     `{"username": "admin", "password": "secret123"}`,
-			key:      "password",
-			expected: "secret123",
+			name: "hmac defaults",
+			auth: &types.AuthConfig{Type: types.AuthHMAC, KeyID: "{tenant}"},
+			want: &types.ResolvedAuth{
+				Type:            types.AuthHMAC,
+				Secret:          "k-123",
+				KeyID:           "acme",
+				Algorithm:       "sha256",
+				Encoding:        "hex",
+				Canonical:       "{method}\n{path}\n{timestamp}\n{body}",
+				SignatureHeader: "X-Signature",
+				SignatureFormat: "{signature}",
+				TimestampHeader: "X-Timestamp",
+				TimestampFormat: "unix",
+			},
 		},
 		{
-			name:     "first key",
-			json:     `{"username": "admin", "password": "secret123"}`,
-			key:      "username",
-			expected: "admin",
+			name:    "hmac unknown canonical placeholder",
+			auth:    &types.AuthConfig{Type: types.AuthHMAC, Canonical: "{method}:{date}"},
+			wantErr: true,
 		},
 		{
-			name:     "numeric value",
-			json:     `{"port": 5432, "host": "localhost"}`,
-			key:      "port",
-			expected: "5432",
+			name:    "hmac unknown algorithm",
+			auth:    &types.AuthConfig{Type: types.AuthHMAC, Algorithm: "md5"},
+			wantErr: true,
 		},
 		{
-			name:     "boolean value",
-			json:     `{"enabled": true, "debug": false}`,
-			key:      "enabled",
-			expected: "true",
+			name: "jwt HS256",
+			auth: &types.AuthConfig{
+				Type:      types.AuthJWT,
+				Algorithm: "hs256",
+				ExpiresIn: "10m",
+				Claims:    map[string]string{"iss": "sreq", "tenant": "{tenant}"},
+			},
+			want: &types.ResolvedAuth{
+				Type:      types.AuthJWT,
+				Algorithm: "HS256",
+				Secret:    "k-123",
+				ExpiresIn: 10 * time.Minute,
+				Claims:    map[string]string{"iss": "sreq", "tenant": "acme"},
+			},
 		},
 		{
-			name:     "null value",
-			json:     `{"value": null}`,
-			key:      "value",
-			expected: "null",
+			name:    "jwt RS256 with invalid key",
+			auth:    &types.AuthConfig{Type: types.AuthJWT, PrivateKey: "{api_key}"},
+			wantErr: true,
 		},
 		{
-			name:     "no spaces around colon",
-			json:     `{"password":"secret123"}`,
-			key:      "password",
-			expected: "secret123",
+			name:    "jwt reserved claim",
+			auth:    &types.AuthConfig{Type: types.AuthJWT, Algorithm: "HS256", Claims: map[string]string{"exp": "1"}},
+			wantErr: true,
 		},
 		{
-			name:      "key not found",
-			json:      `{"username": "admin"}`,
-			key:       "password",
-			expectErr: true,
+			name:    "jwt bad expires_in",
+			auth:    &types.AuthConfig{Type: types.AuthJWT, Algorithm: "HS256", ExpiresIn: "soon"},
+			wantErr: true,
 		},
 		{
-			name:      "empty json",
-			json:      `{}`,
-			key:       "password",
-			expectErr: true,
+			name:    "unknown type",
+			auth:    &types.AuthConfig{Type: "oauth"},
+			wantErr: true,
 		},
 		{
-			name:     "value with special characters",
-			json:     `{"password": "p@ss!word#123"}`,
-			key:      "password",
-			expected: "p@ss!word#123",
+			name:    "invalid api key location",
+			auth:    &types.AuthConfig{Type: types.AuthAPIKey, In: "cookie"},
+			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := extractJSONKey(tt.json, tt.key)
+			cfg := &types.Config{
+				Providers: map[string]types.ProviderConfig{},
+				Services: map[string]types.ServiceConfig{
+					"svc": {Paths: paths, Auth: tt.auth},
+				},
+			}
+			r, _ := New(cfg)
+			r.providers["mock"] = &mockProvider{name: "mock", values: values}
 
-			if tt.expectErr {
+			creds, err := r.Resolve(context.Background(), ResolveOptions{Service: "svc", Env: "dev"})
+			if tt.wantErr {
 				if err == nil {
-					t.Errorf("expected error, got nil")
+					t.Fatalf("expected error, got auth %+v", creds.Auth)
 				}
 				return
 			}
-
 			if err != nil {
-				t.Errorf("unexpected error: %v", err)
-				return
+				t.Fatalf("Resolve() error = %v", err)
 			}
 
-			if result != tt.expected {
-				t.Errorf("got %q, want %q", result, tt.expected)
+			if tt.want == nil {
+				if creds.Auth != nil {
+					t.Errorf("Auth = %+v, want nil", creds.Auth)
+				}
+				return
+			}
+			if !reflect.DeepEqual(creds.Auth, tt.want) {
+				t.Errorf("Auth = %+v, want %+v", creds.Auth, tt.want)
 			}
 		})
 	}
 }
+
+func TestResolver_Resolve_SimpleModeKeepsServiceVar(t *testing.T) {
+	cfg := &types.Config{
+		Providers: map[string]types.ProviderConfig{
+			"aws": {Paths: map[string]string{"api_key": "{service}/{env}/api_key"}},
+		},
+		Services: map[string]types.ServiceConfig{
+			"billing": {
+				AWSPrefix: "billing-svc",
+				Auth:      &types.AuthConfig{Type: types.AuthAPIKey, Name: "X-Client", Value: "{service}:{api_key}"},
+			},
+		},
+	}
+
+	r, _ := New(cfg)
+	r.providers["aws"] = &mockProvider{
+		name:   "aws",
+		values: map[string]string{"billing-svc/dev/api_key": "k-123"},
+	}
+
+	creds, err := r.Resolve(context.Background(), ResolveOptions{Service: "billing", Env: "dev"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if creds.APIKey != "k-123" {
+		t.Errorf("APIKey = %q, want the value at the aws_prefix path", creds.APIKey)
+	}
+	if creds.Auth == nil || creds.Auth.Value != "billing:k-123" {
+		t.Errorf("Auth = %+v, want {service} rendered as the service name", creds.Auth)
+	}
+}
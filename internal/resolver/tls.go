@@ -0,0 +1,69 @@
+package resolver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	sreerrors "github.com/Priyans-hu/sreq/internal/errors"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// resolveTLS fetches the PEM material for a service's tls block and
+// checks that it parses, so a bad certificate fails before the request
+func (r *Resolver) resolveTLS(ctx context.Context, service, env string, cfg *types.TLSConfig, vars map[string]string) (*types.ResolvedTLS, error) {
+	if cfg.InsecureSkipVerify && r.config.IsProtectedEnv(env) {
+		return nil, sreerrors.InsecureTLSNotAllowed(env)
+	}
+	if (cfg.Cert == "") != (cfg.Key == "") {
+		return nil, sreerrors.InvalidTLSConfig(service, fmt.Errorf("cert and key must be set together"))
+	}
+
+	specs := map[string]string{
+		"tls.cert": cfg.Cert,
+		"tls.key":  cfg.Key,
+		"tls.ca":   cfg.CA,
+	}
+
+	// Validate every spec before making any provider call
+	plans := make(map[string]*pathPlan, len(specs))
+	for key, spec := range specs {
+		if spec == "" {
+			continue
+		}
+		plan, err := r.planPath(key, spec, vars)
+		if err != nil {
+			return nil, err
+		}
+		plans[key] = plan
+	}
+
+	values := make(map[string]string, len(plans))
+	for key, plan := range plans {
+		value, err := r.resolvePath(ctx, plan)
+		if err != nil {
+			return nil, sreerrors.PathResolutionFailed(key, err)
+		}
+		values[key] = value
+	}
+
+	resolved := &types.ResolvedTLS{
+		CertPEM:            values["tls.cert"],
+		KeyPEM:             values["tls.key"],
+		CAPEM:              values["tls.ca"],
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if resolved.CertPEM != "" {
+		if _, err := tls.X509KeyPair([]byte(resolved.CertPEM), []byte(resolved.KeyPEM)); err != nil {
+			return nil, sreerrors.InvalidTLSConfig(service, err)
+		}
+	}
+	if resolved.CAPEM != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(resolved.CAPEM)) {
+		return nil, sreerrors.InvalidTLSConfig(service, fmt.Errorf("ca contains no PEM certificates"))
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,139 @@
+package resolver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// testCertPEM generates a self-signed certificate and key
+func testCertPEM(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestResolver_Resolve_TLS(t *testing.T) {
+	certPEM, keyPEM := testCertPEM(t)
+	bundle, _ := json.Marshal(map[string]string{"cert": certPEM, "key": keyPEM})
+
+	values := map[string]string{
+		"partner/url":     "https://partner.internal",
+		"partner/dev/tls": string(bundle),
+		"shared/ca":       certPEM,
+	}
+
+	tests := []struct {
+		name    string
+		env     string
+		tls     *types.TLSConfig
+		wantErr bool
+	}{
+		{
+			name: "cert key and ca",
+			env:  "dev",
+			tls: &types.TLSConfig{
+				Cert: "mock:partner/{env}/tls#cert",
+				Key:  "mock:partner/{env}/tls#key",
+				CA:   "mock:shared/ca",
+			},
+		},
+		{
+			name: "ca only",
+			env:  "dev",
+			tls:  &types.TLSConfig{CA: "mock:shared/ca"},
+		},
+		{
+			name: "insecure in dev",
+			env:  "dev",
+			tls:  &types.TLSConfig{InsecureSkipVerify: true},
+		},
+		{
+			name:    "insecure in prod",
+			env:     "prod",
+			tls:     &types.TLSConfig{InsecureSkipVerify: true},
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			env:     "dev",
+			tls:     &types.TLSConfig{Cert: "mock:partner/{env}/tls#cert"},
+			wantErr: true,
+		},
+		{
+			name:    "key does not match",
+			env:     "dev",
+			tls:     &types.TLSConfig{Cert: "mock:partner/{env}/tls#cert", Key: "mock:shared/ca"},
+			wantErr: true,
+		},
+		{
+			name:    "ca is not pem",
+			env:     "dev",
+			tls:     &types.TLSConfig{CA: "mock:partner/url"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &types.Config{
+				Providers: map[string]types.ProviderConfig{},
+				Services: map[string]types.ServiceConfig{
+					"partner": {
+						Paths: map[string]string{"base_url": "mock:partner/url"},
+						TLS:   tt.tls,
+					},
+				},
+			}
+			r, _ := New(cfg)
+			r.providers["mock"] = &mockProvider{name: "mock", values: values}
+
+			creds, err := r.Resolve(context.Background(), ResolveOptions{Service: "partner", Env: tt.env})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if creds.TLS == nil {
+				t.Fatal("TLS should be resolved")
+			}
+			if tt.tls.Cert != "" && (creds.TLS.CertPEM != certPEM || creds.TLS.KeyPEM != keyPEM) {
+				t.Error("cert/key PEM not resolved")
+			}
+			if creds.TLS.InsecureSkipVerify != tt.tls.InsecureSkipVerify {
+				t.Errorf("InsecureSkipVerify = %v, want %v", creds.TLS.InsecureSkipVerify, tt.tls.InsecureSkipVerify)
+			}
+		})
+	}
+}
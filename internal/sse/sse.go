@@ -0,0 +1,87 @@
+// Package sse parses Server-Sent Events streams (text/event-stream).
+package sse
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxLineSize bounds a single line of the stream
+const maxLineSize = 1 << 20
+
+// Event is one dispatched server-sent event
+type Event struct {
+	ID    string
+	Event string // empty means "message"
+	Data  string
+	Retry int // reconnection time in milliseconds, 0 if not sent
+}
+
+// Reader reads events from a stream
+type Reader struct {
+	scanner *bufio.Scanner
+	first   bool
+}
+
+// NewReader creates a reader over an event stream
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineSize)
+	return &Reader{scanner: scanner, first: true}
+}
+
+// Next returns the next event. It returns io.EOF when the stream ends;
+// a trailing event without a blank line is discarded, as browsers do.
+func (r *Reader) Next() (*Event, error) {
+	var ev Event
+	var data []string
+	hasData := false
+
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if r.first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			r.first = false
+		}
+
+		// A blank line dispatches the event, if it carried any data
+		if line == "" {
+			if hasData {
+				ev.Data = strings.Join(data, "\n")
+				return &ev, nil
+			}
+			ev = Event{}
+			continue
+		}
+		// Comments keep connections alive
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "data":
+			data = append(data, value)
+			hasData = true
+		case "event":
+			ev.Event = value
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				ev.ID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				ev.Retry = ms
+			}
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
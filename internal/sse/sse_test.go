@@ -0,0 +1,51 @@
+package sse
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReader_Next(t *testing.T) {
+	stream := "\ufeff: keep-alive\n\n" +
+		"event: update\nid: 1\ndata: {\"n\":1}\n\n" +
+		"data: line one\ndata:line two\nretry: 3000\n\n" +
+		"event: ignored-without-data\n\n" +
+		"data: unterminated"
+
+	r := NewReader(strings.NewReader(stream))
+
+	var events []Event
+	for {
+		ev, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		events = append(events, *ev)
+	}
+
+	want := []Event{
+		{ID: "1", Event: "update", Data: `{"n":1}`},
+		{Data: "line one\nline two", Retry: 3000},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %+v, want %+v", events, want)
+	}
+}
+
+func TestReader_CRLF(t *testing.T) {
+	r := NewReader(strings.NewReader("data: hello\r\n\r\n"))
+
+	ev, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if ev.Data != "hello" {
+		t.Errorf("Data = %q, want %q", ev.Data, "hello")
+	}
+}
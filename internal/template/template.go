@@ -0,0 +1,196 @@
+package template
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Options controls how placeholders are rendered
+type Options struct {
+	// DefaultModifiers are applied to placeholders that have no explicit
+	// modifiers (e.g. ["env"] turns {service} into AUTH_SERVICE)
+	DefaultModifiers []string
+
+	// IgnoreCase matches placeholder names case-insensitively, so {SERVICE}
+	// and {service} both resolve from vars["service"]
+	IgnoreCase bool
+}
+
+// UnresolvedError reports placeholders that had no value
+type UnresolvedError struct {
+	Template string
+	Names    []string
+}
+
+func (e *UnresolvedError) Error() string {
+	placeholders := make([]string, len(e.Names))
+	for i, name := range e.Names {
+		placeholders[i] = "{" + name + "}"
+	}
+	return fmt.Sprintf("unresolved placeholders in '%s': %s", e.Template, strings.Join(placeholders, ", "))
+}
+
+// Modifier converts a placeholder value
+type Modifier func(string) string
+
+var modifiers = map[string]Modifier{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"snake": func(s string) string { return strings.ToLower(separate(s, '_')) },
+	"kebab": func(s string) string { return strings.ToLower(separate(s, '-')) },
+	"env":   func(s string) string { return strings.ToUpper(separate(s, '_')) },
+}
+
+// ModifierNames returns the supported modifier names, sorted
+func ModifierNames() []string {
+	names := make([]string, 0, len(modifiers))
+	for name := range modifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render replaces {name} placeholders in tmpl with values from vars.
+//
+// Syntax:
+//   - {name}              value of vars["name"]
+//   - {name|upper|snake}  value with modifiers applied left to right
+//   - {{ and }}           literal { and }
+//
+// All placeholders without a value are reported together in an
+// *UnresolvedError so nothing half-rendered is ever sent to a provider.
+func Render(tmpl string, vars map[string]string, opts Options) (string, error) {
+	var sb strings.Builder
+	var missing []string
+	seen := make(map[string]bool)
+
+	for i := 0; i < len(tmpl); i++ {
+		c := tmpl[i]
+
+		switch {
+		case c == '{' && i+1 < len(tmpl) && tmpl[i+1] == '{':
+			sb.WriteByte('{')
+			i++
+
+		case c == '}' && i+1 < len(tmpl) && tmpl[i+1] == '}':
+			sb.WriteByte('}')
+			i++
+
+		case c == '{':
+			end := strings.IndexByte(tmpl[i+1:], '}')
+			if end == -1 {
+				return "", fmt.Errorf("unterminated placeholder in '%s' (use '{{' for a literal brace)", tmpl)
+			}
+			expr := tmpl[i+1 : i+1+end]
+			i += end + 1
+
+			value, ok, err := evaluate(expr, vars, opts)
+			if err != nil {
+				return "", fmt.Errorf("invalid placeholder '{%s}' in '%s': %w", expr, tmpl, err)
+			}
+			if !ok {
+				name := placeholderName(expr)
+				if !seen[name] {
+					seen[name] = true
+					missing = append(missing, name)
+				}
+				continue
+			}
+			sb.WriteString(value)
+
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	if len(missing) > 0 {
+		return "", &UnresolvedError{Template: tmpl, Names: missing}
+	}
+
+	return sb.String(), nil
+}
+
+// Names returns the placeholder names in tmpl, in order of first use
+func Names(tmpl string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for i := 0; i < len(tmpl); i++ {
+		if tmpl[i] != '{' && tmpl[i] != '}' {
+			continue
+		}
+		if i+1 < len(tmpl) && tmpl[i+1] == tmpl[i] {
+			i++
+			continue
+		}
+		if tmpl[i] == '}' {
+			continue
+		}
+		end := strings.IndexByte(tmpl[i+1:], '}')
+		if end == -1 {
+			break
+		}
+		name := placeholderName(tmpl[i+1 : i+1+end])
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		i += end + 1
+	}
+	return names
+}
+
+// evaluate resolves a single placeholder expression like "service|upper"
+func evaluate(expr string, vars map[string]string, opts Options) (string, bool, error) {
+	parts := strings.Split(expr, "|")
+	name := strings.TrimSpace(parts[0])
+	if name == "" {
+		return "", false, fmt.Errorf("empty placeholder name")
+	}
+
+	mods := parts[1:]
+	if len(mods) == 0 {
+		mods = opts.DefaultModifiers
+	}
+
+	// Validate modifiers even when the value is missing, so typos surface early
+	fns := make([]Modifier, 0, len(mods))
+	for _, m := range mods {
+		fn, ok := modifiers[strings.TrimSpace(m)]
+		if !ok {
+			return "", false, fmt.Errorf("unknown modifier '%s' (available: %s)", m, strings.Join(ModifierNames(), ", "))
+		}
+		fns = append(fns, fn)
+	}
+
+	value, ok := vars[name]
+	if !ok && opts.IgnoreCase {
+		value, ok = vars[strings.ToLower(name)]
+	}
+	if !ok {
+		return "", false, nil
+	}
+
+	for _, fn := range fns {
+		value = fn(value)
+	}
+	return value, true, nil
+}
+
+// placeholderName returns the name part of a placeholder expression
+func placeholderName(expr string) string {
+	name, _, _ := strings.Cut(expr, "|")
+	return strings.TrimSpace(name)
+}
+
+// separate replaces word separators (-, ., space, _) with sep
+func separate(s string, sep byte) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '.', ' ', '_':
+			return rune(sep)
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,116 @@
+package template
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	vars := map[string]string{
+		"service": "auth-service",
+		"env":     "prod",
+		"region":  "us-east-1",
+		"app":     "Billing.API",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		opts     Options
+		expected string
+	}{
+		{"no placeholders", "static/path", Options{}, "static/path"},
+		{"single placeholder", "services/{service}/url", Options{}, "services/auth-service/url"},
+		{"repeated placeholder", "{env}/{service}/{env}", Options{}, "prod/auth-service/prod"},
+		{"upper modifier", "{service|upper}", Options{}, "AUTH-SERVICE"},
+		{"lower modifier", "{app|lower}", Options{}, "billing.api"},
+		{"snake modifier", "{service|snake}", Options{}, "auth_service"},
+		{"kebab modifier", "{app|kebab}", Options{}, "billing-api"},
+		{"env modifier", "{app|env}_KEY", Options{}, "BILLING_API_KEY"},
+		{"chained modifiers", "{app|snake|upper}", Options{}, "BILLING_API"},
+		{"spaces around modifiers", "{ service | upper }", Options{}, "AUTH-SERVICE"},
+		{"escaped braces", "{{literal}}/{env}", Options{}, "{literal}/prod"},
+		{"escaped braces around placeholder", "{{{env}}}", Options{}, "{prod}"},
+		{"lone closing brace", "a}b", Options{}, "a}b"},
+		{"default modifiers", "{SERVICE}_{env}", Options{DefaultModifiers: []string{"env"}, IgnoreCase: true}, "AUTH_SERVICE_PROD"},
+		{"explicit modifier overrides default", "{service|lower}", Options{DefaultModifiers: []string{"env"}}, "auth-service"},
+		{"case sensitive by default", "{env}", Options{}, "prod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Render(tt.template, vars, tt.opts)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("got %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	got := Names("/{{literal}}/{tenant}/{env|upper}/{ tenant }/{open")
+	if want := []string{"tenant", "env"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	if got := Names("/plain"); got != nil {
+		t.Errorf("Names(no placeholders) = %v", got)
+	}
+}
+
+func TestRender_Unresolved(t *testing.T) {
+	vars := map[string]string{"service": "auth"}
+
+	_, err := Render("{project}/{service}/{region|upper}/{project}", vars, Options{})
+
+	var unresolved *UnresolvedError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("error = %v, want *UnresolvedError", err)
+	}
+	if !reflect.DeepEqual(unresolved.Names, []string{"project", "region"}) {
+		t.Errorf("Names = %v, want [project region]", unresolved.Names)
+	}
+	if !strings.Contains(err.Error(), "{project}, {region}") {
+		t.Errorf("error %q should list missing placeholders", err.Error())
+	}
+}
+
+func TestRender_CaseSensitiveNames(t *testing.T) {
+	_, err := Render("{SERVICE}", map[string]string{"service": "auth"}, Options{})
+
+	var unresolved *UnresolvedError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("error = %v, want *UnresolvedError", err)
+	}
+}
+
+func TestRender_SyntaxErrors(t *testing.T) {
+	vars := map[string]string{"service": "auth"}
+
+	tests := []struct {
+		name     string
+		template string
+		contains string
+	}{
+		{"unterminated", "services/{service", "unterminated placeholder"},
+		{"empty name", "services/{}/url", "empty placeholder name"},
+		{"unknown modifier", "{service|shout}", "unknown modifier 'shout'"},
+		{"unknown modifier on missing var", "{region|shout}", "unknown modifier 'shout'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render(tt.template, vars, Options{})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("error %q should contain %q", err.Error(), tt.contains)
+			}
+		})
+	}
+}
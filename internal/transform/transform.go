@@ -0,0 +1,236 @@
+package transform
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Priyans-hu/sreq/internal/jsonpath"
+	"gopkg.in/yaml.v3"
+)
+
+// Func transforms a resolved value. arg is the text after the first ':'
+// in the step (e.g. "sub" for "jwt:sub"), or empty if none was given.
+type Func func(value, arg string) (string, error)
+
+// Step is a single parsed transform in a pipeline
+type Step struct {
+	Name string
+	Arg  string
+}
+
+// String returns the step in its spec form (name or name:arg)
+func (s Step) String() string {
+	if s.Arg == "" {
+		return s.Name
+	}
+	return s.Name + ":" + s.Arg
+}
+
+var (
+	registry   = map[string]Func{}
+	registryMu sync.RWMutex
+)
+
+func init() {
+	Register("trim", trim)
+	Register("upper", upper)
+	Register("lower", lower)
+	Register("b64dec", b64dec)
+	Register("b64enc", b64enc)
+	Register("urlencode", urlencode)
+	Register("json", jsonField)
+	Register("yaml", yamlField)
+	Register("jwt", jwtClaim)
+	Register("prefix", prefix)
+	Register("suffix", suffix)
+	Register("port", port)
+	Register("urljoin", urljoin)
+}
+
+// Register adds a transform to the registry, replacing any existing
+// transform with the same name
+func Register(name string, fn Func) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = fn
+}
+
+// Lookup returns the transform registered under name
+func Lookup(name string) (Func, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	fn, ok := registry[name]
+	return fn, ok
+}
+
+// Names returns all registered transform names, sorted
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse parses pipe-separated transform steps (without the leading value)
+// and verifies that every transform is registered.
+// Example: ["b64dec", "jwt:sub", "trim"]
+func Parse(specs []string) ([]Step, error) {
+	steps := make([]Step, 0, len(specs))
+	for _, spec := range specs {
+		name, arg, _ := strings.Cut(strings.TrimSpace(spec), ":")
+		if name == "" {
+			return nil, fmt.Errorf("empty transform in pipeline")
+		}
+		if _, ok := Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown transform '%s' (available: %s)", name, strings.Join(Names(), ", "))
+		}
+		steps = append(steps, Step{Name: name, Arg: arg})
+	}
+	return steps, nil
+}
+
+// Apply runs value through each step in order
+func Apply(value string, steps []Step) (string, error) {
+	for _, step := range steps {
+		fn, ok := Lookup(step.Name)
+		if !ok {
+			return "", fmt.Errorf("unknown transform '%s'", step.Name)
+		}
+
+		var err error
+		value, err = fn(value, step.Arg)
+		if err != nil {
+			return "", fmt.Errorf("transform '%s' failed: %w", step, err)
+		}
+	}
+	return value, nil
+}
+
+func trim(value, arg string) (string, error) {
+	if arg != "" {
+		return strings.Trim(value, arg), nil
+	}
+	return strings.TrimSpace(value), nil
+}
+
+func upper(value, _ string) (string, error) {
+	return strings.ToUpper(value), nil
+}
+
+func lower(value, _ string) (string, error) {
+	return strings.ToLower(value), nil
+}
+
+// b64dec decodes standard or URL-safe base64, with or without padding
+func b64dec(value, _ string) (string, error) {
+	return decodeBase64(strings.TrimSpace(value))
+}
+
+func b64enc(value, _ string) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(value)), nil
+}
+
+func urlencode(value, _ string) (string, error) {
+	return url.QueryEscape(value), nil
+}
+
+// jsonField extracts a JSON path from the value (same syntax as #key)
+func jsonField(value, arg string) (string, error) {
+	return jsonpath.Extract(value, arg)
+}
+
+// yamlField parses the value as YAML and extracts a JSON path from it
+func yamlField(value, arg string) (string, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return "", fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("YAML cannot be represented as JSON: %w", err)
+	}
+	return jsonpath.Extract(string(data), arg)
+}
+
+// jwtClaim decodes a JWT's payload (without verifying the signature)
+// and returns a claim, or the whole payload if no claim is given
+func jwtClaim(value, arg string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(value), ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("value is not a JWT (expected 3 segments, got %d)", len(parts))
+	}
+
+	payload, err := decodeBase64(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid JWT payload: %w", err)
+	}
+	return jsonpath.Extract(payload, arg)
+}
+
+func prefix(value, arg string) (string, error) {
+	return arg + value, nil
+}
+
+func suffix(value, arg string) (string, error) {
+	return value + arg, nil
+}
+
+// port sets the port on a URL ("https://host" -> "https://host:8443")
+// or joins a bare host with a port ("host" -> "host:8443")
+func port(value, arg string) (string, error) {
+	if arg == "" {
+		return "", fmt.Errorf("port is required (e.g. port:8443)")
+	}
+
+	value = strings.TrimSpace(value)
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		u.Host = net.JoinHostPort(u.Hostname(), arg)
+		return u.String(), nil
+	}
+
+	host := value
+	if h, _, err := net.SplitHostPort(value); err == nil {
+		host = h
+	}
+	return net.JoinHostPort(host, arg), nil
+}
+
+// urljoin appends a path to a base URL without doubling or dropping slashes
+func urljoin(value, arg string) (string, error) {
+	base := strings.TrimRight(strings.TrimSpace(value), "/")
+	if arg == "" {
+		return base, nil
+	}
+	return base + "/" + strings.TrimLeft(arg, "/"), nil
+}
+
+func decodeBase64(s string) (string, error) {
+	encodings := []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	}
+
+	var lastErr error
+	for _, enc := range encodings {
+		decoded, err := enc.DecodeString(s)
+		if err == nil {
+			return string(decoded), nil
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
@@ -0,0 +1,146 @@
+package transform
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	steps, err := Parse([]string{"b64dec", "jwt:sub", " trim "})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []Step{{Name: "b64dec"}, {Name: "jwt", Arg: "sub"}, {Name: "trim"}}
+	if len(steps) != len(want) {
+		t.Fatalf("got %d steps, want %d", len(steps), len(want))
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("step %d = %+v, want %+v", i, steps[i], want[i])
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []string
+	}{
+		{"unknown transform", []string{"rot13"}},
+		{"empty transform", []string{"trim", ""}},
+		{"arg without name", []string{":x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.specs); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	jwt := "eyJhbGciOiJIUzI1NiJ9." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"svc-123","aud":["a","b"]}`)) +
+		".sig"
+
+	tests := []struct {
+		name     string
+		value    string
+		pipeline []string
+		expected string
+	}{
+		{"trim", "  token\n", []string{"trim"}, "token"},
+		{"trim with cutset", "--token--", []string{"trim:-"}, "token"},
+		{"upper", "abc", []string{"upper"}, "ABC"},
+		{"lower", "ABC", []string{"lower"}, "abc"},
+		{"b64dec padded", base64.StdEncoding.EncodeToString([]byte("secret")), []string{"b64dec"}, "secret"},
+		{"b64dec unpadded url-safe", base64.RawURLEncoding.EncodeToString([]byte("s?cr>t")), []string{"b64dec"}, "s?cr>t"},
+		{"b64enc", "secret", []string{"b64enc"}, "c2VjcmV0"},
+		{"urlencode", "a b&c", []string{"urlencode"}, "a+b%26c"},
+		{"json", `{"db": {"user": "admin"}}`, []string{"json:db.user"}, "admin"},
+		{"yaml", "db:\n  hosts:\n    - a\n    - b\n", []string{"yaml:db.hosts[1]"}, "b"},
+		{"jwt claim", jwt, []string{"jwt:sub"}, "svc-123"},
+		{"jwt array claim", jwt, []string{"jwt:aud"}, `["a","b"]`},
+		{"prefix", "example.com", []string{"prefix:https://"}, "https://example.com"},
+		{"suffix with colon", "host", []string{"suffix::8080"}, "host:8080"},
+		{"port on bare host", "db.internal", []string{"port:5432"}, "db.internal:5432"},
+		{"port replaces existing", "db.internal:1", []string{"port:5432"}, "db.internal:5432"},
+		{"port on url", "https://api.internal/v1", []string{"port:8443"}, "https://api.internal:8443/v1"},
+		{"urljoin", "https://api.internal/", []string{"urljoin:/v1/users"}, "https://api.internal/v1/users"},
+		{"chained", "  " + base64.StdEncoding.EncodeToString([]byte(" tok \n")) + " ", []string{"b64dec", "trim", "upper"}, "TOK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := Parse(tt.pipeline)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			result, err := Apply(tt.value, steps)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("got %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		pipeline []string
+	}{
+		{"invalid base64", "not base64!", []string{"b64dec"}},
+		{"not a jwt", "abc", []string{"jwt:sub"}},
+		{"missing json key", `{"a": 1}`, []string{"json:b"}},
+		{"invalid yaml", "a: [", []string{"yaml:a"}},
+		{"port without arg", "host", []string{"port"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := Parse(tt.pipeline)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			_, err = Apply(tt.value, steps)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.pipeline[0]) {
+				t.Errorf("error %q should name the failing transform", err.Error())
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("reverse", func(value, _ string) (string, error) {
+		runes := []rune(value)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	})
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "reverse")
+		registryMu.Unlock()
+	}()
+
+	steps, err := Parse([]string{"reverse"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	result, _ := Apply("abc", steps)
+	if result != "cba" {
+		t.Errorf("got %q, want %q", result, "cba")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Priyans-hu/sreq/internal/config"
 	"github.com/Priyans-hu/sreq/internal/history"
+	"github.com/Priyans-hu/sreq/internal/redact"
 	"github.com/Priyans-hu/sreq/pkg/types"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -37,6 +38,10 @@ type Model struct {
 	selectedHistory *history.Entry
 	currentEnv      string
 
+	// red masks secrets in displayed history, including entries saved
+	// before history was redacted
+	red *redact.Redactor
+
 	// Dimensions
 	width  int
 	height int
@@ -54,10 +59,11 @@ func (i serviceItem) FilterValue() string { return i.name }
 // historyItem implements list.Item for history entries
 type historyItem struct {
 	entry history.Entry
+	red   *redact.Redactor
 }
 
 func (i historyItem) Title() string {
-	return fmt.Sprintf("%s %s", i.entry.Method, i.entry.Path)
+	return fmt.Sprintf("%s %s", i.entry.Method, i.red.URL(i.entry.Path))
 }
 
 func (i historyItem) Description() string {
@@ -74,7 +80,7 @@ func (i historyItem) Description() string {
 }
 
 func (i historyItem) FilterValue() string {
-	return fmt.Sprintf("%s %s %s", i.entry.Service, i.entry.Method, i.entry.Path)
+	return fmt.Sprintf("%s %s %s", i.entry.Service, i.entry.Method, i.red.URL(i.entry.Path))
 }
 
 // keyMap defines keyboard shortcuts
@@ -172,9 +178,10 @@ func New() (*Model, error) {
 	serviceList.SetShowHelp(false)
 
 	// Create history list
+	red := redact.New()
 	historyListItems := make([]list.Item, len(historyEntries))
 	for i, e := range historyEntries {
-		historyListItems[i] = historyItem{entry: e}
+		historyListItems[i] = historyItem{entry: e, red: red}
 	}
 	historyList := list.New(historyListItems, list.NewDefaultDelegate(), 0, 0)
 	historyList.Title = "Recent Requests"
@@ -195,6 +202,7 @@ func New() (*Model, error) {
 		serviceList:  serviceList,
 		historyList:  historyList,
 		currentEnv:   currentEnv,
+		red:          red,
 	}, nil
 }
 
@@ -372,11 +380,11 @@ func (m Model) viewHistoryDetail() string {
 		fmt.Sprintf("Service:  %s", e.Service),
 		fmt.Sprintf("Env:      %s", e.Env),
 		fmt.Sprintf("Method:   %s", e.Method),
-		fmt.Sprintf("Path:     %s", e.Path),
+		fmt.Sprintf("Path:     %s", m.red.URL(e.Path)),
 	}
 
 	if e.BaseURL != "" {
-		details = append(details, fmt.Sprintf("Base URL: %s", e.BaseURL))
+		details = append(details, fmt.Sprintf("Base URL: %s", m.red.URL(e.BaseURL)))
 	}
 
 	if e.Status > 0 {
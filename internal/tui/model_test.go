@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Priyans-hu/sreq/internal/history"
+	"github.com/Priyans-hu/sreq/internal/redact"
+)
+
+func TestModel_RedactsHistory(t *testing.T) {
+	// Saved before history was redacted
+	entry := history.Entry{
+		ID:      1,
+		Service: "auth",
+		Env:     "dev",
+		Method:  "GET",
+		Path:    "/users?api_key=abc123&page=2",
+		BaseURL: "https://api.example.com?token=xyz789",
+	}
+	red := redact.New()
+	item := historyItem{entry: entry, red: red}
+	m := Model{selectedHistory: &entry, red: red}
+
+	for name, out := range map[string]string{
+		"title":  item.Title(),
+		"filter": item.FilterValue(),
+		"detail": m.viewHistoryDetail(),
+	} {
+		if strings.Contains(out, "abc123") || strings.Contains(out, "xyz789") {
+			t.Errorf("%s leaks a secret: %s", name, out)
+		}
+		if !strings.Contains(out, "page=2") {
+			t.Errorf("%s dropped a query param: %s", name, out)
+		}
+	}
+}
@@ -0,0 +1,438 @@
+// Package websocket implements the client side of the WebSocket protocol
+// (RFC 6455) over connections upgraded by internal/client.
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Priyans-hu/sreq/internal/client"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// Opcode is the type of a frame
+type Opcode byte
+
+// Frame opcodes
+const (
+	OpContinuation Opcode = 0x0
+	OpText         Opcode = 0x1
+	OpBinary       Opcode = 0x2
+	OpClose        Opcode = 0x8
+	OpPing         Opcode = 0x9
+	OpPong         Opcode = 0xA
+)
+
+func (o Opcode) String() string {
+	switch o {
+	case OpContinuation:
+		return "continuation"
+	case OpText:
+		return "text"
+	case OpBinary:
+		return "binary"
+	case OpClose:
+		return "close"
+	case OpPing:
+		return "ping"
+	case OpPong:
+		return "pong"
+	}
+	return fmt.Sprintf("opcode %d", byte(o))
+}
+
+// Close codes
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseNoStatus        = 1005
+	CloseAbnormal        = 1006
+	CloseInvalidData     = 1007
+	CloseMessageTooLarge = 1009
+)
+
+// MaxMessageSize bounds a received message, across its fragments
+const MaxMessageSize = 32 << 20
+
+// acceptGUID is appended to the handshake key to compute the accept value
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Message is a received data message
+type Message struct {
+	Type Opcode // OpText or OpBinary
+	Data []byte
+}
+
+// CloseError is returned by ReadMessage once the server closed the
+// connection
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("websocket closed: %d %s", e.Code, e.Reason)
+	}
+	return fmt.Sprintf("websocket closed: %d", e.Code)
+}
+
+// Conn is an open WebSocket connection. ReadMessage must be called from a
+// single goroutine; writes may come from any.
+type Conn struct {
+	// Subprotocol is the one the server selected, if any
+	Subprotocol string
+
+	rw io.ReadWriteCloser
+	br *bufio.Reader
+
+	writeMu   sync.Mutex
+	closeSent bool
+
+	closeErr   *CloseError
+	fragOp     Opcode
+	fragmented []byte
+}
+
+// URL returns the ws:// or wss:// form of an http:// or https:// URL
+func URL(httpURL string) string {
+	u, err := url.Parse(httpURL)
+	if err != nil {
+		return httpURL
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+	return u.String()
+}
+
+// httpBaseURL maps a ws:// or wss:// base URL to the http:// or https://
+// URL the handshake is sent to
+func httpBaseURL(baseURL string) string {
+	lower := strings.ToLower(baseURL)
+	switch {
+	case strings.HasPrefix(lower, "ws://"):
+		return "http://" + baseURL[len("ws://"):]
+	case strings.HasPrefix(lower, "wss://"):
+		return "https://" + baseURL[len("wss://"):]
+	}
+	return baseURL
+}
+
+// HandshakeError is returned by Dial when the server does not switch
+// protocols. Response is the server's answer.
+type HandshakeError struct {
+	Response *types.Response
+}
+
+func (e *HandshakeError) Error() string {
+	return fmt.Sprintf("server refused the websocket upgrade: %s", e.Response.Status)
+}
+
+// Dial opens a WebSocket at req.Path of the service, sending req.Headers
+// and the service's auth with the handshake. Base URLs may use http(s) or
+// ws(s). subprotocols are offered in order of preference. The handshake
+// response is returned whenever the server answered, even with an error.
+func Dial(ctx context.Context, c *client.Client, req *types.Request, creds *types.ResolvedCredentials, subprotocols []string) (*Conn, *types.Response, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hreq := *req
+	hreq.Method = http.MethodGet
+	hreq.Body = ""
+	hreq.Headers = make(map[string]string, len(req.Headers)+5)
+	for k, v := range req.Headers {
+		hreq.Headers[k] = v
+	}
+	hreq.Headers["Connection"] = "Upgrade"
+	hreq.Headers["Upgrade"] = "websocket"
+	hreq.Headers["Sec-WebSocket-Version"] = "13"
+	hreq.Headers["Sec-WebSocket-Key"] = key
+	if len(subprotocols) > 0 {
+		hreq.Headers["Sec-WebSocket-Protocol"] = strings.Join(subprotocols, ", ")
+	}
+
+	hcreds := *creds
+	hcreds.BaseURL = httpBaseURL(creds.BaseURL)
+
+	resp, rw, err := c.Upgrade(ctx, &hreq, &hcreds)
+	if err != nil {
+		return nil, resp, err
+	}
+	if rw == nil {
+		return nil, resp, &HandshakeError{Response: resp}
+	}
+
+	h := http.Header(resp.Headers)
+	if !strings.EqualFold(h.Get("Upgrade"), "websocket") {
+		_ = rw.Close()
+		return nil, resp, fmt.Errorf("server switched to %q instead of websocket", h.Get("Upgrade"))
+	}
+	if h.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		_ = rw.Close()
+		return nil, resp, fmt.Errorf("server sent an invalid Sec-WebSocket-Accept")
+	}
+	if h.Get("Sec-WebSocket-Extensions") != "" {
+		_ = rw.Close()
+		return nil, resp, fmt.Errorf("server enabled extensions that were not offered: %s", h.Get("Sec-WebSocket-Extensions"))
+	}
+
+	return &Conn{
+		rw:          rw,
+		br:          bufio.NewReader(rw),
+		Subprotocol: h.Get("Sec-WebSocket-Protocol"),
+	}, resp, nil
+}
+
+// newKey returns a random Sec-WebSocket-Key
+func newKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate websocket key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// acceptKey computes the Sec-WebSocket-Accept expected for key
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// ReadMessage returns the next text or binary message. Pings are answered
+// and fragments joined on the way. Once the server closes the connection,
+// the close is acknowledged and a *CloseError returned.
+func (c *Conn) ReadMessage() (*Message, error) {
+	if c.closeErr != nil {
+		return nil, c.closeErr
+	}
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case OpPing:
+			if err := c.writeFrame(OpPong, payload); err != nil {
+				return nil, err
+			}
+		case OpPong:
+		case OpClose:
+			c.closeErr = parseClose(payload)
+			// Echo the close unless we started it
+			_ = c.writeClose(c.closeErr.Code, "")
+			return nil, c.closeErr
+		case OpText, OpBinary:
+			if c.fragOp != 0 {
+				return nil, c.fail(CloseProtocolError, "new message before the last one finished")
+			}
+			if !fin {
+				c.fragOp, c.fragmented = op, payload
+				continue
+			}
+			return c.message(op, payload)
+		case OpContinuation:
+			if c.fragOp == 0 {
+				return nil, c.fail(CloseProtocolError, "continuation frame without a message")
+			}
+			if len(c.fragmented)+len(payload) > MaxMessageSize {
+				return nil, c.fail(CloseMessageTooLarge, "message too large")
+			}
+			c.fragmented = append(c.fragmented, payload...)
+			if fin {
+				op, data := c.fragOp, c.fragmented
+				c.fragOp, c.fragmented = 0, nil
+				return c.message(op, data)
+			}
+		default:
+			return nil, c.fail(CloseProtocolError, fmt.Sprintf("unknown %s", op))
+		}
+	}
+}
+
+// message validates a complete message
+func (c *Conn) message(op Opcode, data []byte) (*Message, error) {
+	if op == OpText && !utf8.Valid(data) {
+		return nil, c.fail(CloseInvalidData, "text message is not valid UTF-8")
+	}
+	return &Message{Type: op, Data: data}, nil
+}
+
+// fail closes the connection after a protocol violation by the server
+func (c *Conn) fail(code int, reason string) error {
+	_ = c.writeClose(code, reason)
+	return fmt.Errorf("websocket protocol error: %s", reason)
+}
+
+// readFrame reads one frame from the server, which never masks them
+func (c *Conn) readFrame() (fin bool, op Opcode, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err = io.ReadFull(c.br, hdr[:]); err != nil {
+		return false, 0, nil, readErr(err)
+	}
+	fin = hdr[0]&0x80 != 0
+	if hdr[0]&0x70 != 0 {
+		return false, 0, nil, c.fail(CloseProtocolError, "reserved bits set without an extension")
+	}
+	op = Opcode(hdr[0] & 0x0F)
+	if hdr[1]&0x80 != 0 {
+		return false, 0, nil, c.fail(CloseProtocolError, "masked frame from server")
+	}
+
+	n := uint64(hdr[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, readErr(err)
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, readErr(err)
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if op >= OpClose && (n > 125 || !fin) {
+		return false, 0, nil, c.fail(CloseProtocolError, "invalid control frame")
+	}
+	if n > MaxMessageSize {
+		return false, 0, nil, c.fail(CloseMessageTooLarge, "message too large")
+	}
+
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, readErr(err)
+	}
+	return fin, op, payload, nil
+}
+
+// readErr reports a connection that ended without a close frame
+func readErr(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &CloseError{Code: CloseAbnormal, Reason: "connection closed without a close frame"}
+	}
+	return err
+}
+
+// parseClose reads the status code and reason of a close frame
+func parseClose(payload []byte) *CloseError {
+	if len(payload) < 2 {
+		return &CloseError{Code: CloseNoStatus}
+	}
+	return &CloseError{
+		Code:   int(binary.BigEndian.Uint16(payload)),
+		Reason: string(payload[2:]),
+	}
+}
+
+// WriteMessage sends a text or binary message in a single frame
+func (c *Conn) WriteMessage(op Opcode, data []byte) error {
+	if op != OpText && op != OpBinary {
+		return fmt.Errorf("cannot send a %s frame as a message", op)
+	}
+	return c.writeFrame(op, data)
+}
+
+// Ping sends a ping; the server's pong is consumed by ReadMessage
+func (c *Conn) Ping(data []byte) error {
+	return c.writeFrame(OpPing, data)
+}
+
+// CloseWithReason starts the closing handshake. ReadMessage returns a
+// *CloseError once the server acknowledges it.
+func (c *Conn) CloseWithReason(code int, reason string) error {
+	return c.writeClose(code, reason)
+}
+
+// writeClose sends a close frame, at most once
+func (c *Conn) writeClose(code int, reason string) error {
+	c.writeMu.Lock()
+	sent := c.closeSent
+	c.closeSent = true
+	c.writeMu.Unlock()
+	if sent {
+		return nil
+	}
+
+	var payload []byte
+	if code != CloseNoStatus {
+		payload = binary.BigEndian.AppendUint16(nil, uint16(code))
+		payload = append(payload, reason...)
+	}
+	return c.writeFrame(OpClose, payload)
+}
+
+// Close closes the underlying connection without a closing handshake
+func (c *Conn) Close() error {
+	return c.rw.Close()
+}
+
+// writeFrame sends one final, masked frame
+func (c *Conn) writeFrame(op Opcode, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closeSent {
+		switch op {
+		case OpClose:
+		case OpPong:
+			return nil // A ping racing our close needs no answer
+		default:
+			return fmt.Errorf("websocket is closing")
+		}
+	}
+
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|byte(op))
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	// Clients must mask every frame with a fresh key
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	start := len(frame)
+	frame = append(frame, payload...)
+	maskBytes(mask, frame[start:])
+
+	_, err := c.rw.Write(frame)
+	return err
+}
+
+// maskBytes applies a masking key in place
+func maskBytes(mask [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= mask[i%4]
+	}
+}
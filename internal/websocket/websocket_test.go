@@ -0,0 +1,278 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Priyans-hu/sreq/internal/client"
+	"github.com/Priyans-hu/sreq/pkg/types"
+)
+
+// testServer accepts one WebSocket per request and hands it to fn
+func testServer(t *testing.T, fn func(r *http.Request, br *bufio.Reader, w io.Writer)) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+			http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack() error = %v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		_, _ = io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+			"Sec-WebSocket-Accept: "+acceptKey(r.Header.Get("Sec-WebSocket-Key"))+"\r\n\r\n")
+		fn(r, rw.Reader, conn)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// serverFrame writes an unmasked frame, as servers send them
+func serverFrame(w io.Writer, fin bool, op Opcode, payload []byte) {
+	b0 := byte(op)
+	if fin {
+		b0 |= 0x80
+	}
+	frame := []byte{b0}
+	if len(payload) <= 125 {
+		frame = append(frame, byte(len(payload)))
+	} else {
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	}
+	_, _ = w.Write(append(frame, payload...))
+}
+
+// readClientFrame reads a frame from the client, which must be masked
+func readClientFrame(t *testing.T, br *bufio.Reader) (Opcode, []byte) {
+	t.Helper()
+	var hdr [2]byte
+	if _, err := io.ReadFull(br, hdr[:]); err != nil {
+		t.Errorf("reading client frame: %v", err)
+		return 0, nil
+	}
+	if hdr[0]&0x80 == 0 {
+		t.Error("client frame is not final")
+	}
+	if hdr[1]&0x80 == 0 {
+		t.Error("client frame is not masked")
+	}
+
+	n := uint64(hdr[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		_, _ = io.ReadFull(br, ext[:])
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		_, _ = io.ReadFull(br, ext[:])
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	var mask [4]byte
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(br, mask[:]); err != nil {
+		t.Errorf("reading client frame: %v", err)
+	}
+	if _, err := io.ReadFull(br, payload); err != nil {
+		t.Errorf("reading client frame: %v", err)
+	}
+	maskBytes(mask, payload)
+	return Opcode(hdr[0] & 0x0F), payload
+}
+
+type nopWriteCloser struct{}
+
+func (nopWriteCloser) Read([]byte) (int, error)    { return 0, io.EOF }
+func (nopWriteCloser) Write(p []byte) (int, error) { return len(p), nil }
+func (nopWriteCloser) Close() error                { return nil }
+
+func dial(t *testing.T, url string, opts ...client.Option) (*Conn, *types.Response, error) {
+	t.Helper()
+	creds := &types.ResolvedCredentials{
+		BaseURL: strings.Replace(url, "http://", "ws://", 1),
+		Auth:    &types.ResolvedAuth{Type: types.AuthBearer, Token: "t0ken"},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return Dial(ctx, client.New(opts...), &types.Request{Path: "/ws?room=1"}, creds, []string{"chat.v2", "chat.v1"})
+}
+
+func TestDial_Messages(t *testing.T) {
+	server := testServer(t, func(r *http.Request, br *bufio.Reader, w io.Writer) {
+		if got := r.Header.Get("Authorization"); got != "Bearer t0ken" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.URL.Path != "/ws" || r.URL.Query().Get("room") != "1" {
+			t.Errorf("handshake URL = %s", r.URL)
+		}
+
+		// Echo one message, after a ping and as two fragments
+		op, payload := readClientFrame(t, br)
+		if op != OpText || string(payload) != "hello" {
+			t.Errorf("client sent %s %q, want text \"hello\"", op, payload)
+		}
+		serverFrame(w, true, OpPing, []byte("p"))
+		serverFrame(w, false, OpText, []byte("hel"))
+		serverFrame(w, true, OpContinuation, []byte("lo"))
+		if op, payload := readClientFrame(t, br); op != OpPong || string(payload) != "p" {
+			t.Errorf("ping answered with %s %q, want pong \"p\"", op, payload)
+		}
+
+		serverFrame(w, true, OpBinary, []byte{0, 1, 2})
+		serverFrame(w, true, OpClose, append(binary.BigEndian.AppendUint16(nil, CloseGoingAway), "bye"...))
+		if op, _ := readClientFrame(t, br); op != OpClose {
+			t.Errorf("close answered with %s", op)
+		}
+	})
+
+	conn, resp, err := dial(t, server.URL)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("StatusCode = %d", resp.StatusCode)
+	}
+
+	if err := conn.WriteMessage(OpText, []byte("hello")); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	msg, err := conn.ReadMessage()
+	if err != nil || msg.Type != OpText || string(msg.Data) != "hello" {
+		t.Fatalf("ReadMessage() = %+v, %v; want text \"hello\"", msg, err)
+	}
+	msg, err = conn.ReadMessage()
+	if err != nil || msg.Type != OpBinary || len(msg.Data) != 3 {
+		t.Fatalf("ReadMessage() = %+v, %v; want 3 binary bytes", msg, err)
+	}
+
+	_, err = conn.ReadMessage()
+	var ce *CloseError
+	if !errors.As(err, &ce) || ce.Code != CloseGoingAway || ce.Reason != "bye" {
+		t.Fatalf("ReadMessage() error = %v, want close 1001 bye", err)
+	}
+	if err := conn.WriteMessage(OpText, []byte("late")); err == nil {
+		t.Error("WriteMessage() after close should fail")
+	}
+}
+
+func TestDial_TokenQuery(t *testing.T) {
+	server := testServer(t, func(r *http.Request, br *bufio.Reader, w io.Writer) {
+		if got := r.URL.Query().Get("access_token"); got != "t0ken" {
+			t.Errorf("access_token = %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Authorization = %q, want none", got)
+		}
+		serverFrame(w, true, OpClose, nil)
+	})
+
+	conn, _, err := dial(t, server.URL, client.WithTokenQuery("access_token"))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	_, err = conn.ReadMessage()
+	var ce *CloseError
+	if !errors.As(err, &ce) || ce.Code != CloseNoStatus {
+		t.Errorf("ReadMessage() error = %v, want close 1005", err)
+	}
+}
+
+func TestDial_Refused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "token expired", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, resp, err := dial(t, server.URL)
+	var he *HandshakeError
+	if !errors.As(err, &he) {
+		t.Fatalf("Dial() error = %v, want a HandshakeError", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized || !strings.Contains(string(resp.Body), "token expired") {
+		t.Errorf("response = %d %q", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestDial_BadAccept(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		defer func() { _ = conn.Close() }()
+		_, _ = io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: nope\r\n\r\n")
+	}))
+	defer server.Close()
+
+	if _, _, err := dial(t, server.URL); err == nil || !strings.Contains(err.Error(), "Sec-WebSocket-Accept") {
+		t.Errorf("Dial() error = %v, want an accept mismatch", err)
+	}
+}
+
+func TestReadMessage_ProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames func(w io.Writer)
+		want   string
+	}{
+		{"invalid utf-8", func(w io.Writer) { serverFrame(w, true, OpText, []byte{0xff, 0xfe}) }, "UTF-8"},
+		{"stray continuation", func(w io.Writer) { serverFrame(w, true, OpContinuation, []byte("x")) }, "continuation"},
+		{"fragmented ping", func(w io.Writer) { serverFrame(w, false, OpPing, nil) }, "control frame"},
+		{"unknown opcode", func(w io.Writer) { serverFrame(w, true, Opcode(3), nil) }, "unknown"},
+		{"masked frame", func(w io.Writer) { _, _ = w.Write([]byte{0x80 | byte(OpText), 0x81, 1, 2, 3, 4, 'x' ^ 1}) }, "masked frame from server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var frames strings.Builder
+			tt.frames(&frames)
+			var sent strings.Builder
+			c := &Conn{br: bufio.NewReader(strings.NewReader(frames.String())), rw: &writeRecorder{w: &sent}}
+
+			_, err := c.ReadMessage()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadMessage() error = %v, want %q", err, tt.want)
+			}
+			// The client closes the connection with a reason
+			if !strings.HasPrefix(sent.String(), string([]byte{0x80 | byte(OpClose)})) {
+				t.Errorf("no close frame sent, got %q", sent.String())
+			}
+		})
+	}
+}
+
+type writeRecorder struct {
+	nopWriteCloser
+	w io.Writer
+}
+
+func (r *writeRecorder) Write(p []byte) (int, error) { return r.w.Write(p) }
+
+func TestURL(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:8080/ws":       "ws://localhost:8080/ws",
+		"https://api.example.com/v1?x=1": "wss://api.example.com/v1?x=1",
+		"wss://api.example.com":          "wss://api.example.com",
+	}
+	for in, want := range tests {
+		if got := URL(in); got != want {
+			t.Errorf("URL(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := httpBaseURL("WSS://api.example.com/v1"); got != "https://api.example.com/v1" {
+		t.Errorf("httpBaseURL() = %q", got)
+	}
+}
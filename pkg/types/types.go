@@ -1,5 +1,10 @@
 package types
 
+import (
+	"strings"
+	"time"
+)
+
 // ServiceConfig represents a service's configuration
 // Supports two modes:
 //
@@ -18,6 +23,19 @@ package types
 //	      base_url: "billing_service/invoice_svc_url"
 //	      username: "billing_service/invoice_svc_username"
 //	      password: "aws:billing/{env}/invoice#password"
+//
+// Per-environment overrides and inheritance:
+//
+//	services:
+//	  base-api:
+//	    paths:
+//	      base_url: "shared/{env}/api_url"
+//	  orders:
+//	    extends: base-api
+//	    envs:
+//	      prod:
+//	        paths:
+//	          password: "aws:orders/prod-vault#password"
 type ServiceConfig struct {
 	Name      string `yaml:"name,omitempty"`
 	ConsulKey string `yaml:"consul_key,omitempty"` // Simple mode: Consul key prefix
@@ -25,13 +43,317 @@ type ServiceConfig struct {
 
 	// Advanced mode: explicit path mappings
 	// Keys: base_url, username, password, api_key, or custom
-	// Values: path with optional provider prefix (consul:, aws:)
-	//         and JSON key suffix (#key)
+	// Values: path with optional provider prefix (consul:, aws:),
+	//         JSON key suffix (#key) and transforms (|b64dec|trim)
 	// Examples:
 	//   "billing_service/invoice_svc_url"           -> Consul (default)
 	//   "consul:billing_service/invoice_svc_url"    -> Consul (explicit)
 	//   "aws:billing/dev/creds#password"            -> AWS with JSON key
+	//   "aws:billing/dev/creds#token|b64dec|trim"   -> AWS with JSON key and transforms
 	Paths map[string]string `yaml:"paths,omitempty"`
+
+	// Extends names another service whose settings this one inherits.
+	// Fields set here override the parent; paths are merged key by key.
+	Extends string `yaml:"extends,omitempty"`
+
+	// Envs holds per-environment overrides merged over the base settings
+	Envs map[string]ServiceEnvConfig `yaml:"envs,omitempty"`
+
+	// Auth selects how credentials are attached to requests.
+	// Without it, Basic auth is used when username and password resolve.
+	Auth *AuthConfig `yaml:"auth,omitempty"`
+
+	// TLS configures client certificates and server verification
+	TLS *TLSConfig `yaml:"tls,omitempty"`
+
+	// Retry overrides the global retry policy for this service
+	Retry *RetryConfig `yaml:"retry,omitempty"`
+
+	// Proxy overrides the global and per-environment proxy
+	Proxy *ProxyConfig `yaml:"proxy,omitempty"`
+
+	// Protocol forces an HTTP version, see the Protocol constants
+	Protocol string `yaml:"protocol,omitempty"`
+
+	// Cookies keeps a cookie jar per environment across invocations,
+	// stored in the encrypted cache. Unset inherits; false turns an
+	// inherited jar off.
+	Cookies *bool `yaml:"cookies,omitempty"`
+}
+
+// ServiceEnvConfig overrides a service's settings for one environment
+type ServiceEnvConfig struct {
+	ConsulKey string            `yaml:"consul_key,omitempty"`
+	AWSPrefix string            `yaml:"aws_prefix,omitempty"`
+	Paths     map[string]string `yaml:"paths,omitempty"`
+	Auth      *AuthConfig       `yaml:"auth,omitempty"`
+	TLS       *TLSConfig        `yaml:"tls,omitempty"`
+	Retry     *RetryConfig      `yaml:"retry,omitempty"`
+	Proxy     *ProxyConfig      `yaml:"proxy,omitempty"`
+	Protocol  string            `yaml:"protocol,omitempty"`
+	Cookies   *bool             `yaml:"cookies,omitempty"`
+}
+
+// HTTP versions a service can be pinned to. Without one, HTTP/2 is
+// negotiated over TLS and HTTP/1.1 is used otherwise.
+const (
+	ProtocolHTTP1 = "http1.1"
+	ProtocolHTTP2 = "h2"  // HTTP/2 over TLS only
+	ProtocolH2C   = "h2c" // cleartext HTTP/2 with prior knowledge
+	ProtocolHTTP3 = "h3"  // HTTP/3 over QUIC
+)
+
+// RetryConfig configures retries of failed requests. Connection errors
+// and the retry_on statuses are retried; non-idempotent methods (POST,
+// PATCH) only when non_idempotent is set or an Idempotency-Key is sent.
+//
+//	retry:
+//	  max_attempts: 3
+//	  backoff: 200ms
+//	  max_backoff: 5s
+//	  retry_on: [429, 502, 503, 504]
+type RetryConfig struct {
+	MaxAttempts   int    `yaml:"max_attempts,omitempty"`
+	Backoff       string `yaml:"backoff,omitempty"`
+	MaxBackoff    string `yaml:"max_backoff,omitempty"`
+	RetryOn       []int  `yaml:"retry_on,omitempty"`
+	NonIdempotent bool   `yaml:"non_idempotent,omitempty"`
+}
+
+// Cookie is a stored HTTP cookie
+type Cookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain"`
+	HostOnly bool      `json:"host_only,omitempty"`
+	Path     string    `json:"path"`
+	Expires  time.Time `json:"expires,omitempty"` // zero for session cookies
+	Secure   bool      `json:"secure,omitempty"`
+	HTTPOnly bool      `json:"http_only,omitempty"`
+}
+
+// Expired reports whether the cookie has expired at now
+func (c Cookie) Expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+// Attempt records one try of a request
+type Attempt struct {
+	StatusCode int
+	Error      string
+	Duration   time.Duration
+	// Wait is the backoff slept before the next attempt
+	Wait time.Duration
+}
+
+// TLSConfig configures mutual TLS for a service. Cert, Key and CA are path
+// specs resolved through providers, like paths, and hold PEM data.
+//
+//	tls:
+//	  cert: "aws:partners/{env}/mtls#cert"
+//	  key: "aws:partners/{env}/mtls#key"
+//	  ca: "consul:partners/ca.pem"
+type TLSConfig struct {
+	Cert       string `yaml:"cert,omitempty"`
+	Key        string `yaml:"key,omitempty"`
+	CA         string `yaml:"ca,omitempty"`
+	ServerName string `yaml:"server_name,omitempty"`
+
+	// InsecureSkipVerify disables server certificate checks.
+	// Refused in protected environments.
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify,omitempty"`
+}
+
+// ProxyDirect as a proxy url disables any inherited proxy
+const ProxyDirect = "direct"
+
+// ProxyConfig routes requests through an HTTP, HTTPS or SOCKS5 proxy.
+// Username and password are path specs resolved through providers.
+//
+//	proxy:
+//	  url: "socks5://proxy.corp:1080"
+//	  username: "aws:network/{env}/proxy#username"
+//	  password: "aws:network/{env}/proxy#password"
+//	  no_proxy: [localhost, .internal, 10.0.0.0/8]
+type ProxyConfig struct {
+	URL      string   `yaml:"url"`
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	NoProxy  []string `yaml:"no_proxy,omitempty"`
+}
+
+// ResolvedProxy is a proxy with its credentials resolved
+type ResolvedProxy struct {
+	URL      string
+	Username string
+	Password string
+	NoProxy  []string
+}
+
+// ResolvedTLS holds resolved PEM material. It is kept in memory or in the
+// encrypted cache only.
+type ResolvedTLS struct {
+	CertPEM            string
+	KeyPEM             string
+	CAPEM              string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// Auth scheme types
+const (
+	AuthNone   = "none"
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+	AuthAPIKey = "api_key"
+	AuthDigest = "digest"
+	AuthOAuth2 = "oauth2"
+	AuthSigV4  = "sigv4"
+	AuthHMAC   = "hmac"
+	AuthJWT    = "jwt"
+)
+
+// AuthTypes lists every auth scheme type
+var AuthTypes = []string{AuthNone, AuthBasic, AuthBearer, AuthAPIKey, AuthDigest, AuthOAuth2, AuthSigV4, AuthHMAC, AuthJWT}
+
+// OAuth2 grant types
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// AuthConfig describes how a service authenticates.
+// Value fields are templates over resolved fields: {username}, {password},
+// {api_key}, {base_url} and any custom path key.
+//
+//	auth:
+//	  type: api_key
+//	  in: header            # header (default) or query
+//	  name: X-Service-Token
+//	  value: "{api_key}"
+type AuthConfig struct {
+	Type string `yaml:"type"` // none, basic, bearer, api_key, digest, oauth2, sigv4, hmac, jwt
+
+	// basic, digest
+	Username string `yaml:"username,omitempty"` // default: {username}
+	Password string `yaml:"password,omitempty"` // default: {password}
+
+	// bearer
+	Token string `yaml:"token,omitempty"` // default: {api_key}
+
+	// api_key
+	In    string `yaml:"in,omitempty"`    // header (default) or query
+	Name  string `yaml:"name,omitempty"`  // default: X-API-Key (header) or api_key (query)
+	Value string `yaml:"value,omitempty"` // default: {api_key}
+
+	// oauth2
+	TokenURL     string   `yaml:"token_url,omitempty"`
+	GrantType    string   `yaml:"grant_type,omitempty"`    // client_credentials (default) or refresh_token
+	ClientID     string   `yaml:"client_id,omitempty"`     // default: {client_id}
+	ClientSecret string   `yaml:"client_secret,omitempty"` // default: {client_secret}
+	RefreshToken string   `yaml:"refresh_token,omitempty"` // default: {refresh_token} (refresh_token grant)
+	Scopes       []string `yaml:"scopes,omitempty"`
+	Audience     string   `yaml:"audience,omitempty"`
+	AuthStyle    string   `yaml:"auth_style,omitempty"` // body (default) or header (client_secret_basic)
+
+	// sigv4: static keys when access_key_id is set, otherwise the AWS
+	// credential chain for profile, optionally assuming role_arn
+	SigningService  string `yaml:"service,omitempty"` // e.g. execute-api, es
+	Region          string `yaml:"region,omitempty"`  // default: {region}, then the AWS profile's region
+	Profile         string `yaml:"profile,omitempty"`
+	RoleARN         string `yaml:"role_arn,omitempty"`
+	AccessKeyID     string `yaml:"access_key_id,omitempty"`
+	SecretAccessKey string `yaml:"secret_access_key,omitempty"`
+	SessionToken    string `yaml:"session_token,omitempty"`
+
+	// hmac: Canonical and SignatureFormat are rendered per request with
+	// HMACRequestVars, the other fields with resolved credentials
+	Secret          string `yaml:"secret,omitempty"`           // default: {api_key}
+	KeyID           string `yaml:"key_id,omitempty"`           // available as {key_id}
+	Algorithm       string `yaml:"algorithm,omitempty"`        // sha256 (default), sha512, sha1
+	Encoding        string `yaml:"encoding,omitempty"`         // hex (default), base64, base64url
+	Canonical       string `yaml:"canonical,omitempty"`        // default: "{method}\n{path}\n{timestamp}\n{body}"
+	SignatureHeader string `yaml:"signature_header,omitempty"` // default: X-Signature
+	SignatureFormat string `yaml:"signature_format,omitempty"` // default: {signature}
+	TimestampHeader string `yaml:"timestamp_header,omitempty"` // default: X-Timestamp
+	TimestampFormat string `yaml:"timestamp_format,omitempty"` // unix (default), unix_ms, rfc3339
+	NonceHeader     string `yaml:"nonce_header,omitempty"`     // sent only when set
+
+	// jwt: Algorithm is RS256 (default), ES256 or HS256. HS256 signs with
+	// Secret; KeyID becomes the "kid" header.
+	PrivateKey string            `yaml:"private_key,omitempty"` // default: {private_key}
+	Claims     map[string]string `yaml:"claims,omitempty"`      // iss, sub, aud and custom claims
+	ExpiresIn  string            `yaml:"expires_in,omitempty"`  // default: 5m
+}
+
+// HMACRequestVars are the placeholders available in hmac canonical and
+// signature_format templates
+var HMACRequestVars = []string{
+	"method", "path", "query", "host", "content_type",
+	"timestamp", "nonce", "body", "body_sha256", "key_id", "signature",
+}
+
+// ResolvedAuth is an AuthConfig with all templates rendered
+type ResolvedAuth struct {
+	Type     string
+	Username string
+	Password string
+	Token    string
+	In       string
+	Name     string
+	Value    string
+
+	// oauth2
+	TokenURL     string
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+	Scopes       []string
+	Audience     string
+	AuthStyle    string
+
+	// sigv4
+	SigningService  string
+	Region          string
+	Profile         string
+	RoleARN         string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// hmac
+	Secret          string
+	KeyID           string
+	Algorithm       string
+	Encoding        string
+	Canonical       string
+	SignatureHeader string
+	SignatureFormat string
+	TimestampHeader string
+	TimestampFormat string
+	NonceHeader     string
+
+	// jwt
+	PrivateKey string
+	Claims     map[string]string
+	ExpiresIn  time.Duration
+}
+
+// OAuthToken is an access token obtained from an OAuth2 token endpoint
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// Valid reports whether the access token is usable for at least leeway
+func (t *OAuthToken) Valid(leeway time.Duration) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(leeway).Before(t.Expiry)
 }
 
 // IsAdvancedMode returns true if the service uses explicit path mappings
@@ -41,12 +363,12 @@ func (s *ServiceConfig) IsAdvancedMode() bool {
 
 // ProviderConfig represents a secret provider's configuration
 type ProviderConfig struct {
-	Type       string            `yaml:"type,omitempty"`
-	Address    string            `yaml:"address,omitempty"`
-	Token      string            `yaml:"token,omitempty"`
-	Region     string            `yaml:"region,omitempty"`
-	Profile    string            `yaml:"profile,omitempty"`
-	Datacenter string            `yaml:"datacenter,omitempty"` // Consul datacenter
+	Type       string `yaml:"type,omitempty"`
+	Address    string `yaml:"address,omitempty"`
+	Token      string `yaml:"token,omitempty"`
+	Region     string `yaml:"region,omitempty"`
+	Profile    string `yaml:"profile,omitempty"`
+	Datacenter string `yaml:"datacenter,omitempty"` // Consul datacenter
 
 	// Environment-specific addresses (overrides Address for specific envs)
 	// Example:
@@ -97,6 +419,37 @@ type Config struct {
 	Services       map[string]ServiceConfig  `yaml:"services,omitempty"`
 	Contexts       map[string]Context        `yaml:"contexts,omitempty"`
 	DefaultContext string                    `yaml:"default_context,omitempty"`
+
+	// ProtectedEnvs are environments where unsafe options (such as
+	// skipping TLS verification) are refused. Default: prod, production.
+	ProtectedEnvs []string `yaml:"protected_envs,omitempty"`
+
+	// Retry is the default retry policy, services may override it
+	Retry *RetryConfig `yaml:"retry,omitempty"`
+
+	// Proxy is used for every request unless an environment or service
+	// overrides it. Without any proxy config, HTTP_PROXY and friends apply.
+	Proxy *ProxyConfig `yaml:"proxy,omitempty"`
+
+	// EnvProxies override Proxy per environment
+	EnvProxies map[string]*ProxyConfig `yaml:"env_proxies,omitempty"`
+}
+
+// DefaultProtectedEnvs are used when protected_envs is not configured
+var DefaultProtectedEnvs = []string{"prod", "production"}
+
+// IsProtectedEnv reports whether env is a protected environment
+func (c *Config) IsProtectedEnv(env string) bool {
+	protected := c.ProtectedEnvs
+	if len(protected) == 0 {
+		protected = DefaultProtectedEnvs
+	}
+	for _, p := range protected {
+		if strings.EqualFold(p, env) {
+			return true
+		}
+	}
+	return false
 }
 
 // ResolvedCredentials contains the resolved credentials for a service
@@ -107,6 +460,9 @@ type ResolvedCredentials struct {
 	APIKey   string            // API key (if used instead of basic auth)
 	Headers  map[string]string // Additional headers to add to requests
 	Custom   map[string]string // Custom resolved values from paths
+	Auth     *ResolvedAuth     // Auth scheme to apply (nil: basic auth if username/password set)
+	TLS      *ResolvedTLS      // Client certificate and CA (nil: system defaults)
+	Proxy    *ResolvedProxy    // Proxy to route through (nil: proxy environment variables)
 }
 
 // Request represents an HTTP request to be made
@@ -117,14 +473,79 @@ type Request struct {
 	Environment string
 	Body        string
 	Headers     map[string]string
+
+	// BodyFile is streamed from disk instead of Body
+	BodyFile string
+
+	// Multipart sends a multipart/form-data body; files are streamed
+	Multipart []FormField
+
+	// Compress sends the body compressed with this Content-Encoding,
+	// gzip or zstd
+	Compress string
+}
+
+// FormField is one part of a multipart form. Either Value or File is set.
+type FormField struct {
+	Name        string
+	Value       string
+	File        string // path of a file to upload
+	Filename    string // defaults to the file's base name
+	ContentType string
 }
 
 // Response represents an HTTP response
 type Response struct {
 	StatusCode int
 	Status     string
+	Proto      string // negotiated protocol, e.g. "HTTP/2.0"
 	Headers    map[string][]string
 	Body       []byte
+
+	// Trailers are sent after the body, as gRPC does with its status
+	Trailers map[string][]string
+
+	// Size is the full body length. Streamed responses keep only the
+	// first bytes in Body, see Truncated.
+	Size      int64
+	Streamed  bool
+	Truncated bool
+
+	// Encoding lists the Content-Encoding codings the body was decoded
+	// from, and EncodedSize is its length on the wire before decoding
+	Encoding    string
+	EncodedSize int64
+
+	// Attempts lists every try made, including retries
+	Attempts []Attempt
+
+	// Redirects lists the redirects followed by the final attempt
+	Redirects []Redirect
+
+	// Timing breaks down the final attempt's latency
+	Timing *Timing
+}
+
+// Redirect is one followed redirect
+type Redirect struct {
+	StatusCode int
+	Method     string // method of the redirected request
+	URL        string // where the redirect led
+
+	// Stripped names the credential headers dropped because the redirect
+	// left the base URL's host
+	Stripped []string
+}
+
+// Timing is the latency breakdown of a request. Phases that did not happen,
+// such as DNS on a reused connection, are zero.
+type Timing struct {
+	DNS      time.Duration
+	Connect  time.Duration
+	TLS      time.Duration
+	TTFB     time.Duration // from getting a connection to the first response byte
+	Transfer time.Duration // reading the response body
+	Total    time.Duration
 }
 
 // PathMapping represents a parsed path configuration
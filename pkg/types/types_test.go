@@ -2,6 +2,7 @@ package types
 
 import (
 	"testing"
+	"time"
 )
 
 func TestProviderConfig_GetAddressForEnv(t *testing.T) {
@@ -182,3 +183,40 @@ func TestServiceConfig_IsAdvancedMode(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_IsProtectedEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		protected []string
+		env       string
+		expected  bool
+	}{
+		{"default prod", nil, "prod", true},
+		{"default production", nil, "Production", true},
+		{"default dev", nil, "dev", false},
+		{"configured list", []string{"live", "prod-eu"}, "prod-eu", true},
+		{"configured list replaces defaults", []string{"live"}, "prod", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{ProtectedEnvs: tt.protected}
+			if got := cfg.IsProtectedEnv(tt.env); got != tt.expected {
+				t.Errorf("IsProtectedEnv(%q) = %v, want %v", tt.env, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOAuthToken_Valid(t *testing.T) {
+	var nilToken *OAuthToken
+	if nilToken.Valid(0) {
+		t.Error("nil token should not be valid")
+	}
+	if (&OAuthToken{AccessToken: "a"}).Valid(time.Hour) != true {
+		t.Error("token without expiry should be valid")
+	}
+	if (&OAuthToken{AccessToken: "a", Expiry: time.Now().Add(time.Minute)}).Valid(2 * time.Minute) {
+		t.Error("token expiring within leeway should not be valid")
+	}
+}